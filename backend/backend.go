@@ -9,7 +9,10 @@ import (
 type Backend interface {
 	Started() bool
 	Version() string
+	// Obfuscation names the traffic obfuscation in use, empty when there is none.
+	Obfuscation() string
 	Logs() <-chan string
+	LogFiles() []string
 	Restart() error
 	Shutdown()
 	SyncUser(context.Context, *common.User) error
@@ -23,6 +26,36 @@ type Backend interface {
 	GetUserOnlineIpListStats(context.Context, string) (*common.StatsOnlineIpListResponse, error)
 }
 
+// IPBlocker is implemented by backends that can route a user's extra source
+// IPs away instead of disabling the user.
+type IPBlocker interface {
+	BlockUserIPs(context.Context, string, []string) error
+}
+
+// PeerManager is implemented by backends that give each user a peer with its
+// own address, client config and forwarding policy.
+type PeerManager interface {
+	GetPeerAllocations(context.Context) (*common.PeerAllocationsResponse, error)
+	GetClientConfig(context.Context, *common.ClientConfigRequest) (*common.ClientConfigResponse, error)
+	UpdatePolicy(context.Context, *common.PolicyRequest) error
+	// SubscribePeerEvents returns a channel of session events and a release
+	// function; the channel is closed on release or shutdown.
+	SubscribePeerEvents(context.Context) (<-chan *common.PeerEvent, func(), error)
+	GetPeerSessions(ctx context.Context, email string) (*common.PeerSessionsResponse, error)
+}
+
+// RoutingManager is implemented by backends whose outbounds and routing rules
+// can be changed at runtime.
+type RoutingManager interface {
+	AddOutbound(context.Context, *common.AddOutboundRequest) error
+	RemoveOutbound(ctx context.Context, tag string) error
+	ListOutbounds(context.Context) (*common.OutboundsResponse, error)
+	AddRoutingRule(context.Context, *common.AddRoutingRuleRequest) error
+	RemoveRoutingRule(ctx context.Context, tag string) error
+	ListRoutingRules(context.Context) (*common.RoutingRulesResponse, error)
+	OverrideBalancerTarget(ctx context.Context, balancerTag, target string) error
+}
+
 type ConfigKey struct{}
 
 type UsersKey struct{}
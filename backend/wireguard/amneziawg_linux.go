@@ -0,0 +1,54 @@
+package wireguard
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/amnezia-vpn/amneziawg-go/conn"
+	"github.com/amnezia-vpn/amneziawg-go/device"
+	"github.com/amnezia-vpn/amneziawg-go/tun"
+	"golang.zx2c4.com/wireguard/ipc"
+)
+
+// startAmneziaDevice runs amneziawg-go on a new TUN device with the given
+// obfuscation. The UAPI socket is opened with the wireguard-go ipc package so
+// it lands where wgctrl looks; amneziawg-go would use its own directory. The
+// device accepts the standard keys, so wgctrl manages peers as usual and
+// leaves the obfuscation parameters untouched.
+func startAmneziaDevice(interfaceName string, obfuscation *ObfuscationConfig) (io.Closer, error) {
+	tunDevice, err := tun.CreateTUN(interfaceName, device.DefaultMTU)
+	if err != nil {
+		return nil, fmt.Errorf("create tun: %w", err)
+	}
+
+	logger := device.NewLogger(device.LogLevelError, fmt.Sprintf("amneziawg-go(%s): ", interfaceName))
+	dev := device.NewDevice(tunDevice, conn.NewDefaultBind(), logger)
+	if err := dev.IpcSet(obfuscation.uapi()); err != nil {
+		dev.Close()
+		return nil, fmt.Errorf("set obfuscation: %w", err)
+	}
+
+	uapiFile, err := ipc.UAPIOpen(interfaceName)
+	if err != nil {
+		dev.Close()
+		return nil, fmt.Errorf("open uapi socket: %w", err)
+	}
+	uapi, err := ipc.UAPIListen(interfaceName, uapiFile)
+	uapiFile.Close()
+	if err != nil {
+		dev.Close()
+		return nil, fmt.Errorf("listen on uapi socket: %w", err)
+	}
+
+	go func() {
+		for {
+			conn, err := uapi.Accept()
+			if err != nil {
+				return
+			}
+			go dev.IpcHandle(conn)
+		}
+	}()
+
+	return &userspaceDevice{device: dev, uapi: uapi}, nil
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package wireguard
+
+import (
+	"errors"
+	"io"
+)
+
+func startAmneziaDevice(string, *ObfuscationConfig) (io.Closer, error) {
+	return nil, errors.New("amneziawg obfuscation is only supported on linux")
+}
@@ -0,0 +1,162 @@
+package wireguard
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pasarguard/node/common"
+)
+
+const (
+	clientPrivateKeyPlaceholder = "<client private key>"
+	clientQRScale               = 8
+)
+
+var defaultClientAllowedIPs = []string{"0.0.0.0/0", "::/0"}
+
+// GetClientConfig builds a wg-quick config for the peer of request.Email and,
+// when asked, renders it as a QR code.
+func (wg *WireGuard) GetClientConfig(_ context.Context, request *common.ClientConfigRequest) (*common.ClientConfigResponse, error) {
+	wg.mu.RLock()
+	state := wg.state
+	cfg := wg.config
+	wg.mu.RUnlock()
+
+	if state != lifecycleRunning {
+		return nil, errWireGuardNotStarted
+	}
+
+	if request.GetEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+	if request.GetInbound() != "" && request.GetInbound() != cfg.InterfaceName {
+		return nil, status.Errorf(codes.NotFound, "unknown wireguard inbound %s", request.GetInbound())
+	}
+	peer := wg.peerStore.GetByEmail(request.GetEmail())
+	if peer == nil {
+		return nil, status.Errorf(codes.NotFound, "no wireguard peer for user %s", request.GetEmail())
+	}
+
+	privateKey, err := cfg.GetPrivateKey()
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "invalid server private key: %v", err)
+	}
+	presharedKey := peer.PresharedKey
+	if presharedKey == nil {
+		presharedKey, _ = cfg.GetPreSharedKey()
+	}
+
+	endpoint, err := clientEndpoint(request.GetEndpoint(), cfg.ListenPort)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	clientKey := clientPrivateKeyPlaceholder
+	if request.GetPrivateKey() != "" {
+		key, err := wgtypes.ParseKey(request.GetPrivateKey())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid private key: %v", err)
+		}
+		if key.PublicKey() != peer.PublicKey {
+			return nil, status.Error(codes.InvalidArgument, "private key does not match the peer public key")
+		}
+		clientKey = key.String()
+	}
+
+	allowedIPs := request.GetAllowedIps()
+	if len(allowedIPs) == 0 {
+		allowedIPs = defaultClientAllowedIPs
+	}
+	for _, cidr := range allowedIPs {
+		if _, err := netip.ParsePrefix(cidr); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid allowed ip %q", cidr)
+		}
+	}
+	dnsServers := request.GetDns()
+	if len(dnsServers) == 0 {
+		dnsServers = cfg.DNS
+	}
+	for _, dns := range dnsServers {
+		if _, err := netip.ParseAddr(dns); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid dns server %q", dns)
+		}
+	}
+
+	addresses := make([]string, 0, len(peer.AllowedIPs))
+	for _, ipNet := range peer.AllowedIPs {
+		addresses = append(addresses, ipNet.String())
+	}
+
+	var b strings.Builder
+	b.WriteString("[Interface]\n")
+	fmt.Fprintf(&b, "PrivateKey = %s\n", clientKey)
+	fmt.Fprintf(&b, "Address = %s\n", strings.Join(addresses, ", "))
+	if len(dnsServers) > 0 {
+		fmt.Fprintf(&b, "DNS = %s\n", strings.Join(dnsServers, ", "))
+	}
+	if cfg.MTU > 0 {
+		fmt.Fprintf(&b, "MTU = %d\n", cfg.MTU)
+	}
+	for _, field := range cfg.Obfuscation.fields() {
+		fmt.Fprintf(&b, "%s = %s\n", field[0], field[1])
+	}
+	b.WriteString("\n[Peer]\n")
+	fmt.Fprintf(&b, "PublicKey = %s\n", privateKey.PublicKey().String())
+	if presharedKey != nil {
+		fmt.Fprintf(&b, "PresharedKey = %s\n", presharedKey.String())
+	}
+	fmt.Fprintf(&b, "Endpoint = %s\n", endpoint)
+	fmt.Fprintf(&b, "AllowedIPs = %s\n", strings.Join(allowedIPs, ", "))
+	if peer.PersistentKeepalive > 0 {
+		fmt.Fprintf(&b, "PersistentKeepalive = %d\n", int(peer.PersistentKeepalive.Seconds()))
+	}
+
+	response := &common.ClientConfigResponse{Config: b.String()}
+	if !request.GetQrPng() && !request.GetQrAscii() {
+		return response, nil
+	}
+
+	code, err := qrcode.New(response.Config, qrcode.Medium)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to encode qr code: %v", err)
+	}
+	if request.GetQrPng() {
+		// A negative size renders clientQRScale pixels per module.
+		if response.QrPng, err = code.PNG(-clientQRScale); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to render qr code: %v", err)
+		}
+	}
+	if request.GetQrAscii() {
+		response.QrAscii = code.ToSmallString(false)
+	}
+	return response, nil
+}
+
+// clientEndpoint adds the listen port to endpoint when it has none.
+func clientEndpoint(endpoint string, listenPort int) (string, error) {
+	endpoint = strings.TrimSpace(endpoint)
+	if endpoint == "" {
+		return "", fmt.Errorf("endpoint is required")
+	}
+
+	if host, port, err := net.SplitHostPort(endpoint); err == nil {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return "", fmt.Errorf("invalid endpoint port %q", port)
+		}
+		if host == "" {
+			return "", fmt.Errorf("endpoint host is required")
+		}
+		return endpoint, nil
+	}
+
+	return net.JoinHostPort(strings.Trim(endpoint, "[]"), strconv.Itoa(listenPort)), nil
+}
@@ -0,0 +1,206 @@
+package wireguard
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/draw"
+	"image/png"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pasarguard/node/common"
+)
+
+func newClientConfigTestBackend(t *testing.T) (*WireGuard, wgtypes.Key) {
+	t.Helper()
+
+	serverKey, _ := wgtypes.GeneratePrivateKey()
+	cfg, err := NewConfig(`{
+		"interface_name":"wg-test",
+		"private_key":"` + serverKey.String() + `",
+		"listen_port":51820,
+		"address":["10.75.0.1/24"]
+	}`)
+	if err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+
+	clientKey, _ := wgtypes.GeneratePrivateKey()
+	psk, _ := wgtypes.GenerateKey()
+	_, ipNet, _ := net.ParseCIDR("10.75.0.2/32")
+
+	wg := &WireGuard{config: cfg, peerStore: NewPeerStore(), state: lifecycleRunning}
+	wg.peerStore.Init([]*PeerInfo{{
+		Email:               "user@example.com",
+		PublicKey:           clientKey.PublicKey(),
+		AllowedIPs:          []net.IPNet{*ipNet},
+		PresharedKey:        &psk,
+		PersistentKeepalive: 25 * time.Second,
+	}})
+	return wg, clientKey
+}
+
+func TestGetClientConfigBuildsWGQuickConfig(t *testing.T) {
+	wg, clientKey := newClientConfigTestBackend(t)
+	serverKey, _ := wg.config.GetPrivateKey()
+
+	response, err := wg.GetClientConfig(context.Background(), &common.ClientConfigRequest{
+		Email:      "user@example.com",
+		Endpoint:   "vpn.example.com",
+		Dns:        []string{"1.1.1.1"},
+		AllowedIps: []string{"10.0.0.0/8"},
+		PrivateKey: clientKey.String(),
+	})
+	if err != nil {
+		t.Fatalf("GetClientConfig failed: %v", err)
+	}
+
+	for _, line := range []string{
+		"PrivateKey = " + clientKey.String(),
+		"Address = 10.75.0.2/32",
+		"DNS = 1.1.1.1",
+		"PublicKey = " + serverKey.PublicKey().String(),
+		"PresharedKey = ",
+		"Endpoint = vpn.example.com:51820",
+		"AllowedIPs = 10.0.0.0/8",
+		"PersistentKeepalive = 25",
+	} {
+		if !strings.Contains(response.GetConfig(), line) {
+			t.Fatalf("expected config to contain %q, got:\n%s", line, response.GetConfig())
+		}
+	}
+	if len(response.GetQrPng()) != 0 || response.GetQrAscii() != "" {
+		t.Fatal("expected no qr code unless requested")
+	}
+}
+
+func TestGetClientConfigDefaultsAndQRCode(t *testing.T) {
+	wg, _ := newClientConfigTestBackend(t)
+
+	response, err := wg.GetClientConfig(context.Background(), &common.ClientConfigRequest{
+		Email:    "user@example.com",
+		Endpoint: "[2001:db8::1]:443",
+		QrPng:    true,
+		QrAscii:  true,
+	})
+	if err != nil {
+		t.Fatalf("GetClientConfig failed: %v", err)
+	}
+
+	config := response.GetConfig()
+	if !strings.Contains(config, "PrivateKey = "+clientPrivateKeyPlaceholder) {
+		t.Fatalf("expected private key placeholder, got:\n%s", config)
+	}
+	if !strings.Contains(config, "Endpoint = [2001:db8::1]:443") || !strings.Contains(config, "AllowedIPs = 0.0.0.0/0, ::/0") {
+		t.Fatalf("unexpected endpoint or routes:\n%s", config)
+	}
+	pngImage, err := png.Decode(bytes.NewReader(response.GetQrPng()))
+	if err != nil {
+		t.Fatalf("expected a png qr code: %v", err)
+	}
+	if got := decodeQR(t, pngImage); got != config {
+		t.Fatalf("png qr code decodes to %q", got)
+	}
+	if got := decodeQR(t, asciiQRImage(response.GetQrAscii())); got != config {
+		t.Fatalf("ascii qr code decodes to %q", got)
+	}
+}
+
+func decodeQR(t *testing.T, img image.Image) string {
+	t.Helper()
+
+	bitmap, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		t.Fatalf("failed to read qr image: %v", err)
+	}
+	result, err := qrcode.NewQRCodeReader().Decode(bitmap, nil)
+	if err != nil {
+		t.Fatalf("failed to decode qr code: %v", err)
+	}
+	return result.GetText()
+}
+
+// asciiQRImage draws half-block text the way a terminal with a dark
+// background shows it: blocks are light foreground, spaces stay dark.
+func asciiQRImage(text string) image.Image {
+	const scale = 4
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	width := len([]rune(lines[0]))
+	img := image.NewGray(image.Rect(0, 0, width*scale, len(lines)*2*scale))
+	draw.Draw(img, img.Bounds(), image.Black, image.Point{}, draw.Src)
+
+	for row, line := range lines {
+		for col, cell := range []rune(line) {
+			var top, bottom bool
+			switch cell {
+			case '█':
+				top, bottom = true, true
+			case '▀':
+				top = true
+			case '▄':
+				bottom = true
+			}
+			for half, light := range []bool{top, bottom} {
+				if !light {
+					continue
+				}
+				rect := image.Rect(col*scale, (row*2+half)*scale, (col+1)*scale, (row*2+half+1)*scale)
+				draw.Draw(img, rect, image.White, image.Point{}, draw.Src)
+			}
+		}
+	}
+	return img
+}
+
+func TestGetClientConfigUsesConfiguredDNSAndMTU(t *testing.T) {
+	wg, _ := newClientConfigTestBackend(t)
+	wg.config.DNS = []string{"9.9.9.9"}
+	wg.config.MTU = 1380
+
+	response, err := wg.GetClientConfig(context.Background(), &common.ClientConfigRequest{Email: "user@example.com", Endpoint: "vpn.example.com"})
+	if err != nil {
+		t.Fatalf("GetClientConfig failed: %v", err)
+	}
+	for _, line := range []string{"DNS = 9.9.9.9\n", "MTU = 1380\n"} {
+		if !strings.Contains(response.GetConfig(), line) {
+			t.Fatalf("expected config to contain %q, got:\n%s", line, response.GetConfig())
+		}
+	}
+
+	response, err = wg.GetClientConfig(context.Background(), &common.ClientConfigRequest{Email: "user@example.com", Endpoint: "vpn.example.com", Dns: []string{"1.1.1.1"}})
+	if err != nil {
+		t.Fatalf("GetClientConfig failed: %v", err)
+	}
+	if !strings.Contains(response.GetConfig(), "DNS = 1.1.1.1\n") {
+		t.Fatalf("expected requested dns to win, got:\n%s", response.GetConfig())
+	}
+}
+
+func TestGetClientConfigRejectsBadRequests(t *testing.T) {
+	wg, _ := newClientConfigTestBackend(t)
+	otherKey, _ := wgtypes.GeneratePrivateKey()
+
+	for name, tc := range map[string]struct {
+		request *common.ClientConfigRequest
+		code    codes.Code
+	}{
+		"unknown user":     {&common.ClientConfigRequest{Email: "nobody@example.com", Endpoint: "vpn.example.com"}, codes.NotFound},
+		"missing endpoint": {&common.ClientConfigRequest{Email: "user@example.com"}, codes.InvalidArgument},
+		"foreign key":      {&common.ClientConfigRequest{Email: "user@example.com", Endpoint: "vpn.example.com", PrivateKey: otherKey.String()}, codes.InvalidArgument},
+		"bad dns":          {&common.ClientConfigRequest{Email: "user@example.com", Endpoint: "vpn.example.com", Dns: []string{"dns.example.com"}}, codes.InvalidArgument},
+	} {
+		_, err := wg.GetClientConfig(context.Background(), tc.request)
+		if status.Code(err) != tc.code {
+			t.Fatalf("%s: expected %s, got %v", name, tc.code, err)
+		}
+	}
+}
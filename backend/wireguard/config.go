@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net"
+	"net/netip"
 	"strings"
 	"sync"
+	"time"
 
 	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
 )
@@ -19,6 +22,27 @@ type Config struct {
 	ListenPort    int            `json:"listen_port"`
 	Address       []string       `json:"address"`
 	Latency       *LatencyConfig `json:"latency,omitempty"`
+	// IPAM lets the node allocate peer_ips for users the panel sends without them.
+	IPAM bool `json:"ipam,omitempty"`
+	// Mode is "kernel", "userspace" (wireguard-go) or "auto", the default.
+	Mode string `json:"mode,omitempty"`
+	// Policy restricts forwarding from peers; it can be replaced at runtime.
+	Policy *PolicyConfig `json:"policy,omitempty"`
+	// MTU of the interface; 0 keeps the default of 1420.
+	MTU int `json:"mtu,omitempty"`
+	// FwMark marks the encrypted packets the interface sends.
+	FwMark uint32 `json:"fwmark,omitempty"`
+	// Table routes packets marked with FwMark through this routing table, so
+	// WireGuard traffic can leave through another uplink. It requires FwMark.
+	Table int `json:"table,omitempty"`
+	// DNS servers written into client configs that do not request their own.
+	DNS []string `json:"dns,omitempty"`
+	// XrayTProxy sends forwarded peer traffic through a co-located Xray.
+	XrayTProxy *XrayTProxyConfig `json:"xray_tproxy,omitempty"`
+	// Obfuscation turns the interface into an AmneziaWG one; it needs userspace mode.
+	Obfuscation *ObfuscationConfig `json:"obfuscation,omitempty"`
+	// Outbounds are the routing tables users can be sent through with their outbound_tag.
+	Outbounds map[string]*PeerOutboundConfig `json:"outbounds,omitempty"`
 
 	privateKeyValue   wgtypes.Key
 	privateKeySet     bool
@@ -38,6 +62,15 @@ type PeerInfo struct {
 	Email      string      `json:"email"`
 	PublicKey  wgtypes.Key `json:"public_key"`
 	AllowedIPs []net.IPNet `json:"allowed_ips"`
+	// UploadRate and DownloadRate are bytes per second, 0 means unlimited.
+	UploadRate   uint64 `json:"upload_rate,omitempty"`
+	DownloadRate uint64 `json:"download_rate,omitempty"`
+	// OutboundTag names the entry of Config.Outbounds the peer egresses through.
+	OutboundTag string `json:"outbound_tag,omitempty"`
+	// PresharedKey overrides the interface key when set.
+	PresharedKey *wgtypes.Key `json:"preshared_key,omitempty"`
+	// PersistentKeepalive overrides the interface default when non-zero.
+	PersistentKeepalive time.Duration `json:"persistent_keepalive,omitempty"`
 }
 
 func clonePeerInfo(peer *PeerInfo) *PeerInfo {
@@ -45,11 +78,20 @@ func clonePeerInfo(peer *PeerInfo) *PeerInfo {
 		return nil
 	}
 
-	return &PeerInfo{
-		Email:      peer.Email,
-		PublicKey:  peer.PublicKey,
-		AllowedIPs: append([]net.IPNet(nil), peer.AllowedIPs...),
+	clone := &PeerInfo{
+		Email:               peer.Email,
+		PublicKey:           peer.PublicKey,
+		AllowedIPs:          append([]net.IPNet(nil), peer.AllowedIPs...),
+		UploadRate:          peer.UploadRate,
+		DownloadRate:        peer.DownloadRate,
+		OutboundTag:         peer.OutboundTag,
+		PersistentKeepalive: peer.PersistentKeepalive,
 	}
+	if peer.PresharedKey != nil {
+		psk := *peer.PresharedKey
+		clone.PresharedKey = &psk
+	}
+	return clone
 }
 
 // NewConfig creates a new WireGuard configuration from JSON
@@ -68,6 +110,31 @@ func NewConfig(config string) (*Config, error) {
 	if wgConfig.ListenPort <= 0 {
 		wgConfig.ListenPort = 51820
 	}
+	switch wgConfig.Mode {
+	case "":
+		wgConfig.Mode = ModeAuto
+	case ModeAuto, ModeKernel, ModeUserspace:
+	default:
+		return nil, fmt.Errorf("invalid wireguard mode %q", wgConfig.Mode)
+	}
+	if err := wgConfig.validateRouting(); err != nil {
+		return nil, err
+	}
+	if err := wgConfig.XrayTProxy.validate(&wgConfig); err != nil {
+		return nil, fmt.Errorf("invalid xray_tproxy: %w", err)
+	}
+	if err := wgConfig.validateOutbounds(); err != nil {
+		return nil, fmt.Errorf("invalid outbounds: %w", err)
+	}
+	if err := wgConfig.Obfuscation.validate(); err != nil {
+		return nil, fmt.Errorf("invalid obfuscation: %w", err)
+	}
+	if wgConfig.Obfuscation.enabled() && wgConfig.Mode == ModeKernel {
+		return nil, errors.New("obfuscation is not supported by the kernel module, use userspace or auto mode")
+	}
+	if err := wgConfig.Policy.validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy: %w", err)
+	}
 	if wgConfig.Latency == nil {
 		wgConfig.Latency = &LatencyConfig{}
 	}
@@ -81,6 +148,75 @@ func NewConfig(config string) (*Config, error) {
 	return &wgConfig, nil
 }
 
+// validateRouting checks the MTU, fwmark, table and DNS options.
+func (c *Config) validateRouting() error {
+	minMTU := 576
+	for _, address := range c.Address {
+		if prefix, err := netip.ParsePrefix(strings.TrimSpace(address)); err == nil && prefix.Addr().Is6() {
+			minMTU = 1280
+		}
+	}
+	if c.MTU != 0 && (c.MTU < minMTU || c.MTU > 65535) {
+		return fmt.Errorf("mtu must be between %d and 65535", minMTU)
+	}
+	if c.FwMark > math.MaxInt32 {
+		return fmt.Errorf("fwmark must not exceed %d", math.MaxInt32)
+	}
+	if c.Table != 0 {
+		if c.FwMark == 0 {
+			return errors.New("table requires fwmark")
+		}
+		// 253 to 255 are the default, main and local tables.
+		if c.Table < 0 || (c.Table >= 253 && c.Table <= 255) {
+			return fmt.Errorf("invalid routing table %d", c.Table)
+		}
+	}
+	for _, dns := range c.DNS {
+		if _, err := netip.ParseAddr(dns); err != nil {
+			return fmt.Errorf("invalid dns server %q", dns)
+		}
+	}
+	return nil
+}
+
+// NewConfigs parses a backend config holding either one interface or an
+// "interfaces" list, each entry having the same fields as a single interface.
+func NewConfigs(config string) ([]*Config, error) {
+	var group struct {
+		Interfaces []json.RawMessage `json:"interfaces"`
+	}
+	if err := json.Unmarshal([]byte(config), &group); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	if len(group.Interfaces) == 0 {
+		wgConfig, err := NewConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		return []*Config{wgConfig}, nil
+	}
+
+	configs := make([]*Config, 0, len(group.Interfaces))
+	names := make(map[string]struct{}, len(group.Interfaces))
+	ports := make(map[int]string, len(group.Interfaces))
+	for i, raw := range group.Interfaces {
+		wgConfig, err := NewConfig(string(raw))
+		if err != nil {
+			return nil, fmt.Errorf("interface %d: %w", i, err)
+		}
+		if _, ok := names[wgConfig.InterfaceName]; ok {
+			return nil, fmt.Errorf("duplicate interface_name %q", wgConfig.InterfaceName)
+		}
+		if other, ok := ports[wgConfig.ListenPort]; ok {
+			return nil, fmt.Errorf("interfaces %q and %q share listen_port %d", other, wgConfig.InterfaceName, wgConfig.ListenPort)
+		}
+		names[wgConfig.InterfaceName] = struct{}{}
+		ports[wgConfig.ListenPort] = wgConfig.InterfaceName
+		configs = append(configs, wgConfig)
+	}
+	return configs, nil
+}
+
 // InterfaceNetworks returns CIDR prefixes parsed from the node's core `address` list.
 // Used to restrict peer AllowedIPs to subnets this interface actually serves.
 func (c *Config) InterfaceNetworks() []*net.IPNet {
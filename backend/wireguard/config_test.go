@@ -75,6 +75,91 @@ func TestNewWireGuardConfigDefaults(t *testing.T) {
 	if config.Latency.TimeoutSeconds != 5 {
 		t.Errorf("expected default latency.timeout_seconds 5, got: %d", config.Latency.TimeoutSeconds)
 	}
+	if config.Mode != ModeAuto {
+		t.Errorf("expected default mode %q, got: %s", ModeAuto, config.Mode)
+	}
+}
+
+func TestNewConfigsAcceptsSingleInterfaceAndList(t *testing.T) {
+	single, err := NewConfigs(`{"interface_name":"wg1","listen_port":51821}`)
+	if err != nil {
+		t.Fatalf("NewConfigs failed: %v", err)
+	}
+	if len(single) != 1 || single[0].InterfaceName != "wg1" {
+		t.Fatalf("expected the single interface config, got %+v", single)
+	}
+
+	list, err := NewConfigs(`{"interfaces":[
+		{"interface_name":"wg0","listen_port":51820,"latency":{"timeout_seconds":3}},
+		{"interface_name":"wg1","listen_port":51821}
+	]}`)
+	if err != nil {
+		t.Fatalf("NewConfigs failed: %v", err)
+	}
+	if len(list) != 2 || list[0].Latency.TimeoutSeconds != 3 || list[1].Latency.TimeoutSeconds != 5 {
+		t.Fatalf("expected per-interface settings with defaults, got %+v", list)
+	}
+}
+
+func TestNewConfigsRejectsSharedNameOrPort(t *testing.T) {
+	for _, raw := range []string{
+		`{"interfaces":[{"interface_name":"wg0","listen_port":51820},{"interface_name":"wg0","listen_port":51821}]}`,
+		`{"interfaces":[{"interface_name":"wg0","listen_port":51820},{"interface_name":"wg1","listen_port":51820}]}`,
+	} {
+		if _, err := NewConfigs(raw); err == nil {
+			t.Fatalf("expected error for %s", raw)
+		}
+	}
+}
+
+func TestNewWireGuardConfigInvalidMode(t *testing.T) {
+	if _, err := NewConfig(`{"mode":"netstack"}`); err == nil {
+		t.Fatal("Expected error for unknown mode")
+	}
+}
+
+func TestNewWireGuardConfigRoutingOptions(t *testing.T) {
+	config, err := NewConfig(`{"address":["10.0.0.1/24"],"mtu":1380,"fwmark":51820,"table":200,"dns":["1.1.1.1","2606:4700:4700::1111"]}`)
+	if err != nil {
+		t.Fatalf("NewConfig failed: %v", err)
+	}
+	if config.MTU != 1380 || config.FwMark != 51820 || config.Table != 200 || len(config.DNS) != 2 {
+		t.Fatalf("unexpected routing options: %+v", config)
+	}
+
+	for name, configJSON := range map[string]string{
+		"mtu too small":       `{"mtu":500}`,
+		"mtu too small on v6": `{"address":["fd00::1/64"],"mtu":1200}`,
+		"table without mark":  `{"table":200}`,
+		"main table":          `{"fwmark":1,"table":254}`,
+		"invalid dns":         `{"dns":["one.one.one.one"]}`,
+	} {
+		if _, err := NewConfig(configJSON); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestNewWireGuardConfigOutbounds(t *testing.T) {
+	config, err := NewConfig(`{"outbounds":{"residential":{"table":100},"backup":{"table":101,"mark":2001}}}`)
+	if err != nil {
+		t.Fatalf("NewConfig failed: %v", err)
+	}
+	if config.Outbounds["residential"].Mark != 100 || config.Outbounds["backup"].Mark != 2001 {
+		t.Fatalf("unexpected outbound marks: %+v %+v", config.Outbounds["residential"], config.Outbounds["backup"])
+	}
+
+	for name, configJSON := range map[string]string{
+		"missing table":   `{"outbounds":{"a":{}}}`,
+		"main table":      `{"outbounds":{"a":{"table":254}}}`,
+		"shared mark":     `{"outbounds":{"a":{"table":100},"b":{"table":101,"mark":100}}}`,
+		"interface mark":  `{"fwmark":100,"table":200,"outbounds":{"a":{"table":100}}}`,
+		"with xray proxy": `{"xray_tproxy":{"port":12345},"outbounds":{"a":{"table":100}}}`,
+	} {
+		if _, err := NewConfig(configJSON); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
 }
 
 func TestNewWireGuardConfigLatencyNested(t *testing.T) {
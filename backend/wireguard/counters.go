@@ -0,0 +1,38 @@
+package wireguard
+
+import "log"
+
+// egressTraffic is the forwarded traffic between the interface and one egress
+// interface: up leaves through the egress, down comes back from it.
+type egressTraffic struct {
+	up   int64
+	down int64
+}
+
+// trafficCounters counts forwarded traffic per egress interface, which
+// GetStats reports as the outbounds of the interface.
+type trafficCounters interface {
+	// Install resolves the egress interfaces and adds a counter for each.
+	Install(addresses []string) error
+	// Read returns the byte counts since Install by egress interface.
+	Read() (map[string]egressTraffic, error)
+	// Close removes every counter this instance installed.
+	Close() error
+}
+
+// syncTrafficCounters installs the egress counters at startup. Without them
+// outbound stats stay empty, so a failure is logged instead of failing the start.
+func (wg *WireGuard) syncTrafficCounters() {
+	wg.mu.RLock()
+	counters := wg.counters
+	addresses := wg.config.Address
+	wg.mu.RUnlock()
+
+	if counters == nil {
+		return
+	}
+	if err := counters.Install(addresses); err != nil {
+		log.Printf("wireguard traffic counters: %v", err)
+		wg.emitErrorLogf("failed to install outbound traffic counters: %v", err)
+	}
+}
@@ -0,0 +1,201 @@
+//go:build linux
+
+package wireguard
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+const (
+	nftCountersTableFamily = "inet"
+	nftCountersTableName   = "pg_node_wg_stats"
+	nftCountersChain       = "forward"
+)
+
+// nftTrafficCounters keeps one counter rule per egress interface and
+// direction in its own forward base chain. The chain runs after the policy,
+// shaping and host forward chains, so dropped packets are not counted.
+type nftTrafficCounters struct {
+	mu      sync.Mutex
+	iface   string
+	ownerID string
+	closed  bool
+}
+
+func newTrafficCounters(iface string) trafficCounters {
+	return &nftTrafficCounters{iface: iface}
+}
+
+func (c *nftTrafficCounters) chain() nftBaseChain {
+	return nftBaseChain{family: nftCountersTableFamily, table: nftCountersTableName, name: nftCountersChain}
+}
+
+func (c *nftTrafficCounters) Install(addresses []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed || c.ownerID != "" {
+		return nil
+	}
+	if err := requireNFTFirewall("outbound stats"); err != nil {
+		return err
+	}
+	// Without any firewall tool there is nothing to count with; host routing already failed.
+	if _, err := exec.LookPath("nft"); err != nil {
+		log.Printf("wireguard traffic counters: nft not found, outbound stats are unavailable")
+		return nil
+	}
+
+	egress, ok := resolveIPv4EgressInterface()
+	if !ok {
+		log.Printf(
+			"wireguard traffic counters: could not detect default IPv4 egress interface; counting %q (set %s)",
+			egress,
+			envNATOutputInterface,
+		)
+	}
+	egresses := []string{egress}
+	if hasIPv6Address(addresses) {
+		if egress6 := resolveIPv6EgressInterface(egress); egress6 != egress {
+			egresses = append(egresses, egress6)
+		}
+	}
+
+	if err := ensureNFTCountersChain(); err != nil {
+		return err
+	}
+	c.ownerID = newHostRoutingOwnerID(c.iface)
+
+	chain := c.chain()
+	var script strings.Builder
+	for _, egress := range egresses {
+		for _, outbound := range []bool{true, false} {
+			fmt.Fprintf(&script, "add rule %s %s %s %s\n", chain.family, chain.table, chain.name, nftCounterRule(c.ownerID, c.iface, egress, outbound))
+		}
+	}
+	return runNFTScript(script.String())
+}
+
+func (c *nftTrafficCounters) Read() (map[string]egressTraffic, error) {
+	c.mu.Lock()
+	ownerID := c.ownerID
+	c.mu.Unlock()
+
+	if ownerID == "" {
+		return map[string]egressTraffic{}, nil
+	}
+
+	chain := c.chain()
+	out, err := exec.Command("nft", "-j", "list", "chain", chain.family, chain.table, chain.name).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("nft -j list chain %s %s %s: %w: %s", chain.family, chain.table, chain.name, err, strings.TrimSpace(string(out)))
+	}
+	return parseNFTCounters(out, ownerID)
+}
+
+func (c *nftTrafficCounters) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	if c.ownerID == "" {
+		return nil
+	}
+	return removeNFTRulesWithCommentPrefix(c.chain(), nftOwnerCommentPrefix(c.ownerID))
+}
+
+func ensureNFTCountersChain() error {
+	if err := runNFT("add", "table", nftCountersTableFamily, nftCountersTableName); err != nil && !nftAlreadyExists(err) {
+		return err
+	}
+	if err := runNFT(
+		"add", "chain", nftCountersTableFamily, nftCountersTableName, nftCountersChain,
+		"{", "type", "filter", "hook", "forward", "priority", "10", ";", "policy", "accept", ";", "}",
+	); err != nil && !nftAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+func nftCounterRule(ownerID, wgIface, egress string, outbound bool) string {
+	in, out := egress, wgIface
+	if outbound {
+		in, out = wgIface, egress
+	}
+	return fmt.Sprintf("iifname %q oifname %q counter comment %s", in, out, nftString(nftCounterComment(ownerID, wgIface, egress, outbound)))
+}
+
+func nftCounterComment(ownerID, wgIface, egress string, outbound bool) string {
+	direction := "return"
+	if outbound {
+		direction = "outbound"
+	}
+	return fmt.Sprintf("%sowner=%s type=counter iface=%s out=%s direction=%s", nftRuleCommentPrefix, ownerID, wgIface, egress, direction)
+}
+
+type nftListCounterRule struct {
+	Comment string                       `json:"comment"`
+	Expr    []map[string]json.RawMessage `json:"expr"`
+}
+
+// parseNFTCounters sums the counters of ownerID by egress interface, reading
+// the egress and direction back from the rule comments.
+func parseNFTCounters(data []byte, ownerID string) (map[string]egressTraffic, error) {
+	var ruleset nftListRuleset
+	if err := json.Unmarshal(data, &ruleset); err != nil {
+		return nil, fmt.Errorf("parse nft chain: %w", err)
+	}
+
+	prefix := nftOwnerCommentPrefix(ownerID)
+	traffic := make(map[string]egressTraffic)
+	for _, item := range ruleset.NFTables {
+		raw, ok := item["rule"]
+		if !ok {
+			continue
+		}
+		var rule nftListCounterRule
+		if err := json.Unmarshal(raw, &rule); err != nil {
+			return nil, fmt.Errorf("parse nft rule: %w", err)
+		}
+		if !strings.HasPrefix(rule.Comment, prefix) {
+			continue
+		}
+
+		var egress, direction string
+		for _, field := range strings.Fields(strings.TrimPrefix(rule.Comment, prefix)) {
+			key, value, _ := strings.Cut(field, "=")
+			switch key {
+			case "out":
+				egress = value
+			case "direction":
+				direction = value
+			}
+		}
+
+		for _, expr := range rule.Expr {
+			raw, ok := expr["counter"]
+			if !ok {
+				continue
+			}
+			var counter struct {
+				Bytes int64 `json:"bytes"`
+			}
+			if err := json.Unmarshal(raw, &counter); err != nil {
+				return nil, fmt.Errorf("parse nft counter: %w", err)
+			}
+			current := traffic[egress]
+			if direction == "outbound" {
+				current.up += counter.Bytes
+			} else {
+				current.down += counter.Bytes
+			}
+			traffic[egress] = current
+		}
+	}
+	return traffic, nil
+}
@@ -0,0 +1,43 @@
+//go:build linux
+
+package wireguard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNFTCounterRule(t *testing.T) {
+	got := nftCounterRule("wg0_1_ab", "wg0", "eth0", true)
+	want := `iifname "wg0" oifname "eth0" counter comment "pg_node_wg owner=wg0_1_ab type=counter iface=wg0 out=eth0 direction=outbound"`
+	if got != want {
+		t.Fatalf("unexpected rule:\n%s\nwant:\n%s", got, want)
+	}
+	if got := nftCounterRule("wg0_1_ab", "wg0", "eth0", false); !strings.HasPrefix(got, `iifname "eth0" oifname "wg0" counter`) {
+		t.Fatalf("unexpected return rule: %s", got)
+	}
+}
+
+func TestParseNFTCounters(t *testing.T) {
+	data := []byte(`{"nftables":[
+		{"metainfo":{"json_schema_version":1}},
+		{"chain":{"family":"inet","table":"pg_node_wg_stats","name":"forward","handle":1}},
+		{"rule":{"family":"inet","table":"pg_node_wg_stats","chain":"forward","handle":2,
+			"comment":"pg_node_wg owner=wg0_1_ab type=counter iface=wg0 out=eth0 direction=outbound",
+			"expr":[{"match":{"op":"==","left":{"meta":{"key":"iifname"}},"right":"wg0"}},{"counter":{"packets":3,"bytes":300}}]}},
+		{"rule":{"family":"inet","table":"pg_node_wg_stats","chain":"forward","handle":3,
+			"comment":"pg_node_wg owner=wg0_1_ab type=counter iface=wg0 out=eth0 direction=return",
+			"expr":[{"counter":{"packets":5,"bytes":5000}}]}},
+		{"rule":{"family":"inet","table":"pg_node_wg_stats","chain":"forward","handle":4,
+			"comment":"pg_node_wg owner=wg1_1_cd type=counter iface=wg1 out=eth0 direction=outbound",
+			"expr":[{"counter":{"packets":1,"bytes":77}}]}}
+	]}`)
+
+	traffic, err := parseNFTCounters(data, "wg0_1_ab")
+	if err != nil {
+		t.Fatalf("parseNFTCounters failed: %v", err)
+	}
+	if len(traffic) != 1 || traffic["eth0"] != (egressTraffic{up: 300, down: 5000}) {
+		t.Fatalf("unexpected traffic: %+v", traffic)
+	}
+}
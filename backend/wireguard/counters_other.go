@@ -0,0 +1,16 @@
+//go:build !linux
+
+package wireguard
+
+// noopTrafficCounters reports no outbounds on platforms without nftables.
+type noopTrafficCounters struct{}
+
+func newTrafficCounters(_ string) trafficCounters { return noopTrafficCounters{} }
+
+func (noopTrafficCounters) Install(_ []string) error { return nil }
+
+func (noopTrafficCounters) Read() (map[string]egressTraffic, error) {
+	return map[string]egressTraffic{}, nil
+}
+
+func (noopTrafficCounters) Close() error { return nil }
@@ -13,15 +13,8 @@ import (
 // linuxDefaultRouteInterfaceIPv4 returns the egress interface for the IPv4 default route
 // (e.g. ens192, eth0, enp0s3). Used when PG_NODE_WG_NAT_OUTPUT_INTERFACE is unset.
 func linuxDefaultRouteInterfaceIPv4() (string, bool) {
-	if out, err := exec.Command("ip", "-4", "-j", "route", "show", "default").Output(); err == nil {
-		var routes []struct {
-			Dev string `json:"dev"`
-		}
-		if err := json.Unmarshal(bytes.TrimSpace(out), &routes); err == nil && len(routes) > 0 {
-			if dev := strings.TrimSpace(routes[0].Dev); dev != "" {
-				return dev, true
-			}
-		}
+	if dev, ok := ipDefaultRouteDev("-4"); ok {
+		return dev, true
 	}
 	if out, err := os.ReadFile("/proc/net/route"); err == nil {
 		return parseDefaultIfaceFromProcNetRoute(out)
@@ -29,6 +22,37 @@ func linuxDefaultRouteInterfaceIPv4() (string, bool) {
 	return "", false
 }
 
+// linuxDefaultRouteInterfaceIPv6 returns the egress interface for the IPv6 default route.
+// Used when PG_NODE_WG_NAT_OUTPUT_INTERFACE_IPV6 is unset.
+func linuxDefaultRouteInterfaceIPv6() (string, bool) {
+	if dev, ok := ipDefaultRouteDev("-6"); ok {
+		return dev, true
+	}
+	if out, err := os.ReadFile("/proc/net/ipv6_route"); err == nil {
+		return parseDefaultIfaceFromProcIPv6Route(out)
+	}
+	return "", false
+}
+
+func ipDefaultRouteDev(familyFlag string) (string, bool) {
+	out, err := exec.Command("ip", familyFlag, "-j", "route", "show", "default").Output()
+	if err != nil {
+		return "", false
+	}
+	var routes []struct {
+		Dev string `json:"dev"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(out), &routes); err != nil {
+		return "", false
+	}
+	for _, route := range routes {
+		if dev := strings.TrimSpace(route.Dev); dev != "" && dev != "lo" {
+			return dev, true
+		}
+	}
+	return "", false
+}
+
 // parseDefaultIfaceFromProcNetRoute parses /proc/net/route (kernel ABI).
 // Default route rows use destination 00000000.
 func parseDefaultIfaceFromProcNetRoute(data []byte) (string, bool) {
@@ -47,3 +71,21 @@ func parseDefaultIfaceFromProcNetRoute(data []byte) (string, bool) {
 	}
 	return "", false
 }
+
+// parseDefaultIfaceFromProcIPv6Route parses /proc/net/ipv6_route (kernel ABI).
+// Default route rows have an all-zero destination with prefix length 00; the
+// unreachable default the kernel keeps on lo is skipped.
+func parseDefaultIfaceFromProcIPv6Route(data []byte) (string, bool) {
+	const zeroDestination = "00000000000000000000000000000000"
+	for _, line := range bytes.Split(data, []byte{'\n'}) {
+		fields := strings.Fields(string(line))
+		if len(fields) < 10 {
+			continue
+		}
+		iface := fields[9]
+		if fields[0] == zeroDestination && fields[1] == "00" && iface != "lo" {
+			return iface, true
+		}
+	}
+	return "", false
+}
@@ -13,3 +13,14 @@ func TestParseDefaultIfaceFromProcNetRoute(t *testing.T) {
 		t.Fatalf("got %q ok=%v", got, ok)
 	}
 }
+
+func TestParseDefaultIfaceFromProcIPv6Route(t *testing.T) {
+	const sample = "20010db8000000000000000000000000 40 00000000000000000000000000000000 00 00000000000000000000000000000000 00000100 00000001 00000000 00000001     ens33\n" +
+		"00000000000000000000000000000000 00 00000000000000000000000000000000 00 00000000000000000000000000000000 ffffffff 00000001 00000000 00200200       lo\n" +
+		"00000000000000000000000000000000 00 00000000000000000000000000000000 00 fe800000000000000000000000000001 00000400 00000001 00000000 00000003     ens33\n"
+
+	got, ok := parseDefaultIfaceFromProcIPv6Route([]byte(sample))
+	if !ok || got != "ens33" {
+		t.Fatalf("got %q ok=%v", got, ok)
+	}
+}
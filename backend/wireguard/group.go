@@ -0,0 +1,407 @@
+package wireguard
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pasarguard/node/common"
+	"github.com/pasarguard/node/config"
+)
+
+// Group runs one WireGuard instance per configured interface and serves them
+// as a single backend. Each instance only takes the users whose inbounds name
+// its interface, so user syncs are sent to all of them unchanged.
+type Group struct {
+	members []*WireGuard
+	logChan chan string
+}
+
+// NewGroup starts every interface of configs. If one fails, the ones already
+// started are shut down.
+func NewGroup(cfg *config.Config, configs []*Config, users []*common.User) (*Group, error) {
+	return newGroupWithFactory(configs, func(wgConfig *Config) (*WireGuard, error) {
+		return New(cfg, wgConfig, users)
+	})
+}
+
+func newGroupWithFactory(configs []*Config, start func(*Config) (*WireGuard, error)) (*Group, error) {
+	if len(configs) == 0 {
+		return nil, errors.New("wireguard config has no interfaces")
+	}
+
+	g := &Group{}
+	for _, wgConfig := range configs {
+		member, err := start(wgConfig)
+		if err != nil {
+			g.shutdownMembers()
+			return nil, fmt.Errorf("interface %s: %w", wgConfig.InterfaceName, err)
+		}
+		g.members = append(g.members, member)
+	}
+
+	g.logChan = make(chan string, cap(g.members[0].Logs()))
+	var wg sync.WaitGroup
+	for _, member := range g.members {
+		wg.Add(1)
+		go func(logs <-chan string) {
+			defer wg.Done()
+			for line := range logs {
+				// Drop lines nobody reads, like a single interface does.
+				select {
+				case g.logChan <- line:
+				default:
+				}
+			}
+		}(member.Logs())
+	}
+	go func() {
+		wg.Wait()
+		close(g.logChan)
+	}()
+
+	return g, nil
+}
+
+func (g *Group) shutdownMembers() {
+	for _, member := range g.members {
+		member.Shutdown()
+	}
+}
+
+// member returns the instance serving interfaceName.
+func (g *Group) member(interfaceName string) *WireGuard {
+	for _, member := range g.members {
+		if member.config.InterfaceName == interfaceName {
+			return member
+		}
+	}
+	return nil
+}
+
+// each runs fn on every interface and joins the errors, tagged by interface.
+func (g *Group) each(fn func(*WireGuard) error) error {
+	var errs []error
+	for _, member := range g.members {
+		if err := fn(member); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", member.config.InterfaceName, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (g *Group) Started() bool {
+	for _, member := range g.members {
+		if !member.Started() {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *Group) Version() string {
+	return g.members[0].Version()
+}
+
+// Obfuscation reports the obfuscation of the first interface that has one.
+func (g *Group) Obfuscation() string {
+	for _, member := range g.members {
+		if obfuscation := member.Obfuscation(); obfuscation != "" {
+			return obfuscation
+		}
+	}
+	return ""
+}
+
+// Logs merges the logs of every interface. The channel is closed once all of
+// them are shut down.
+func (g *Group) Logs() <-chan string {
+	return g.logChan
+}
+
+func (g *Group) LogFiles() []string {
+	return nil
+}
+
+func (g *Group) Restart() error {
+	return g.each(func(member *WireGuard) error { return member.Restart() })
+}
+
+func (g *Group) Shutdown() {
+	g.shutdownMembers()
+}
+
+func (g *Group) SyncUser(ctx context.Context, user *common.User) error {
+	return g.each(func(member *WireGuard) error { return member.SyncUser(ctx, user) })
+}
+
+func (g *Group) SyncUsers(ctx context.Context, users []*common.User) error {
+	return g.each(func(member *WireGuard) error { return member.SyncUsers(ctx, users) })
+}
+
+func (g *Group) UpdateUsers(ctx context.Context, users []*common.User) error {
+	return g.each(func(member *WireGuard) error { return member.UpdateUsers(ctx, users) })
+}
+
+func (g *Group) UpdateUsersAndRestart(ctx context.Context, users []*common.User) error {
+	return g.each(func(member *WireGuard) error { return member.UpdateUsersAndRestart(ctx, users) })
+}
+
+// GetSysStats checks that every interface is up; the runtime stats are process wide.
+func (g *Group) GetSysStats(ctx context.Context) (*common.BackendStatsResponse, error) {
+	var response *common.BackendStatsResponse
+	err := g.each(func(member *WireGuard) error {
+		stats, err := member.GetSysStats(ctx)
+		if response == nil {
+			response = stats
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// GetStats reports each interface as an inbound and sums user and outbound
+// traffic across interfaces, since users and egress interfaces are shared.
+func (g *Group) GetStats(ctx context.Context, request *common.StatRequest) (*common.StatResponse, error) {
+	if request.GetType() == common.StatType_Inbound && request.GetName() != "" {
+		member := g.member(request.GetName())
+		if member == nil {
+			return &common.StatResponse{Stats: []*common.Stat{}}, nil
+		}
+		return member.GetStats(ctx, request)
+	}
+
+	var collected []*common.Stat
+	err := g.each(func(member *WireGuard) error {
+		response, err := member.GetStats(ctx, request)
+		if err != nil {
+			return err
+		}
+		collected = append(collected, response.GetStats()...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &common.StatResponse{Stats: mergeStats(collected)}, nil
+}
+
+// mergeStats adds up stats with the same name, type and link, keeping the
+// order in which they first appear.
+func mergeStats(collected []*common.Stat) []*common.Stat {
+	type statKey struct{ name, kind, link string }
+	merged := make([]*common.Stat, 0, len(collected))
+	index := make(map[statKey]*common.Stat, len(collected))
+	for _, stat := range collected {
+		key := statKey{stat.GetName(), stat.GetType(), stat.GetLink()}
+		if existing, ok := index[key]; ok {
+			existing.Value += stat.GetValue()
+			continue
+		}
+		copied := &common.Stat{Name: stat.GetName(), Type: stat.GetType(), Link: stat.GetLink(), Value: stat.GetValue()}
+		index[key] = copied
+		merged = append(merged, copied)
+	}
+	return merged
+}
+
+func (g *Group) GetOutboundsLatency(ctx context.Context, request *common.LatencyRequest) (*common.LatencyResponse, error) {
+	response := &common.LatencyResponse{Latencies: []*common.Latency{}}
+	err := g.each(func(member *WireGuard) error {
+		latencies, err := member.GetOutboundsLatency(ctx, request)
+		if err != nil {
+			return err
+		}
+		response.Latencies = append(response.Latencies, latencies.GetLatencies()...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (g *Group) GetUserOnlineStats(ctx context.Context, email string) (*common.OnlineStatResponse, error) {
+	response := &common.OnlineStatResponse{Name: email}
+	err := g.each(func(member *WireGuard) error {
+		online, err := member.GetUserOnlineStats(ctx, email)
+		if err != nil {
+			return err
+		}
+		response.Value = max(response.Value, online.GetValue())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (g *Group) GetUserOnlineIpListStats(ctx context.Context, email string) (*common.StatsOnlineIpListResponse, error) {
+	response := &common.StatsOnlineIpListResponse{Name: email, Ips: make(map[string]int64)}
+	err := g.each(func(member *WireGuard) error {
+		ips, err := member.GetUserOnlineIpListStats(ctx, email)
+		if err != nil {
+			return err
+		}
+		for ip, lastSeen := range ips.GetIps() {
+			response.Ips[ip] = max(response.Ips[ip], lastSeen)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (g *Group) GetPeerAllocations(ctx context.Context) (*common.PeerAllocationsResponse, error) {
+	response := &common.PeerAllocationsResponse{}
+	err := g.each(func(member *WireGuard) error {
+		allocations, err := member.GetPeerAllocations(ctx)
+		if err != nil {
+			return err
+		}
+		response.Allocations = append(response.Allocations, allocations.GetAllocations()...)
+		response.Conflicts = append(response.Conflicts, allocations.GetConflicts()...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(response.Allocations, func(a, b *common.PeerAllocation) int {
+		return cmp.Or(cmp.Compare(a.GetEmail(), b.GetEmail()), cmp.Compare(a.GetInbound(), b.GetInbound()))
+	})
+	slices.SortStableFunc(response.Conflicts, func(a, b *common.PeerConflict) int {
+		return cmp.Or(cmp.Compare(a.GetEmail(), b.GetEmail()), cmp.Compare(a.GetInbound(), b.GetInbound()))
+	})
+	return response, nil
+}
+
+// UpdatePolicy updates the requested inbound, or every interface.
+func (g *Group) UpdatePolicy(ctx context.Context, request *common.PolicyRequest) error {
+	if request.GetInbound() != "" {
+		member := g.member(request.GetInbound())
+		if member == nil {
+			return status.Errorf(codes.NotFound, "unknown wireguard inbound %s", request.GetInbound())
+		}
+		return member.UpdatePolicy(ctx, request)
+	}
+	if err := policyFromProto(request.GetPolicy()).validate(); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid policy: %v", err)
+	}
+	return g.each(func(member *WireGuard) error { return member.UpdatePolicy(ctx, request) })
+}
+
+// SubscribePeerEvents merges the events of every interface. Like a single
+// interface, the merged channel is closed when the subscriber falls behind.
+func (g *Group) SubscribePeerEvents(ctx context.Context) (<-chan *common.PeerEvent, func(), error) {
+	merged := make(chan *common.PeerEvent, peerEventBufferSize)
+	releases := make([]func(), 0, len(g.members))
+	releaseAll := func() {
+		for _, release := range releases {
+			release()
+		}
+	}
+
+	overflow := make(chan struct{})
+	var overflowOnce sync.Once
+
+	var wg sync.WaitGroup
+	for _, member := range g.members {
+		events, release, err := member.SubscribePeerEvents(ctx)
+		if err != nil {
+			releaseAll()
+			return nil, nil, fmt.Errorf("%s: %w", member.config.InterfaceName, err)
+		}
+		releases = append(releases, release)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for event := range events {
+				select {
+				case merged <- event:
+				default:
+					overflowOnce.Do(func() { close(overflow) })
+					return
+				}
+			}
+		}()
+	}
+
+	var once sync.Once
+	release := func() { once.Do(releaseAll) }
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(merged)
+		close(done)
+	}()
+	go func() {
+		select {
+		case <-overflow:
+			release()
+		case <-done:
+		}
+	}()
+
+	return merged, release, nil
+}
+
+// GetPeerSessions returns the sessions of every interface, oldest first.
+func (g *Group) GetPeerSessions(ctx context.Context, email string) (*common.PeerSessionsResponse, error) {
+	response := &common.PeerSessionsResponse{Email: email}
+	err := g.each(func(member *WireGuard) error {
+		sessions, err := member.GetPeerSessions(ctx, email)
+		if err != nil {
+			return err
+		}
+		response.Sessions = append(response.Sessions, sessions.GetSessions()...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Active sessions have no end and sort last.
+	active := func(session *common.PeerSession) int {
+		if session.GetEndedAt() == 0 {
+			return 1
+		}
+		return 0
+	}
+	slices.SortStableFunc(response.Sessions, func(a, b *common.PeerSession) int {
+		return cmp.Or(cmp.Compare(active(a), active(b)), cmp.Compare(a.GetStartedAt(), b.GetStartedAt()))
+	})
+	return response, nil
+}
+
+// GetClientConfig uses the requested inbound, or the first interface the user
+// has a peer on.
+func (g *Group) GetClientConfig(ctx context.Context, request *common.ClientConfigRequest) (*common.ClientConfigResponse, error) {
+	if request.GetInbound() != "" {
+		member := g.member(request.GetInbound())
+		if member == nil {
+			return nil, status.Errorf(codes.NotFound, "unknown wireguard inbound %s", request.GetInbound())
+		}
+		return member.GetClientConfig(ctx, request)
+	}
+
+	for _, member := range g.members {
+		if member.peerStore.GetByEmail(request.GetEmail()) != nil {
+			return member.GetClientConfig(ctx, request)
+		}
+	}
+	return g.members[0].GetClientConfig(ctx, request)
+}
@@ -0,0 +1,226 @@
+package wireguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vishvananda/netlink"
+
+	"github.com/pasarguard/node/common"
+	nodeconfig "github.com/pasarguard/node/config"
+)
+
+func newGroupTestManager(interfaceName string, rxBytes *atomic.Uint64) *Manager {
+	return &Manager{
+		iFaceName: interfaceName,
+		client:    &fakeWGClient{},
+		nl: mockNetlinkOps{
+			parseAddrFn: func(address string) (*netlink.Addr, error) {
+				return netlink.ParseAddr(address)
+			},
+			linkAddFn: func(_ netlink.Link) error {
+				return nil
+			},
+			linkByName: func(name string) (netlink.Link, error) {
+				return &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{
+					Name:       name,
+					Flags:      net.FlagUp,
+					Statistics: &netlink.LinkStatistics{RxBytes: rxBytes.Load()},
+				}}, nil
+			},
+			addrAddFn: func(_ netlink.Link, _ *netlink.Addr) error {
+				return nil
+			},
+			linkSetUpFn: func(_ netlink.Link) error {
+				return nil
+			},
+			linkDelFn: func(_ netlink.Link) error {
+				return nil
+			},
+		},
+	}
+}
+
+// groupTestRx holds the received byte counters of the test interfaces.
+var groupTestRx = map[string]*atomic.Uint64{"wg-a": {}, "wg-b": {}}
+
+func newTestGroup(t *testing.T, users []*common.User) *Group {
+	t.Helper()
+	t.Setenv(envHostRouting, "0")
+
+	cfg := &nodeconfig.Config{
+		LogBufferSize:               8,
+		StatsUpdateIntervalSeconds:  10,
+		StatsCleanupIntervalSeconds: 10,
+	}
+
+	privateA, _, _ := GenerateKeyPair()
+	privateB, _, _ := GenerateKeyPair()
+	configs, err := NewConfigs(fmt.Sprintf(`{"interfaces":[
+		{"interface_name":"wg-a","listen_port":51820,"address":["10.81.0.1/24"],"private_key":"%s"},
+		{"interface_name":"wg-b","listen_port":51821,"address":["10.82.0.1/24"],"private_key":"%s"}
+	]}`, privateA, privateB))
+	if err != nil {
+		t.Fatalf("NewConfigs failed: %v", err)
+	}
+
+	g, err := newGroupWithFactory(configs, func(wgConfig *Config) (*WireGuard, error) {
+		return newWithManagerFactory(cfg, wgConfig, users, func(name string) (*Manager, error) {
+			return newGroupTestManager(name, groupTestRx[name]), nil
+		})
+	})
+	if err != nil {
+		t.Fatalf("newGroupWithFactory failed: %v", err)
+	}
+	return g
+}
+
+func groupTestUser(t *testing.T, email string, inbounds []string, peerIPs ...string) *common.User {
+	t.Helper()
+	return &common.User{
+		Email:    email,
+		Inbounds: inbounds,
+		Proxies: &common.Proxy{
+			Wireguard: &common.Wireguard{PublicKey: mustGeneratePublicKey(t), PeerIps: peerIPs},
+		},
+	}
+}
+
+func TestGroupRoutesUsersByInbound(t *testing.T) {
+	users := []*common.User{
+		groupTestUser(t, "a@example.com", []string{"wg-a"}, "10.81.0.2/32"),
+		groupTestUser(t, "both@example.com", []string{"wg-a", "wg-b"}, "10.81.0.3/32", "10.82.0.3/32"),
+	}
+	g := newTestGroup(t, users)
+	defer g.Shutdown()
+
+	if !g.Started() {
+		t.Fatal("expected every interface to be started")
+	}
+	if g.member("wg-b").peerStore.GetByEmail("a@example.com") != nil {
+		t.Fatal("user without the wg-b inbound must not get a peer there")
+	}
+
+	response, err := g.GetPeerAllocations(context.Background())
+	if err != nil {
+		t.Fatalf("GetPeerAllocations failed: %v", err)
+	}
+	var got []string
+	for _, allocation := range response.GetAllocations() {
+		got = append(got, allocation.GetEmail()+"@"+allocation.GetInbound()+"="+strings.Join(allocation.GetPeerIps(), ","))
+	}
+	want := "a@example.com@wg-a=10.81.0.2/32 both@example.com@wg-a=10.81.0.3/32 both@example.com@wg-b=10.82.0.3/32"
+	if strings.Join(got, " ") != want {
+		t.Fatalf("expected allocations %q, got %q", want, strings.Join(got, " "))
+	}
+
+	// Moving a user off wg-a removes the peer there only.
+	moved := groupTestUser(t, "a@example.com", []string{"wg-b"}, "10.82.0.2/32")
+	if err := g.SyncUser(context.Background(), moved); err != nil {
+		t.Fatalf("SyncUser failed: %v", err)
+	}
+	if g.member("wg-a").peerStore.GetByEmail("a@example.com") != nil || g.member("wg-b").peerStore.GetByEmail("a@example.com") == nil {
+		t.Fatal("expected the user to move from wg-a to wg-b")
+	}
+}
+
+func TestGroupReportsStatsPerInterface(t *testing.T) {
+	g := newTestGroup(t, nil)
+	defer g.Shutdown()
+
+	// The first read sets the baseline of each interface.
+	if _, err := g.GetStats(context.Background(), &common.StatRequest{Type: common.StatType_Inbounds}); err != nil {
+		t.Fatalf("GetStats failed: %v", err)
+	}
+	groupTestRx["wg-a"].Add(100)
+	groupTestRx["wg-b"].Add(200)
+
+	response, err := g.GetStats(context.Background(), &common.StatRequest{Type: common.StatType_Inbounds})
+	if err != nil {
+		t.Fatalf("GetStats failed: %v", err)
+	}
+	byName := make(map[string]int64)
+	for _, stat := range response.GetStats() {
+		byName[stat.GetName()+"/"+stat.GetLink()] += stat.GetValue()
+	}
+	if byName["wg-a/traffic"] != 100 || byName["wg-b/traffic"] != 200 {
+		t.Fatalf("expected separate interface stats, got %v", byName)
+	}
+
+	single, err := g.GetStats(context.Background(), &common.StatRequest{Type: common.StatType_Inbound, Name: "wg-b"})
+	if err != nil {
+		t.Fatalf("GetStats failed: %v", err)
+	}
+	for _, stat := range single.GetStats() {
+		if stat.GetName() != "wg-b" {
+			t.Fatalf("expected only wg-b stats, got %v", single.GetStats())
+		}
+	}
+}
+
+func TestMergeStatsSumsUserTraffic(t *testing.T) {
+	merged := mergeStats([]*common.Stat{
+		{Name: "user", Type: "uplink", Value: 1},
+		{Name: "user", Type: "downlink", Value: 2},
+		{Name: "user", Type: "uplink", Value: 3},
+	})
+	if len(merged) != 2 || merged[0].GetValue() != 4 || merged[1].GetValue() != 2 {
+		t.Fatalf("unexpected merged stats: %v", merged)
+	}
+}
+
+func TestGroupMergesLogsAndClosesAfterShutdown(t *testing.T) {
+	g := newTestGroup(t, nil)
+
+	seen := make(map[string]bool)
+	deadline := time.After(time.Second)
+	for !seen["wg-a"] || !seen["wg-b"] {
+		select {
+		case line := <-g.Logs():
+			for _, name := range []string{"wg-a", "wg-b"} {
+				if strings.Contains(line, "interface "+name+" initialized") {
+					seen[name] = true
+				}
+			}
+		case <-deadline:
+			t.Fatalf("expected startup logs of both interfaces, saw %v", seen)
+		}
+	}
+
+	g.Shutdown()
+	deadline = time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-g.Logs():
+			if !ok {
+				return
+			}
+		case <-deadline:
+			t.Fatal("expected merged log channel to close after shutdown")
+		}
+	}
+}
+
+func TestNewGroupShutsDownStartedInterfacesOnFailure(t *testing.T) {
+	configs := []*Config{{InterfaceName: "wg-a"}, {InterfaceName: "wg-b"}}
+	first := &WireGuard{state: lifecycleRunning, logChan: make(chan string, 1)}
+
+	_, err := newGroupWithFactory(configs, func(wgConfig *Config) (*WireGuard, error) {
+		if wgConfig.InterfaceName == "wg-a" {
+			return first, nil
+		}
+		return nil, errors.New("boom")
+	})
+	if err == nil || !strings.Contains(err.Error(), "interface wg-b: boom") {
+		t.Fatalf("expected wg-b startup error, got %v", err)
+	}
+	if first.Started() {
+		t.Fatal("expected the started interface to be shut down")
+	}
+}
@@ -0,0 +1,130 @@
+//go:build linux
+
+package wireguard
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const envFirewall = "PG_NODE_WG_FIREWALL"
+
+// hostEgress names the interfaces WireGuard traffic leaves the host through.
+// ipv6 is empty when the WireGuard interface has no IPv6 address.
+type hostEgress struct {
+	ipv4 string
+	ipv6 string
+}
+
+// hostFirewall installs the host routing rules. Every rule carries the owner
+// comment from nftOwnerCommentPrefix, so cleanup only removes the rules of
+// its own instance.
+type hostFirewall interface {
+	String() string
+	ensureMasquerade(ipv6 bool, wgIface, outputIface string, egressOnly bool, ownerID string) error
+	ensureForwarding(wgIface string, egress hostEgress, ownerID string) error
+	cleanup(ownerID string) error
+}
+
+// selectHostFirewall returns the driver named by PG_NODE_WG_FIREWALL
+// (auto, nftables, iptables or iptables-legacy).
+func selectHostFirewall() (hostFirewall, error) {
+	switch driver := strings.ToLower(strings.TrimSpace(os.Getenv(envFirewall))); driver {
+	case "", "auto":
+		return detectHostFirewall(exec.LookPath, iptablesVersion), nil
+	case "nftables", "nft":
+		return &nftFirewall{}, nil
+	case "iptables":
+		return newIptablesFirewall("iptables", "ip6tables"), nil
+	case "iptables-legacy":
+		return newIptablesFirewall("iptables-legacy", "ip6tables-legacy"), nil
+	default:
+		return nil, fmt.Errorf("unknown %s %q (want auto, nftables, iptables or iptables-legacy)", envFirewall, driver)
+	}
+}
+
+// requireNFTFirewall fails when the selected driver is iptables. Policies,
+// rate limits, xray_tproxy, peer outbounds and outbound stats are only
+// implemented with nftables.
+func requireNFTFirewall(feature string) error {
+	firewall, err := selectHostFirewall()
+	if err != nil {
+		return err
+	}
+	if _, ok := firewall.(*nftFirewall); !ok {
+		return fmt.Errorf("the nftables firewall driver is required for %s, but %s is in use (see %s)", feature, firewall, envFirewall)
+	}
+	return nil
+}
+
+// checkFirewallFeatures rejects a config using nftables-only features on a
+// host that runs the iptables driver.
+func checkFirewallFeatures(cfg *Config) error {
+	var features []string
+	if !cfg.Policy.empty() {
+		features = append(features, "policy")
+	}
+	if cfg.XrayTProxy != nil {
+		features = append(features, "xray_tproxy")
+	}
+	if len(cfg.Outbounds) > 0 {
+		features = append(features, "outbounds")
+	}
+	if len(features) == 0 {
+		return nil
+	}
+	return requireNFTFirewall(strings.Join(features, ", "))
+}
+
+// detectHostFirewall prefers nftables. iptables is used when nft is missing,
+// or when iptables is the legacy variant: legacy FORWARD chains are evaluated
+// apart from nftables, so accepting there is the only way past a drop policy.
+func detectHostFirewall(lookPath func(string) (string, error), version func(string) string) hostFirewall {
+	if _, err := lookPath("iptables"); err == nil {
+		_, nftErr := lookPath("nft")
+		if nftErr != nil || iptablesIsLegacy(version("iptables")) {
+			return newIptablesFirewall("iptables", "ip6tables")
+		}
+	}
+	return &nftFirewall{}
+}
+
+// nftFirewall keeps the host routing rules in our own nftables NAT tables and
+// in the forward base chains already on the host.
+type nftFirewall struct {
+	natChains []nftBaseChain
+}
+
+func (f *nftFirewall) String() string { return "nftables" }
+
+func (f *nftFirewall) ensureMasquerade(ipv6 bool, wgIface, outputIface string, egressOnly bool, ownerID string) error {
+	chain := nftBaseChain{family: nftTableFamily, table: nftTableName, name: nftPostroutingChain}
+	if ipv6 {
+		chain = nftBaseChain{family: nftTableFamilyIPv6, table: nftTableNameIPv6, name: nftPostroutingChain}
+	}
+	f.natChains = append(f.natChains, chain)
+	return ensureNFTMasquerade(chain, wgIface, outputIface, egressOnly, ownerID)
+}
+
+func (f *nftFirewall) ensureForwarding(wgIface string, egress hostEgress, ownerID string) error {
+	return ensureNFTForwarding(wgIface, nftForwardEgress(egress), ownerID)
+}
+
+func (f *nftFirewall) cleanup(ownerID string) error {
+	return cleanupNFTHostRouting(ownerID, f.natChains)
+}
+
+// nftForwardEgress maps each nft family to the egress interfaces its forward
+// chains see; inet chains carry both address families.
+func nftForwardEgress(egress hostEgress) map[string][]string {
+	families := map[string][]string{nftTableFamily: {egress.ipv4}, "inet": {egress.ipv4}}
+	if egress.ipv6 != "" {
+		families[nftTableFamilyIPv6] = []string{egress.ipv6}
+		if egress.ipv6 != egress.ipv4 {
+			families["inet"] = append(families["inet"], egress.ipv6)
+		}
+	}
+	return families
+}
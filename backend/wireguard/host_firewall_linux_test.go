@@ -0,0 +1,85 @@
+//go:build linux
+
+package wireguard
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDetectHostFirewall(t *testing.T) {
+	lookPath := func(found ...string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			for _, f := range found {
+				if f == name {
+					return "/usr/sbin/" + name, nil
+				}
+			}
+			return "", errors.New("not found")
+		}
+	}
+	nfTables := func(string) string { return "iptables v1.8.9 (nf_tables)" }
+	legacy := func(string) string { return "iptables v1.8.7 (legacy)" }
+
+	for name, tc := range map[string]struct {
+		lookPath func(string) (string, error)
+		version  func(string) string
+		want     string
+	}{
+		"nft host":          {lookPath("nft", "iptables"), nfTables, "nftables"},
+		"legacy iptables":   {lookPath("nft", "iptables"), legacy, "iptables"},
+		"no nft":            {lookPath("iptables"), nfTables, "iptables"},
+		"nothing installed": {lookPath(), nfTables, "nftables"},
+	} {
+		if got := detectHostFirewall(tc.lookPath, tc.version).String(); got != tc.want {
+			t.Fatalf("%s: expected %s, got %s", name, tc.want, got)
+		}
+	}
+}
+
+func TestSelectHostFirewallFromEnv(t *testing.T) {
+	t.Setenv(envFirewall, "iptables-legacy")
+	firewall, err := selectHostFirewall()
+	if err != nil || firewall.String() != "iptables-legacy" {
+		t.Fatalf("expected iptables-legacy, got %v (%v)", firewall, err)
+	}
+
+	t.Setenv(envFirewall, "pf")
+	if _, err := selectHostFirewall(); err == nil {
+		t.Fatal("expected unknown firewall error")
+	}
+}
+
+func TestCheckFirewallFeaturesRejectsIptables(t *testing.T) {
+	cfg := &Config{
+		XrayTProxy: &XrayTProxyConfig{Port: 12345},
+		Policy:     &PolicyConfig{BlockPeerToPeer: true},
+	}
+
+	t.Setenv(envFirewall, "iptables")
+	err := checkFirewallFeatures(cfg)
+	if err == nil || !strings.Contains(err.Error(), "nftables firewall driver is required for policy, xray_tproxy") {
+		t.Fatalf("expected nftables-only features to be rejected, got %v", err)
+	}
+	if err := checkFirewallFeatures(&Config{}); err != nil {
+		t.Fatalf("expected a config without nftables-only features to pass, got %v", err)
+	}
+
+	t.Setenv(envFirewall, "nftables")
+	if err := checkFirewallFeatures(cfg); err != nil {
+		t.Fatalf("expected the nftables driver to pass, got %v", err)
+	}
+}
+
+func TestNFTForwardEgress(t *testing.T) {
+	got := nftForwardEgress(hostEgress{ipv4: "eth0", ipv6: "eth1"})
+	if strings.Join(got["ip"], ",") != "eth0" || strings.Join(got["ip6"], ",") != "eth1" || strings.Join(got["inet"], ",") != "eth0,eth1" {
+		t.Fatalf("unexpected dual-stack egress: %v", got)
+	}
+
+	got = nftForwardEgress(hostEgress{ipv4: "eth0"})
+	if _, ok := got["ip6"]; ok || strings.Join(got["inet"], ",") != "eth0" {
+		t.Fatalf("unexpected IPv4 egress: %v", got)
+	}
+}
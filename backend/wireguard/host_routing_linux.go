@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/netip"
 	"os"
 	"os/exec"
 	"strings"
@@ -15,19 +16,25 @@ import (
 )
 
 const (
-	envHostRouting        = "PG_NODE_WG_HOST_ROUTING"
-	envNATOutputInterface = "PG_NODE_WG_NAT_OUTPUT_INTERFACE"
-	envNATEgressOnly      = "PG_NODE_WG_NAT_EGRESS_ONLY"
-	ipv4ForwardPath       = "/proc/sys/net/ipv4/ip_forward"
-	nftTableFamily        = "ip"
-	nftTableName          = "pg_node_wg_nat"
-	nftPostroutingChain   = "postrouting"
-	nftForwardChain       = "forward"
-	nftRuleCommentPrefix  = "pg_node_wg "
+	envHostRouting            = "PG_NODE_WG_HOST_ROUTING"
+	envNATOutputInterface     = "PG_NODE_WG_NAT_OUTPUT_INTERFACE"
+	envNATOutputInterfaceIPv6 = "PG_NODE_WG_NAT_OUTPUT_INTERFACE_IPV6"
+	envNATEgressOnly          = "PG_NODE_WG_NAT_EGRESS_ONLY"
+	envIPv6NAT                = "PG_NODE_WG_IPV6_NAT"
+	ipv4ForwardPath           = "/proc/sys/net/ipv4/ip_forward"
+	ipv6ForwardPath           = "/proc/sys/net/ipv6/conf/all/forwarding"
+	nftTableFamily            = "ip"
+	nftTableName              = "pg_node_wg_nat"
+	nftTableFamilyIPv6        = "ip6"
+	nftTableNameIPv6          = "pg_node_wg_nat6"
+	nftPostroutingChain       = "postrouting"
+	nftForwardChain           = "forward"
+	nftRuleCommentPrefix      = "pg_node_wg "
 )
 
-// applyLinuxHostRouting installs an nftables masquerade rule for traffic from the
-// WireGuard interface to the IPv4 default-route egress interface.
+// applyLinuxHostRouting installs a masquerade rule for traffic from the WireGuard
+// interface to the IPv4 default-route egress interface, plus forward accepts.
+// The rules go through nftables or iptables as chosen by selectHostFirewall.
 //
 // wgInterfaceName comes from core JSON interface_name (e.g. wg0, wg1); never hardcoded here.
 // The NAT egress interface is resolved in order:
@@ -35,8 +42,13 @@ const (
 //  2. IPv4 default route interface (ip -4 -j route, else /proc/net/route)
 //  3. eth0 as last-resort fallback
 //
+// When addresses holds an IPv6 prefix, IPv6 forwarding and forward rules are added
+// too, with the egress taken from PG_NODE_WG_NAT_OUTPUT_INTERFACE_IPV6 or the IPv6
+// default route. Peers are masqueraded (NAT66) unless PG_NODE_WG_IPV6_NAT=0, which
+// is meant for prefixes routed to this host.
+//
 // Disable all of this with PG_NODE_WG_HOST_ROUTING=0.
-func applyLinuxHostRouting(wgInterfaceName string) func() {
+func applyLinuxHostRouting(wgInterfaceName string, addresses []string) func() {
 	if v := strings.TrimSpace(os.Getenv(envHostRouting)); v == "0" || strings.EqualFold(v, "false") {
 		return nil
 	}
@@ -46,18 +58,13 @@ func applyLinuxHostRouting(wgInterfaceName string) func() {
 		wgIf = "wg0"
 	}
 
-	outIf := strings.TrimSpace(os.Getenv(envNATOutputInterface))
-	if outIf == "" {
-		var ok bool
-		outIf, ok = linuxDefaultRouteInterfaceIPv4()
-		if !ok {
-			outIf = "eth0"
-			log.Printf(
-				"wireguard host routing: could not detect default IPv4 egress interface; using fallback %q (set %s)",
-				outIf,
-				envNATOutputInterface,
-			)
-		}
+	outIf, ok := resolveIPv4EgressInterface()
+	if !ok {
+		log.Printf(
+			"wireguard host routing: could not detect default IPv4 egress interface; using fallback %q (set %s)",
+			outIf,
+			envNATOutputInterface,
+		)
 	}
 
 	egressOnly := true
@@ -69,26 +76,97 @@ func applyLinuxHostRouting(wgInterfaceName string) func() {
 		wgIf, outIf, egressOnly,
 	)
 
+	firewall, err := selectHostFirewall()
+	if err != nil {
+		log.Printf("wireguard host routing: %v", err)
+		return nil
+	}
+
 	ownerID := newHostRoutingOwnerID(wgIf)
-	log.Printf("wireguard host routing: owner %q", ownerID)
+	log.Printf("wireguard host routing: owner %q, firewall %s", ownerID, firewall)
 
 	if err := ensureIPv4Forwarding(); err != nil {
 		log.Printf("wireguard host routing: enabling IPv4 forwarding failed: %v", err)
 	}
 
-	if err := ensureNFTMasquerade(wgIf, outIf, egressOnly, ownerID); err != nil {
-		log.Printf("wireguard host routing: nftables masquerade failed: %v", err)
+	if err := firewall.ensureMasquerade(false, wgIf, outIf, egressOnly, ownerID); err != nil {
+		log.Printf("wireguard host routing: %s masquerade failed: %v", firewall, err)
 	}
 
-	if err := ensureNFTForwarding(wgIf, outIf, ownerID); err != nil {
-		log.Printf("wireguard host routing: nftables forward rules failed: %v", err)
+	egress := hostEgress{ipv4: outIf}
+	acceptRAEgress := ""
+	if hasIPv6Address(addresses) {
+		outIf6 := resolveIPv6EgressInterface(outIf)
+		nat66 := true
+		if env := os.Getenv(envIPv6NAT); env != "" {
+			nat66 = envTruthy(env)
+		}
+		log.Printf("wireguard host routing: IPv6 egress %q (nat66=%v)", outIf6, nat66)
+
+		if err := ensureIPv6Forwarding(outIf6); err != nil {
+			log.Printf("wireguard host routing: enabling IPv6 forwarding failed: %v", err)
+		} else {
+			acceptRAEgress = outIf6
+		}
+		if nat66 {
+			if err := firewall.ensureMasquerade(true, wgIf, outIf6, egressOnly, ownerID); err != nil {
+				log.Printf("wireguard host routing: %s IPv6 masquerade failed: %v", firewall, err)
+			}
+		}
+		egress.ipv6 = outIf6
+	}
+
+	if err := firewall.ensureForwarding(wgIf, egress, ownerID); err != nil {
+		log.Printf("wireguard host routing: %s forward rules failed: %v", firewall, err)
 	}
 
 	return func() {
-		if err := cleanupLinuxHostRouting(ownerID); err != nil {
+		if err := firewall.cleanup(ownerID); err != nil {
 			log.Printf("wireguard host routing: cleanup failed for owner %q: %v", ownerID, err)
 		}
+		if acceptRAEgress != "" {
+			if err := releaseAcceptRA(acceptRAEgress); err != nil {
+				log.Printf("wireguard host routing: %v", err)
+			}
+		}
+	}
+}
+
+// resolveIPv4EgressInterface reports false when it fell back to eth0.
+func resolveIPv4EgressInterface() (string, bool) {
+	if outIf := strings.TrimSpace(os.Getenv(envNATOutputInterface)); outIf != "" {
+		return outIf, true
 	}
+	if outIf, ok := linuxDefaultRouteInterfaceIPv4(); ok {
+		return outIf, true
+	}
+	return "eth0", false
+}
+
+func hasIPv6Address(addresses []string) bool {
+	for _, address := range addresses {
+		if prefix, err := netip.ParsePrefix(strings.TrimSpace(address)); err == nil && prefix.Addr().Is6() && !prefix.Addr().Is4In6() {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveIPv6EgressInterface picks the IPv6 NAT egress: the env override, the
+// IPv6 default route, then the IPv4 egress.
+func resolveIPv6EgressInterface(ipv4Egress string) string {
+	if outIf := strings.TrimSpace(os.Getenv(envNATOutputInterfaceIPv6)); outIf != "" {
+		return outIf
+	}
+	if outIf, ok := linuxDefaultRouteInterfaceIPv6(); ok {
+		return outIf
+	}
+	log.Printf(
+		"wireguard host routing: could not detect default IPv6 egress interface; using %q (set %s)",
+		ipv4Egress,
+		envNATOutputInterfaceIPv6,
+	)
+	return ipv4Egress
 }
 
 func envTruthy(s string) bool {
@@ -99,24 +177,24 @@ func envTruthy(s string) bool {
 // ensureNFTMasquerade sets up NAT rules dynamically.
 // If egressOnly, only oifname is matched (same idea as "oifname eth0 masquerade" in /etc/nftables.conf).
 // Otherwise traffic is matched from the WireGuard interface to the egress interface.
-func ensureNFTMasquerade(wgIface, outputIface string, egressOnly bool, ownerID string) error {
-	if err := runNFT("add", "table", nftTableFamily, nftTableName); err != nil && !nftAlreadyExists(err) {
+// chain is the postrouting chain of the ip or ip6 NAT table.
+func ensureNFTMasquerade(chain nftBaseChain, wgIface, outputIface string, egressOnly bool, ownerID string) error {
+	if err := runNFT("add", "table", chain.family, chain.table); err != nil && !nftAlreadyExists(err) {
 		return err
 	}
 
 	if err := runNFT(
-		"add", "chain", nftTableFamily, nftTableName, nftPostroutingChain,
+		"add", "chain", chain.family, chain.table, chain.name,
 		"{", "type", "nat", "hook", "postrouting", "priority", "100", ";", "policy", "accept", ";", "}",
 	); err != nil && !nftAlreadyExists(err) {
 		return err
 	}
 
-	chain := nftBaseChain{family: nftTableFamily, table: nftTableName, name: nftPostroutingChain}
 	if err := removeNFTRulesWithCommentPrefix(chain, nftOwnerCommentPrefix(ownerID)); err != nil {
 		return err
 	}
 
-	args := []string{"add", "rule", nftTableFamily, nftTableName, nftPostroutingChain}
+	args := []string{"add", "rule", chain.family, chain.table, chain.name}
 	args = append(args, nftMasqueradeRuleArgs(wgIface, outputIface, egressOnly)...)
 	args = append(args, "comment", nftString(nftNATRuleComment(ownerID, wgIface, outputIface, egressOnly)))
 	return runNFT(args...)
@@ -146,20 +224,29 @@ func nftMasqueradeConfig(rule, comment string) string {
 `, nftTableFamily, nftTableName, nftPostroutingChain, rule, comment)
 }
 
-func ensureNFTForwarding(wgIface, outputIface, ownerID string) error {
+// ensureNFTForwarding accepts WireGuard traffic in every forward base chain.
+// egress maps an nft family to its egress interfaces; chains of other
+// families are left alone.
+func ensureNFTForwarding(wgIface string, egress map[string][]string, ownerID string) error {
 	chains, err := nftForwardBaseChains()
 	if err != nil {
 		return err
 	}
 	for _, chain := range chains {
-		if err := removeNFTRulesWithCommentPrefix(chain, nftOwnerCommentPrefix(ownerID)); err != nil {
-			return err
+		outputIfaces, ok := egress[chain.family]
+		if !ok {
+			continue
 		}
-		if err := insertNFTForwardRule(chain, wgIface, outputIface, ownerID, false); err != nil {
+		if err := removeNFTRulesWithCommentPrefix(chain, nftOwnerCommentPrefix(ownerID)); err != nil {
 			return err
 		}
-		if err := insertNFTForwardRule(chain, wgIface, outputIface, ownerID, true); err != nil {
-			return err
+		for _, outputIface := range outputIfaces {
+			if err := insertNFTForwardRule(chain, wgIface, outputIface, ownerID, false); err != nil {
+				return err
+			}
+			if err := insertNFTForwardRule(chain, wgIface, outputIface, ownerID, true); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -219,6 +306,12 @@ func parseNFTForwardBaseChains(data []byte) ([]nftBaseChain, error) {
 		if err := json.Unmarshal(raw, &chain); err != nil {
 			return nil, fmt.Errorf("parse nft chain: %w", err)
 		}
+		// The shaping, policy and counter chains are ours; forward accepts
+		// there would skip the limits and the policy drops.
+		switch chain.Table {
+		case nftShapeTableName, nftPolicyTableName, nftCountersTableName:
+			continue
+		}
 		if chain.Hook != nftForwardChain || !nftForwardFamilySupported(chain.Family) {
 			continue
 		}
@@ -232,7 +325,7 @@ func parseNFTForwardBaseChains(data []byte) ([]nftBaseChain, error) {
 }
 
 func nftForwardFamilySupported(family string) bool {
-	return family == "ip" || family == "inet"
+	return family == "ip" || family == "ip6" || family == "inet"
 }
 
 func removeNFTRulesWithCommentPrefix(chain nftBaseChain, commentPrefix string) error {
@@ -305,16 +398,64 @@ func nftString(s string) string {
 	return fmt.Sprintf("%q", s)
 }
 
+// egressAcceptRA holds the egress interfaces switched to accept_ra=2. The
+// last interface routing through an egress restores its saved value.
+var egressAcceptRA = newSharedHostState[string]()
+
+// ensureIPv6Forwarding enables IPv6 forwarding. Forwarding makes the kernel
+// ignore router advertisements, so an egress that learns its route from them
+// is switched to accept_ra=2 to keep it until releaseAcceptRA.
+func ensureIPv6Forwarding(egressIface string) error {
+	if err := egressAcceptRA.acquire(egressIface, func() (func() error, error) {
+		return keepAcceptingRA(fmt.Sprintf("/proc/sys/net/ipv6/conf/%s/accept_ra", egressIface))
+	}); err != nil {
+		return err
+	}
+	if err := enableSysctl(ipv6ForwardPath); err != nil {
+		_ = releaseAcceptRA(egressIface)
+		return err
+	}
+	return nil
+}
+
+// keepAcceptingRA switches the accept_ra sysctl at acceptRAPath from 1 to 2,
+// which accepts router advertisements with forwarding on, and returns the
+// function restoring the saved value.
+func keepAcceptingRA(acceptRAPath string) (func() error, error) {
+	out, err := os.ReadFile(acceptRAPath)
+	if err != nil || strings.TrimSpace(string(out)) != "1" {
+		return func() error { return nil }, nil
+	}
+	if err := os.WriteFile(acceptRAPath, []byte("2\n"), 0o644); err != nil {
+		return nil, fmt.Errorf("write %s: %w", acceptRAPath, err)
+	}
+	return func() error {
+		if err := os.WriteFile(acceptRAPath, out, 0o644); err != nil {
+			return fmt.Errorf("restore %s: %w", acceptRAPath, err)
+		}
+		return nil
+	}, nil
+}
+
+func releaseAcceptRA(egressIface string) error {
+	return egressAcceptRA.release(egressIface)
+}
+
 func ensureIPv4Forwarding() error {
-	out, err := os.ReadFile(ipv4ForwardPath)
+	return enableSysctl(ipv4ForwardPath)
+}
+
+// enableSysctl sets the boolean sysctl at path to 1 unless it already is.
+func enableSysctl(path string) error {
+	out, err := os.ReadFile(path)
 	if err != nil {
-		return fmt.Errorf("read %s: %w", ipv4ForwardPath, err)
+		return fmt.Errorf("read %s: %w", path, err)
 	}
 	if strings.TrimSpace(string(out)) == "1" {
 		return nil
 	}
-	if err := os.WriteFile(ipv4ForwardPath, []byte("1\n"), 0o644); err != nil {
-		return fmt.Errorf("write %s: %w", ipv4ForwardPath, err)
+	if err := os.WriteFile(path, []byte("1\n"), 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
 	}
 	return nil
 }
@@ -345,12 +486,13 @@ func nftAlreadyExists(err error) bool {
 	return strings.Contains(err.Error(), "File exists")
 }
 
-func cleanupLinuxHostRouting(ownerID string) error {
+func cleanupNFTHostRouting(ownerID string, natChains []nftBaseChain) error {
 	var errs []error
 
-	natChain := nftBaseChain{family: nftTableFamily, table: nftTableName, name: nftPostroutingChain}
-	if err := removeNFTRulesWithCommentPrefix(natChain, nftOwnerCommentPrefix(ownerID)); err != nil {
-		errs = append(errs, err)
+	for _, natChain := range natChains {
+		if err := removeNFTRulesWithCommentPrefix(natChain, nftOwnerCommentPrefix(ownerID)); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
 	chains, err := nftForwardBaseChains()
@@ -3,6 +3,8 @@
 package wireguard
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -83,6 +85,8 @@ func TestParseNFTForwardBaseChains(t *testing.T) {
 			{"chain": {"family": "ip", "table": "filter", "name": "FORWARD", "type": "filter", "hook": "forward", "prio": 0, "policy": "drop"}},
 			{"chain": {"family": "inet", "table": "firewalld", "name": "filter_FORWARD", "type": "filter", "hook": "forward", "prio": 10, "policy": "accept"}},
 			{"chain": {"family": "ip6", "table": "filter", "name": "FORWARD", "type": "filter", "hook": "forward", "prio": 0, "policy": "drop"}},
+			{"chain": {"family": "inet", "table": "pg_node_wg_shape", "name": "forward", "type": "filter", "hook": "forward", "prio": -1, "policy": "accept"}},
+			{"chain": {"family": "inet", "table": "pg_node_wg_policy", "name": "forward", "type": "filter", "hook": "forward", "prio": -2, "policy": "accept"}},
 			{"chain": {"family": "ip", "table": "filter", "name": "INPUT", "type": "filter", "hook": "input", "prio": 0, "policy": "drop"}}
 		]
 	}`
@@ -92,8 +96,8 @@ func TestParseNFTForwardBaseChains(t *testing.T) {
 		t.Fatalf("parseNFTForwardBaseChains returned error: %v", err)
 	}
 
-	if len(chains) != 2 {
-		t.Fatalf("expected 2 supported forward chains, got %#v", chains)
+	if len(chains) != 3 {
+		t.Fatalf("expected 3 supported forward chains, got %#v", chains)
 	}
 
 	if chains[0] != (nftBaseChain{family: "ip", table: "filter", name: "FORWARD"}) {
@@ -102,6 +106,18 @@ func TestParseNFTForwardBaseChains(t *testing.T) {
 	if chains[1] != (nftBaseChain{family: "inet", table: "firewalld", name: "filter_FORWARD"}) {
 		t.Fatalf("unexpected second chain: %#v", chains[1])
 	}
+	if chains[2] != (nftBaseChain{family: "ip6", table: "filter", name: "FORWARD"}) {
+		t.Fatalf("unexpected third chain: %#v", chains[2])
+	}
+}
+
+func TestHasIPv6Address(t *testing.T) {
+	if hasIPv6Address([]string{"10.0.0.1/24", "::ffff:10.0.0.1/128", "bad"}) {
+		t.Fatal("IPv4 addresses must not enable IPv6 routing")
+	}
+	if !hasIPv6Address([]string{"10.0.0.1/24", "fd00:70::1/64"}) {
+		t.Fatal("expected IPv6 address to enable IPv6 routing")
+	}
 }
 
 func TestNFTString(t *testing.T) {
@@ -144,3 +160,35 @@ func TestSanitizeNFTOwnerPart(t *testing.T) {
 type staticError string
 
 func (e staticError) Error() string { return string(e) }
+
+func TestKeepAcceptingRARestoresSavedValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "accept_ra")
+	if err := os.WriteFile(path, []byte("1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	restore, err := keepAcceptingRA(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "2\n" {
+		t.Fatalf("expected accept_ra=2 while forwarding, got %q", got)
+	}
+	if err := restore(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "1\n" {
+		t.Fatalf("expected accept_ra to be restored, got %q", got)
+	}
+
+	// A value other than 1 is left alone.
+	if err := os.WriteFile(path, []byte("0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keepAcceptingRA(path); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "0\n" {
+		t.Fatalf("expected accept_ra=0 to be kept, got %q", got)
+	}
+}
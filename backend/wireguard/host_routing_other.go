@@ -3,4 +3,7 @@
 package wireguard
 
 // applyLinuxHostRouting is a no-op on non-Linux platforms.
-func applyLinuxHostRouting(_ string) func() { return nil }
+func applyLinuxHostRouting(_ string, _ []string) func() { return nil }
+
+// checkFirewallFeatures accepts every config; the firewall features are no-ops off Linux.
+func checkFirewallFeatures(_ *Config) error { return nil }
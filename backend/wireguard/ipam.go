@@ -0,0 +1,420 @@
+package wireguard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pasarguard/node/common"
+	"github.com/pasarguard/node/config"
+)
+
+var errAddressPoolExhausted = errors.New("address pool exhausted")
+
+// IPAM hands out peer addresses from the interface Address CIDRs to users the
+// panel sends without peer_ips. Allocations are saved to disk so a user keeps
+// its address across node restarts.
+//
+// New allocations stay pending until Commit, which keeps the ones whose peer
+// was installed and saves the state once per sync.
+type IPAM struct {
+	mu          sync.Mutex
+	path        string
+	pools       []netip.Prefix
+	reserved    map[netip.Addr]struct{}
+	allocations map[string][]netip.Prefix
+	pending     map[string][]netip.Prefix
+	dirty       bool
+}
+
+type ipamState struct {
+	Allocations map[string][]string `json:"allocations"`
+}
+
+// NewIPAM creates an allocator for the given interface addresses and loads
+// previous allocations from path. An empty path keeps allocations in memory.
+func NewIPAM(addresses []string, path string) (*IPAM, error) {
+	ipam := &IPAM{
+		path:        path,
+		reserved:    make(map[netip.Addr]struct{}),
+		allocations: make(map[string][]netip.Prefix),
+		pending:     make(map[string][]netip.Prefix),
+	}
+
+	for _, address := range addresses {
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(address))
+		if err != nil {
+			continue
+		}
+		ipam.pools = append(ipam.pools, prefix.Masked())
+		ipam.reserved[prefix.Addr()] = struct{}{}
+	}
+	if len(ipam.pools) == 0 {
+		return nil, errors.New("ipam needs at least one interface address")
+	}
+
+	if err := ipam.load(); err != nil {
+		return nil, err
+	}
+	return ipam, nil
+}
+
+// Allocate returns the addresses of email, allocating one per pool when it has
+// none yet. taken maps addresses held outside IPAM to their owners; an existing
+// allocation overlapping one of them is replaced. New allocations are pending
+// until Commit.
+func (p *IPAM) Allocate(email string, taken map[string]string) ([]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if current, ok := p.pending[email]; ok && !clashes(current, email, taken) {
+		return prefixStrings(current), nil
+	}
+	if current, ok := p.allocations[email]; ok && !clashes(current, email, taken) {
+		return prefixStrings(current), nil
+	}
+
+	used := make(map[netip.Addr]struct{}, len(p.reserved)+len(taken))
+	for addr := range p.reserved {
+		used[addr] = struct{}{}
+	}
+	for _, held := range []map[string][]netip.Prefix{p.allocations, p.pending} {
+		for owner, prefixes := range held {
+			if owner == email {
+				continue
+			}
+			for _, prefix := range prefixes {
+				used[prefix.Addr()] = struct{}{}
+			}
+		}
+	}
+
+	allocated := make([]netip.Prefix, 0, len(p.pools))
+	for _, pool := range p.pools {
+		addr, ok := nextFreeAddr(pool, used, taken)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", errAddressPoolExhausted, pool)
+		}
+		allocated = append(allocated, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+
+	p.pending[email] = allocated
+	return prefixStrings(allocated), nil
+}
+
+// Commit keeps the pending allocations of users installed reports as having a
+// peer, drops the others, and saves the state if it changed.
+func (p *IPAM) Commit(installed func(email string) bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for email, prefixes := range p.pending {
+		if installed(email) {
+			p.allocations[email] = prefixes
+			p.dirty = true
+		}
+	}
+	clear(p.pending)
+
+	if !p.dirty {
+		return nil
+	}
+	if err := p.saveLocked(); err != nil {
+		return err
+	}
+	p.dirty = false
+	return nil
+}
+
+// Retain drops the allocations of users not in emails. The change is saved by
+// the next Commit.
+func (p *IPAM) Retain(emails map[string]struct{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for email := range p.allocations {
+		if _, ok := emails[email]; !ok {
+			delete(p.allocations, email)
+			p.dirty = true
+		}
+	}
+}
+
+// Allocations returns a copy of the current allocations keyed by email.
+func (p *IPAM) Allocations() map[string][]string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	out := make(map[string][]string, len(p.allocations))
+	for email, prefixes := range p.allocations {
+		out[email] = prefixStrings(prefixes)
+	}
+	return out
+}
+
+func (p *IPAM) load() error {
+	if p.path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(p.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read ipam state: %w", err)
+	}
+
+	var state ipamState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("parse ipam state %s: %w", p.path, err)
+	}
+
+	for email, cidrs := range state.Allocations {
+		prefixes := make([]netip.Prefix, 0, len(cidrs))
+		for _, cidr := range cidrs {
+			prefix, err := netip.ParsePrefix(cidr)
+			if err != nil || !p.inPools(prefix.Addr()) {
+				// The interface addresses changed; the user gets a fresh allocation.
+				prefixes = nil
+				break
+			}
+			prefixes = append(prefixes, prefix)
+		}
+		if len(prefixes) > 0 {
+			p.allocations[email] = prefixes
+		}
+	}
+	return nil
+}
+
+func (p *IPAM) saveLocked() error {
+	if p.path == "" {
+		return nil
+	}
+
+	state := ipamState{Allocations: make(map[string][]string, len(p.allocations))}
+	for email, prefixes := range p.allocations {
+		state.Allocations[email] = prefixStrings(prefixes)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p.path), 0o755); err != nil {
+		return fmt.Errorf("create ipam state dir: %w", err)
+	}
+	tmp := p.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("write ipam state: %w", err)
+	}
+	if err := os.Rename(tmp, p.path); err != nil {
+		return fmt.Errorf("write ipam state: %w", err)
+	}
+	return nil
+}
+
+func (p *IPAM) inPools(addr netip.Addr) bool {
+	for _, pool := range p.pools {
+		if pool.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextFreeAddr returns the lowest host address of pool that is neither used
+// nor inside a taken prefix. The network address and, for IPv4, the broadcast
+// address are skipped.
+func nextFreeAddr(pool netip.Prefix, used map[netip.Addr]struct{}, taken map[string]string) (netip.Addr, bool) {
+	for addr := pool.Addr().Next(); addr.IsValid() && pool.Contains(addr); addr = addr.Next() {
+		if addr.Is4() && !pool.Contains(addr.Next()) {
+			break
+		}
+		if _, ok := used[addr]; ok {
+			continue
+		}
+		if takenOwner(netip.PrefixFrom(addr, addr.BitLen()), "", taken) == "" {
+			return addr, true
+		}
+	}
+	return netip.Addr{}, false
+}
+
+// takenOwner returns the owner of a taken prefix overlapping prefix, ignoring
+// prefixes held by email. IPAM only hands out host prefixes, so it is enough
+// to look up prefix and every prefix containing it.
+func takenOwner(prefix netip.Prefix, email string, taken map[string]string) string {
+	for bits := prefix.Bits(); bits >= 0; bits-- {
+		outer, err := prefix.Addr().Prefix(bits)
+		if err != nil {
+			continue
+		}
+		if owner, ok := taken[outer.String()]; ok && owner != email {
+			return owner
+		}
+	}
+	return ""
+}
+
+func clashes(prefixes []netip.Prefix, email string, taken map[string]string) bool {
+	for _, prefix := range prefixes {
+		if takenOwner(prefix, email, taken) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func prefixStrings(prefixes []netip.Prefix) []string {
+	out := make([]string, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		out = append(out, prefix.String())
+	}
+	return out
+}
+
+// peerConflicts records users whose peer could not be added, so the panel can
+// fix them without the rest of the sync failing.
+type peerConflicts struct {
+	mu      sync.Mutex
+	byEmail map[string]peerConflict
+}
+
+type peerConflict struct {
+	peerIP string
+	detail string
+}
+
+func (c *peerConflicts) replace(conflicts map[string]peerConflict, touched map[string]struct{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if touched == nil {
+		c.byEmail = conflicts
+		return
+	}
+	if c.byEmail == nil {
+		c.byEmail = make(map[string]peerConflict)
+	}
+	for email := range touched {
+		delete(c.byEmail, email)
+	}
+	for email, conflict := range conflicts {
+		c.byEmail[email] = conflict
+	}
+}
+
+func (c *peerConflicts) snapshot() map[string]peerConflict {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := make(map[string]peerConflict, len(c.byEmail))
+	for email, conflict := range c.byEmail {
+		out[email] = conflict
+	}
+	return out
+}
+
+func sortedEmails[V any](m map[string]V) []string {
+	emails := make([]string, 0, len(m))
+	for email := range m {
+		emails = append(emails, email)
+	}
+	sort.Strings(emails)
+	return emails
+}
+
+// ipamStatePath keeps allocations next to the generated configs, one file per interface.
+func ipamStatePath(cfg *config.Config, interfaceName string) string {
+	if cfg == nil || cfg.GeneratedConfigPath == "" {
+		return ""
+	}
+	return filepath.Join(cfg.GeneratedConfigPath, fmt.Sprintf("wireguard_ipam_%s.json", interfaceName))
+}
+
+// retainAllocations releases the addresses of users a full sync no longer
+// puts on this interface. An empty sync is ignored so a panel restart does not
+// reshuffle every address.
+func (wg *WireGuard) retainAllocations(users []*common.User) {
+	if wg.ipam == nil || len(users) == 0 {
+		return
+	}
+	emails := make(map[string]struct{}, len(users))
+	for _, user := range users {
+		if user.GetEmail() != "" && shouldIncludeUserInInterface(user, wg.config.InterfaceName) {
+			emails[user.GetEmail()] = struct{}{}
+		}
+	}
+	wg.ipam.Retain(emails)
+}
+
+// commitAllocations keeps the addresses allocated for peers that made it into
+// the peer store and saves them once for the whole sync.
+func (wg *WireGuard) commitAllocations() {
+	if wg.ipam == nil {
+		return
+	}
+	installed := func(email string) bool { return wg.peerStore.GetByEmail(email) != nil }
+	if err := wg.ipam.Commit(installed); err != nil {
+		log.Printf("wireguard ipam: failed to save allocations: %v", err)
+	}
+}
+
+// GetPeerAllocations returns the addresses of every peer, marking the ones IPAM
+// allocated, and the users that could not be added.
+func (wg *WireGuard) GetPeerAllocations(_ context.Context) (*common.PeerAllocationsResponse, error) {
+	wg.mu.RLock()
+	state := wg.state
+	inbound := wg.config.InterfaceName
+	wg.mu.RUnlock()
+
+	if state != lifecycleRunning {
+		return nil, errWireGuardNotStarted
+	}
+
+	var allocated map[string][]string
+	if wg.ipam != nil {
+		allocated = wg.ipam.Allocations()
+	}
+
+	byEmail := make(map[string]*common.PeerAllocation)
+	for _, peer := range wg.peerStore.GetAll() {
+		ips := make([]string, 0, len(peer.AllowedIPs))
+		for _, ipNet := range peer.AllowedIPs {
+			ips = append(ips, ipNet.String())
+		}
+		_, isAllocated := allocated[peer.Email]
+		byEmail[peer.Email] = &common.PeerAllocation{Email: peer.Email, PeerIps: ips, Allocated: isAllocated, Inbound: inbound}
+	}
+	// Users without an active peer, such as disabled ones, keep their address.
+	for email, ips := range allocated {
+		if _, ok := byEmail[email]; !ok {
+			byEmail[email] = &common.PeerAllocation{Email: email, PeerIps: ips, Allocated: true, Inbound: inbound}
+		}
+	}
+
+	response := &common.PeerAllocationsResponse{}
+	for _, email := range sortedEmails(byEmail) {
+		response.Allocations = append(response.Allocations, byEmail[email])
+	}
+	conflicts := wg.conflicts.snapshot()
+	for _, email := range sortedEmails(conflicts) {
+		response.Conflicts = append(response.Conflicts, &common.PeerConflict{
+			Email:   email,
+			PeerIp:  conflicts[email].peerIP,
+			Detail:  conflicts[email].detail,
+			Inbound: inbound,
+		})
+	}
+	return response, nil
+}
@@ -0,0 +1,121 @@
+package wireguard
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestIPAMAllocatesLowestFreeAddress(t *testing.T) {
+	ipam, err := NewIPAM([]string{"10.70.0.1/24", "fd70::1/64"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := ipam.Allocate("a@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(first, []string{"10.70.0.2/32", "fd70::2/128"}) {
+		t.Fatalf("unexpected first allocation: %v", first)
+	}
+
+	second, err := ipam.Allocate("b@example.com", map[string]string{"10.70.0.3/32": "panel@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(second, []string{"10.70.0.4/32", "fd70::3/128"}) {
+		t.Fatalf("expected panel-assigned address to be skipped, got %v", second)
+	}
+
+	again, _ := ipam.Allocate("a@example.com", nil)
+	if !slices.Equal(again, first) {
+		t.Fatalf("expected a stable allocation, got %v", again)
+	}
+
+	moved, _ := ipam.Allocate("a@example.com", map[string]string{"10.70.0.2/32": "panel@example.com"})
+	if slices.Equal(moved, first) {
+		t.Fatal("expected an allocation clashing with a panel address to move")
+	}
+}
+
+func TestIPAMPersistsAllocations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ipam.json")
+
+	ipam, err := NewIPAM([]string{"10.71.0.1/24"}, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ipam.Allocate("a@example.com", nil); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := ipam.Allocate("b@example.com", nil)
+	if _, err := ipam.Allocate("c@example.com", nil); err != nil {
+		t.Fatal(err)
+	}
+	// c's peer was not installed, so its address is not kept.
+	if err := ipam.Commit(func(email string) bool { return email != "c@example.com" }); err != nil {
+		t.Fatal(err)
+	}
+
+	ipam.Retain(map[string]struct{}{"b@example.com": {}})
+	if err := ipam.Commit(func(string) bool { return true }); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := NewIPAM([]string{"10.71.0.1/24"}, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	allocations := reloaded.Allocations()
+	if len(allocations) != 1 || !slices.Equal(allocations["b@example.com"], want) {
+		t.Fatalf("unexpected reloaded allocations: %v", allocations)
+	}
+
+	// A moved interface subnet invalidates old allocations.
+	moved, err := NewIPAM([]string{"10.72.0.1/24"}, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(moved.Allocations()) != 0 {
+		t.Fatalf("expected allocations outside the pools to be dropped, got %v", moved.Allocations())
+	}
+}
+
+func TestIPAMReportsExhaustedPool(t *testing.T) {
+	ipam, err := NewIPAM([]string{"10.73.0.1/30"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ipam.Allocate("a@example.com", nil); err != nil || !slices.Equal(got, []string{"10.73.0.2/32"}) {
+		t.Fatalf("unexpected allocation %v: %v", got, err)
+	}
+	if _, err := ipam.Allocate("b@example.com", nil); !errors.Is(err, errAddressPoolExhausted) {
+		t.Fatalf("expected pool exhaustion, got %v", err)
+	}
+}
+
+func TestIPAMAvoidsAddressesInsideTakenPrefixes(t *testing.T) {
+	ipam, err := NewIPAM([]string{"10.74.0.1/24"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := ipam.Allocate("a@example.com", nil)
+	if err != nil || !slices.Equal(first, []string{"10.74.0.2/32"}) {
+		t.Fatalf("unexpected first allocation: %v %v", first, err)
+	}
+
+	// A panel-assigned /30 covering the allocation moves it out of the range.
+	taken := map[string]string{"10.74.0.0/30": "panel@example.com"}
+	moved, err := ipam.Allocate("a@example.com", taken)
+	if err != nil || !slices.Equal(moved, []string{"10.74.0.4/32"}) {
+		t.Fatalf("expected the allocation to leave the taken /30, got %v %v", moved, err)
+	}
+
+	second, err := ipam.Allocate("b@example.com", taken)
+	if err != nil || !slices.Equal(second, []string{"10.74.0.5/32"}) {
+		t.Fatalf("expected addresses inside the taken /30 to be skipped, got %v %v", second, err)
+	}
+}
@@ -0,0 +1,215 @@
+//go:build linux
+
+package wireguard
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+const (
+	iptablesNATTable         = "nat"
+	iptablesFilterTable      = "filter"
+	iptablesPostroutingChain = "POSTROUTING"
+	iptablesForwardChain     = "FORWARD"
+)
+
+// iptablesFirewall installs the host routing rules with iptables and
+// ip6tables, for hosts on iptables-legacy or with a firewall manager that
+// owns nftables. Rules go into the built-in nat POSTROUTING and filter
+// FORWARD chains.
+type iptablesFirewall struct {
+	ipv4Cmd string
+	ipv6Cmd string
+	run     func(cmd string, args ...string) ([]byte, error)
+	// used lists the commands that got rules, for cleanup.
+	used []string
+}
+
+func newIptablesFirewall(ipv4Cmd, ipv6Cmd string) *iptablesFirewall {
+	return &iptablesFirewall{ipv4Cmd: ipv4Cmd, ipv6Cmd: ipv6Cmd, run: runIptables}
+}
+
+func (f *iptablesFirewall) String() string { return f.ipv4Cmd }
+
+func (f *iptablesFirewall) command(ipv6 bool) string {
+	cmd := f.ipv4Cmd
+	if ipv6 {
+		cmd = f.ipv6Cmd
+	}
+	if !slices.Contains(f.used, cmd) {
+		f.used = append(f.used, cmd)
+	}
+	return cmd
+}
+
+func (f *iptablesFirewall) ensureMasquerade(ipv6 bool, wgIface, outputIface string, egressOnly bool, ownerID string) error {
+	cmd := f.command(ipv6)
+	if err := f.removeRulesWithCommentPrefix(cmd, iptablesNATTable, iptablesPostroutingChain, nftOwnerCommentPrefix(ownerID)); err != nil {
+		return err
+	}
+
+	args := []string{"-t", iptablesNATTable, "-A", iptablesPostroutingChain}
+	if !egressOnly {
+		args = append(args, "-i", wgIface)
+	}
+	args = append(args,
+		"-o", outputIface,
+		"-m", "comment", "--comment", nftNATRuleComment(ownerID, wgIface, outputIface, egressOnly),
+		"-j", "MASQUERADE",
+	)
+	_, err := f.run(cmd, args...)
+	return err
+}
+
+func (f *iptablesFirewall) ensureForwarding(wgIface string, egress hostEgress, ownerID string) error {
+	for i, outputIface := range []string{egress.ipv4, egress.ipv6} {
+		if outputIface == "" {
+			continue
+		}
+		cmd := f.command(i == 1)
+		if err := f.removeRulesWithCommentPrefix(cmd, iptablesFilterTable, iptablesForwardChain, nftOwnerCommentPrefix(ownerID)); err != nil {
+			return err
+		}
+		for _, outbound := range []bool{false, true} {
+			if _, err := f.run(cmd, iptablesForwardRuleArgs(wgIface, outputIface, ownerID, outbound)...); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// iptablesForwardRuleArgs inserts the rule at the top of FORWARD so it runs
+// before any drop the host already has.
+func iptablesForwardRuleArgs(wgIface, outputIface, ownerID string, outbound bool) []string {
+	args := []string{"-t", iptablesFilterTable, "-I", iptablesForwardChain, "1"}
+	if outbound {
+		args = append(args, "-i", wgIface, "-o", outputIface)
+	} else {
+		args = append(args, "-i", outputIface, "-o", wgIface, "-m", "conntrack", "--ctstate", "RELATED,ESTABLISHED")
+	}
+	return append(args,
+		"-m", "comment", "--comment", nftForwardRuleComment(ownerID, wgIface, outputIface, outbound),
+		"-j", "ACCEPT",
+	)
+}
+
+func (f *iptablesFirewall) cleanup(ownerID string) error {
+	var errs []error
+	for _, cmd := range f.used {
+		for _, chain := range [][2]string{
+			{iptablesNATTable, iptablesPostroutingChain},
+			{iptablesFilterTable, iptablesForwardChain},
+		} {
+			if err := f.removeRulesWithCommentPrefix(cmd, chain[0], chain[1], nftOwnerCommentPrefix(ownerID)); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (f *iptablesFirewall) removeRulesWithCommentPrefix(cmd, table, chain, commentPrefix string) error {
+	out, err := f.run(cmd, "-t", table, "-S", chain)
+	if err != nil {
+		return err
+	}
+
+	rules, err := iptablesRulesWithComment(out, commentPrefix)
+	if err != nil {
+		return fmt.Errorf("%s -t %s -S %s: %w", cmd, table, chain, err)
+	}
+	for _, rule := range rules {
+		if _, err := f.run(cmd, append([]string{"-t", table, "-D"}, rule...)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// iptablesRulesWithComment parses iptables -S output and returns the rules
+// whose comment starts with commentPrefix, without the leading -A, ready to be
+// passed to -D.
+func iptablesRulesWithComment(data []byte, commentPrefix string) ([][]string, error) {
+	rules := make([][]string, 0)
+	for _, line := range strings.Split(string(data), "\n") {
+		if !strings.HasPrefix(line, "-A ") || !strings.Contains(line, commentPrefix) {
+			continue
+		}
+		fields, err := splitIptablesRule(line)
+		if err != nil {
+			return nil, err
+		}
+
+		i := slices.Index(fields, "--comment")
+		if i < 0 || i+1 >= len(fields) || !strings.HasPrefix(fields[i+1], commentPrefix) {
+			continue
+		}
+		rules = append(rules, fields[1:])
+	}
+	return rules, nil
+}
+
+// splitIptablesRule splits a line of iptables -S output into arguments.
+// iptables quotes arguments containing spaces and escapes quotes inside them.
+func splitIptablesRule(line string) ([]string, error) {
+	var (
+		fields  []string
+		current strings.Builder
+		inField bool
+		quoted  bool
+	)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quoted && c == '\\' && i+1 < len(line):
+			i++
+			current.WriteByte(line[i])
+		case c == '"':
+			quoted = !quoted
+			inField = true
+		case !quoted && (c == ' ' || c == '\t'):
+			if inField {
+				fields = append(fields, current.String())
+				current.Reset()
+				inField = false
+			}
+		default:
+			current.WriteByte(c)
+			inField = true
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in %q", line)
+	}
+	if inField {
+		fields = append(fields, current.String())
+	}
+	return fields, nil
+}
+
+func iptablesIsLegacy(version string) bool {
+	return strings.Contains(version, "(legacy)")
+}
+
+func iptablesVersion(cmd string) string {
+	out, err := exec.Command(cmd, "-V").CombinedOutput()
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+// runIptables waits for the xtables lock so it does not fail while another
+// tool is changing rules.
+func runIptables(cmd string, args ...string) ([]byte, error) {
+	out, err := exec.Command(cmd, append([]string{"-w"}, args...)...).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w: %s", cmd, strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return out, nil
+}
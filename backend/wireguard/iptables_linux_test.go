@@ -0,0 +1,115 @@
+//go:build linux
+
+package wireguard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitIptablesRule(t *testing.T) {
+	fields, err := splitIptablesRule(`-A FORWARD -i wg0 -o eth0 -m comment --comment "pg_node_wg owner=o type=\"x\"" -j ACCEPT`)
+	if err != nil {
+		t.Fatalf("splitIptablesRule failed: %v", err)
+	}
+	want := []string{"-A", "FORWARD", "-i", "wg0", "-o", "eth0", "-m", "comment", "--comment", `pg_node_wg owner=o type="x"`, "-j", "ACCEPT"}
+	if strings.Join(fields, "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected fields: %q", fields)
+	}
+
+	if _, err := splitIptablesRule(`-A FORWARD --comment "open`); err == nil {
+		t.Fatal("expected unterminated quote error")
+	}
+}
+
+func TestIptablesRulesWithComment(t *testing.T) {
+	const rules = `-P FORWARD DROP
+-A FORWARD -i eth0 -o wg0 -m conntrack --ctstate RELATED,ESTABLISHED -m comment --comment "pg_node_wg owner=owner-1 type=forward iface=wg0 out=eth0 direction=return" -j ACCEPT
+-A FORWARD -i wg0 -o eth0 -m comment --comment "pg_node_wg owner=owner-1 type=forward iface=wg0 out=eth0 direction=outbound" -j ACCEPT
+-A FORWARD -i wg2 -o eth0 -m comment --comment "pg_node_wg owner=owner-2 type=forward iface=wg2 out=eth0 direction=outbound" -j ACCEPT
+-A FORWARD -m comment --comment "docker pg_node_wg owner=owner-1 " -j DROP
+`
+
+	got, err := iptablesRulesWithComment([]byte(rules), nftOwnerCommentPrefix("owner-1"))
+	if err != nil {
+		t.Fatalf("iptablesRulesWithComment failed: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected the two owner-1 rules, got %q", got)
+	}
+	if got[1][0] != "FORWARD" || got[1][1] != "-i" || got[1][2] != "wg0" {
+		t.Fatalf("expected rule spec without -A, got %q", got[1])
+	}
+}
+
+// fakeIptables records commands and answers -S with the rules it holds.
+type fakeIptables struct {
+	rules map[string][]string // "cmd table" -> -S lines
+	calls []string
+}
+
+func (f *fakeIptables) run(cmd string, args ...string) ([]byte, error) {
+	call := cmd + " " + strings.Join(args, " ")
+	f.calls = append(f.calls, call)
+	if len(args) == 4 && args[2] == "-S" {
+		return []byte(strings.Join(f.rules[cmd+" "+args[1]], "\n")), nil
+	}
+	return nil, nil
+}
+
+func (f *fakeIptables) changes() []string {
+	var changes []string
+	for _, call := range f.calls {
+		if !strings.Contains(call, " -S ") {
+			changes = append(changes, call)
+		}
+	}
+	return changes
+}
+
+func TestIptablesFirewallReplacesOwnRules(t *testing.T) {
+	fake := &fakeIptables{rules: map[string][]string{
+		"iptables filter": {
+			`-A FORWARD -i wg0 -o eth0 -m comment --comment "pg_node_wg owner=owner-1 type=forward iface=wg0 out=eth0 direction=outbound" -j ACCEPT`,
+			`-A FORWARD -i wg1 -o eth0 -m comment --comment "pg_node_wg owner=owner-2 type=forward iface=wg1 out=eth0 direction=outbound" -j ACCEPT`,
+		},
+	}}
+	firewall := newIptablesFirewall("iptables", "ip6tables")
+	firewall.run = fake.run
+
+	if err := firewall.ensureMasquerade(false, "wg0", "eth0", true, "owner-1"); err != nil {
+		t.Fatalf("ensureMasquerade failed: %v", err)
+	}
+	if err := firewall.ensureForwarding("wg0", hostEgress{ipv4: "eth0", ipv6: "eth1"}, "owner-1"); err != nil {
+		t.Fatalf("ensureForwarding failed: %v", err)
+	}
+
+	want := []string{
+		`iptables -t nat -A POSTROUTING -o eth0 -m comment --comment pg_node_wg owner=owner-1 type=nat iface=wg0 out=eth0 scope=egress -j MASQUERADE`,
+		`iptables -t filter -D FORWARD -i wg0 -o eth0 -m comment --comment pg_node_wg owner=owner-1 type=forward iface=wg0 out=eth0 direction=outbound -j ACCEPT`,
+		`iptables -t filter -I FORWARD 1 -i eth0 -o wg0 -m conntrack --ctstate RELATED,ESTABLISHED -m comment --comment pg_node_wg owner=owner-1 type=forward iface=wg0 out=eth0 direction=return -j ACCEPT`,
+		`iptables -t filter -I FORWARD 1 -i wg0 -o eth0 -m comment --comment pg_node_wg owner=owner-1 type=forward iface=wg0 out=eth0 direction=outbound -j ACCEPT`,
+		`ip6tables -t filter -I FORWARD 1 -i eth1 -o wg0 -m conntrack --ctstate RELATED,ESTABLISHED -m comment --comment pg_node_wg owner=owner-1 type=forward iface=wg0 out=eth1 direction=return -j ACCEPT`,
+		`ip6tables -t filter -I FORWARD 1 -i wg0 -o eth1 -m comment --comment pg_node_wg owner=owner-1 type=forward iface=wg0 out=eth1 direction=outbound -j ACCEPT`,
+	}
+	if got := fake.changes(); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected changes:\n%s", strings.Join(got, "\n"))
+	}
+
+	fake.calls = nil
+	if err := firewall.cleanup("owner-1"); err != nil {
+		t.Fatalf("cleanup failed: %v", err)
+	}
+	var listed []string
+	for _, call := range fake.calls {
+		if strings.Contains(call, " -S ") {
+			listed = append(listed, call)
+		}
+	}
+	if len(listed) != 4 {
+		t.Fatalf("expected cleanup to list nat and filter for both commands, got %q", listed)
+	}
+	if got := fake.changes(); len(got) != 1 || !strings.Contains(got[0], "owner=owner-1") {
+		t.Fatalf("expected cleanup to delete only owner-1 rules, got %q", got)
+	}
+}
@@ -58,10 +58,12 @@ func (wg *WireGuard) updateConnectedPeers(ctx context.Context) {
 		return
 	}
 
-	activeHandshakeCutoff := time.Now().Add(-onlineActivityThreshold)
+	now := time.Now()
+	activeHandshakeCutoff := now.Add(-onlineActivityThreshold)
 
 	emailByKey := wg.peerStore.GetEmailMap()
 	samples := make([]stats.Sample, 0, len(device.Peers))
+	observations := make([]peerObservation, 0, len(device.Peers))
 
 	for _, peer := range device.Peers {
 		select {
@@ -70,6 +72,19 @@ func (wg *WireGuard) updateConnectedPeers(ctx context.Context) {
 		default:
 		}
 
+		if email, ok := emailByKey[peer.PublicKey.String()]; ok && !peer.LastHandshakeTime.IsZero() {
+			observation := peerObservation{
+				publicKey:     peer.PublicKey.String(),
+				email:         email,
+				lastHandshake: peer.LastHandshakeTime,
+				rxBytes:       peer.ReceiveBytes,
+			}
+			if peer.Endpoint != nil {
+				observation.endpoint = peer.Endpoint.String()
+			}
+			observations = append(observations, observation)
+		}
+
 		if peer.LastHandshakeTime.IsZero() {
 			continue // never connected
 		}
@@ -98,6 +113,9 @@ func (wg *WireGuard) updateConnectedPeers(ctx context.Context) {
 	}
 
 	wg.statsTracker.UpdateStatsBatch(samples)
+	if wg.sessions != nil {
+		wg.sessions.observe(now, observations)
+	}
 }
 
 func (wg *WireGuard) logStatsDeviceReadError(err error) {
@@ -3,6 +3,9 @@ package wireguard
 import (
 	"errors"
 	"fmt"
+	"io"
+	"log"
+	"slices"
 	"sync"
 	"syscall"
 
@@ -23,7 +26,11 @@ type netlinkOps interface {
 	LinkByName(string) (netlink.Link, error)
 	AddrAdd(netlink.Link, *netlink.Addr) error
 	LinkSetUp(netlink.Link) error
+	LinkSetMTU(netlink.Link, int) error
 	LinkDel(netlink.Link) error
+	RuleList(family int) ([]netlink.Rule, error)
+	RuleAdd(*netlink.Rule) error
+	RuleDel(*netlink.Rule) error
 }
 
 type defaultNetlinkOps struct{}
@@ -48,6 +55,10 @@ func (defaultNetlinkOps) LinkSetUp(link netlink.Link) error {
 	return netlink.LinkSetUp(link)
 }
 
+func (defaultNetlinkOps) LinkSetMTU(link netlink.Link, mtu int) error {
+	return netlink.LinkSetMTU(link, mtu)
+}
+
 func (defaultNetlinkOps) LinkDel(link netlink.Link) error {
 	return netlink.LinkDel(link)
 }
@@ -72,13 +83,34 @@ func wrapPermissionDeniedError(action string, err error) error {
 	return err
 }
 
+// Interface implementations selectable through the config "mode" field.
+const (
+	// ModeAuto uses the kernel module and falls back to userspace when the link cannot be created.
+	ModeAuto      = "auto"
+	ModeKernel    = "kernel"
+	ModeUserspace = "userspace"
+)
+
+// userspaceFactory starts a wireguard-go device on a TUN interface named
+// interfaceName. wgctrl reaches it through its UAPI socket.
+type userspaceFactory func(interfaceName string) (io.Closer, error)
+
 // Manager handles WireGuard interface management using wgctrl
 type Manager struct {
-	client    wgClient
-	iFaceName string
-	nl        netlinkOps
-	configure configureDeviceFunc
-	mu        sync.RWMutex
+	client       wgClient
+	iFaceName    string
+	nl           netlinkOps
+	configure    configureDeviceFunc
+	mode         string
+	newUserspace userspaceFactory
+	userspace    io.Closer
+	// mtu, fwMark and table are applied by InitializeWithPeers when non-zero.
+	mtu    int
+	fwMark int
+	table  int
+	// rules are the policy routing rules to delete on Close.
+	rules []*netlink.Rule
+	mu    sync.RWMutex
 }
 
 // NewManager creates a new WireGuard manager
@@ -89,13 +121,21 @@ func NewManager(interfaceName string) (*Manager, error) {
 	}
 
 	return &Manager{
-		client:    client,
-		iFaceName: interfaceName,
-		nl:        defaultNetlinkOps{},
-		configure: defaultConfigureDevice,
+		client:       client,
+		iFaceName:    interfaceName,
+		nl:           defaultNetlinkOps{},
+		configure:    defaultConfigureDevice,
+		newUserspace: startUserspaceDevice,
 	}, nil
 }
 
+// Userspace reports whether the interface runs on wireguard-go instead of the kernel module.
+func (m *Manager) Userspace() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.userspace != nil
+}
+
 func (m *Manager) getNetlinkOps() netlinkOps {
 	if m.nl == nil {
 		return defaultNetlinkOps{}
@@ -110,11 +150,14 @@ func (m *Manager) getConfigureDevice() configureDeviceFunc {
 	return m.configure
 }
 
-func buildInitialWGConfig(privateKey wgtypes.Key, listenPort int, peers []wgtypes.PeerConfig) wgtypes.Config {
+func buildInitialWGConfig(privateKey wgtypes.Key, listenPort, fwMark int, peers []wgtypes.PeerConfig) wgtypes.Config {
 	config := wgtypes.Config{
 		PrivateKey: &privateKey,
 		ListenPort: &listenPort,
 	}
+	if fwMark != 0 {
+		config.FirewallMark = &fwMark
+	}
 
 	if len(peers) > 0 {
 		config.Peers = peers
@@ -151,19 +194,20 @@ func (m *Manager) InitializeWithPeers(privateKey wgtypes.Key, listenPort int, se
 	}
 
 	// Create WireGuard interface
-	link := &netlink.Wireguard{LinkAttrs: netlink.LinkAttrs{Name: m.iFaceName}}
-	if err := nl.LinkAdd(link); err != nil {
-		return fmt.Errorf("failed to add link: %w", wrapPermissionDeniedError("creating wireguard interface", err))
+	if err := m.createLinkLocked(); err != nil {
+		return err
 	}
 	cleanupOnError := true
 	defer func() {
 		if cleanupOnError {
+			_ = m.removeRulesLocked()
+			_ = m.closeUserspaceLocked()
 			_ = m.cleanupExistingInterface()
 		}
 	}()
 
 	// Configure WireGuard (single call for base settings + optional peers snapshot).
-	config := buildInitialWGConfig(privateKey, listenPort, peers)
+	config := buildInitialWGConfig(privateKey, listenPort, m.fwMark, peers)
 
 	if err := configure(m.client, m.iFaceName, config); err != nil {
 		return fmt.Errorf("failed to configure device: %w", wrapPermissionDeniedError("configuring wireguard device", err))
@@ -184,15 +228,146 @@ func (m *Manager) InitializeWithPeers(privateKey wgtypes.Key, listenPort int, se
 		}
 	}
 
+	if m.mtu > 0 {
+		if err := nl.LinkSetMTU(link2, m.mtu); err != nil {
+			return fmt.Errorf("failed to set mtu %d: %w", m.mtu, wrapPermissionDeniedError("setting wireguard interface mtu", err))
+		}
+	}
+
 	// Bring interface up
 	if err := nl.LinkSetUp(link2); err != nil {
 		return fmt.Errorf("failed to bring up interface: %w", wrapPermissionDeniedError("bringing wireguard interface up", err))
 	}
 
+	if err := m.addRulesLocked(); err != nil {
+		return err
+	}
+
 	cleanupOnError = false
 	return nil
 }
 
+// createLinkLocked creates the interface with the kernel module or wireguard-go, depending on the mode.
+func (m *Manager) createLinkLocked() error {
+	if m.mode == ModeUserspace {
+		return m.startUserspaceLocked()
+	}
+
+	link := &netlink.Wireguard{LinkAttrs: netlink.LinkAttrs{Name: m.iFaceName}}
+	err := m.getNetlinkOps().LinkAdd(link)
+	if err == nil {
+		return nil
+	}
+	kernelErr := fmt.Errorf("failed to add link: %w", wrapPermissionDeniedError("creating wireguard interface", err))
+	if m.mode == ModeKernel {
+		return kernelErr
+	}
+
+	log.Printf("wireguard: kernel interface %s unavailable (%v), falling back to userspace", m.iFaceName, err)
+	if err := m.startUserspaceLocked(); err != nil {
+		return errors.Join(kernelErr, err)
+	}
+	return nil
+}
+
+func (m *Manager) startUserspaceLocked() error {
+	if m.newUserspace == nil {
+		return fmt.Errorf("userspace wireguard is not available")
+	}
+	device, err := m.newUserspace(m.iFaceName)
+	if err != nil {
+		return fmt.Errorf("failed to start userspace wireguard: %w", wrapPermissionDeniedError("creating tun device", err))
+	}
+	m.userspace = device
+	return nil
+}
+
+func (m *Manager) closeUserspaceLocked() error {
+	if m.userspace == nil {
+		return nil
+	}
+	err := m.userspace.Close()
+	m.userspace = nil
+	return err
+}
+
+// addRulesLocked routes the packets carrying fwMark through the dedicated
+// table, for both address families.
+func (m *Manager) addRulesLocked() error {
+	if m.table == 0 || m.fwMark == 0 {
+		return nil
+	}
+	added, err := addPolicyRules(m.getNetlinkOps(), markRules(uint32(m.fwMark), m.table))
+	if err != nil {
+		return fmt.Errorf("failed to add rule for fwmark %d: %w", m.fwMark, wrapPermissionDeniedError("adding routing rule", err))
+	}
+	m.rules = added
+	return nil
+}
+
+func (m *Manager) removeRulesLocked() error {
+	err := deletePolicyRules(m.getNetlinkOps(), m.rules)
+	m.rules = nil
+	return err
+}
+
+// markRules send the packets carrying mark through table, one rule per
+// address family.
+func markRules(mark uint32, table int) []*netlink.Rule {
+	rules := make([]*netlink.Rule, 0, 2)
+	for _, family := range []int{syscall.AF_INET, syscall.AF_INET6} {
+		rule := netlink.NewRule()
+		rule.Family = family
+		rule.Mark = mark
+		rule.Table = table
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// addPolicyRules adds the rules the host does not have yet and returns the
+// ones it added. A rule already present, whether an admin's or a leftover of
+// an unclean exit, is left alone, so deleting the returned rules never removes
+// someone else's. Hosts without IPv6 cannot have IPv6 rules, and need none.
+func addPolicyRules(nl netlinkOps, rules []*netlink.Rule) ([]*netlink.Rule, error) {
+	var added []*netlink.Rule
+	for _, rule := range rules {
+		existing, err := nl.RuleList(rule.Family)
+		if err == nil && slices.ContainsFunc(existing, func(other netlink.Rule) bool { return samePolicyRule(&other, rule) }) {
+			continue
+		}
+		if err == nil {
+			err = nl.RuleAdd(rule)
+		}
+		if err != nil {
+			if rule.Family == syscall.AF_INET6 && errors.Is(err, syscall.EAFNOSUPPORT) {
+				continue
+			}
+			_ = deletePolicyRules(nl, added)
+			return nil, err
+		}
+		added = append(added, rule)
+	}
+	return added, nil
+}
+
+func deletePolicyRules(nl netlinkOps, rules []*netlink.Rule) error {
+	var errs []error
+	for _, rule := range rules {
+		if err := nl.RuleDel(rule); err != nil && !errors.Is(err, syscall.ENOENT) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// samePolicyRule reports whether existing sends the same packets to the same
+// table as rule, which only selects on the mark.
+func samePolicyRule(existing, rule *netlink.Rule) bool {
+	return existing.Table == rule.Table && existing.Mark == rule.Mark && !existing.Invert &&
+		existing.Src == nil && existing.Dst == nil && existing.IifName == "" && existing.OifName == ""
+}
+
 // ApplyPeers applies a batch of peer configurations in a single kernel call.
 func (m *Manager) ApplyPeers(peers []wgtypes.PeerConfig) error {
 	if len(peers) == 0 {
@@ -266,6 +441,15 @@ func (m *Manager) Close() error {
 		}
 	}
 
+	if err := m.removeRulesLocked(); err != nil {
+		errs = append(errs, fmt.Errorf("rule delete: %w", err))
+	}
+
+	// Stop wireguard-go first; closing its TUN device removes the interface.
+	if err := m.closeUserspaceLocked(); err != nil {
+		errs = append(errs, fmt.Errorf("userspace close: %w", err))
+	}
+
 	// Remove interface
 	if err := m.cleanupExistingInterface(); err != nil {
 		errs = append(errs, err)
@@ -0,0 +1,15 @@
+package wireguard
+
+import "github.com/vishvananda/netlink"
+
+func (defaultNetlinkOps) RuleList(family int) ([]netlink.Rule, error) {
+	return netlink.RuleList(family)
+}
+
+func (defaultNetlinkOps) RuleAdd(rule *netlink.Rule) error {
+	return netlink.RuleAdd(rule)
+}
+
+func (defaultNetlinkOps) RuleDel(rule *netlink.Rule) error {
+	return netlink.RuleDel(rule)
+}
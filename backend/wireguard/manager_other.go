@@ -0,0 +1,23 @@
+//go:build !linux
+
+package wireguard
+
+import (
+	"errors"
+
+	"github.com/vishvananda/netlink"
+)
+
+var errRulesUnsupported = errors.New("policy routing rules are only supported on linux")
+
+func (defaultNetlinkOps) RuleList(int) ([]netlink.Rule, error) {
+	return nil, errRulesUnsupported
+}
+
+func (defaultNetlinkOps) RuleAdd(*netlink.Rule) error {
+	return errRulesUnsupported
+}
+
+func (defaultNetlinkOps) RuleDel(*netlink.Rule) error {
+	return errRulesUnsupported
+}
@@ -2,8 +2,10 @@ package wireguard
 
 import (
 	"errors"
+	"io"
 	"net"
 	"strings"
+	"syscall"
 	"testing"
 
 	"github.com/vishvananda/netlink"
@@ -48,7 +50,11 @@ type mockNetlinkOps struct {
 	linkByName  func(string) (netlink.Link, error)
 	addrAddFn   func(netlink.Link, *netlink.Addr) error
 	linkSetUpFn func(netlink.Link) error
+	linkMTUFn   func(netlink.Link, int) error
 	linkDelFn   func(netlink.Link) error
+	ruleListFn  func(int) ([]netlink.Rule, error)
+	ruleAddFn   func(*netlink.Rule) error
+	ruleDelFn   func(*netlink.Rule) error
 }
 
 func (m mockNetlinkOps) ParseAddr(address string) (*netlink.Addr, error) {
@@ -86,6 +92,34 @@ func (m mockNetlinkOps) LinkSetUp(link netlink.Link) error {
 	return m.linkSetUpFn(link)
 }
 
+func (m mockNetlinkOps) LinkSetMTU(link netlink.Link, mtu int) error {
+	if m.linkMTUFn == nil {
+		return errors.New("LinkSetMTU was not mocked")
+	}
+	return m.linkMTUFn(link, mtu)
+}
+
+func (m mockNetlinkOps) RuleList(family int) ([]netlink.Rule, error) {
+	if m.ruleListFn == nil {
+		return nil, errors.New("RuleList was not mocked")
+	}
+	return m.ruleListFn(family)
+}
+
+func (m mockNetlinkOps) RuleAdd(rule *netlink.Rule) error {
+	if m.ruleAddFn == nil {
+		return errors.New("RuleAdd was not mocked")
+	}
+	return m.ruleAddFn(rule)
+}
+
+func (m mockNetlinkOps) RuleDel(rule *netlink.Rule) error {
+	if m.ruleDelFn == nil {
+		return errors.New("RuleDel was not mocked")
+	}
+	return m.ruleDelFn(rule)
+}
+
 func (m mockNetlinkOps) LinkDel(link netlink.Link) error {
 	if m.linkDelFn == nil {
 		return errors.New("LinkDel was not mocked")
@@ -269,6 +303,120 @@ func TestManagerInitializeWithPeersConfiguresReplacePeers(t *testing.T) {
 	}
 }
 
+func TestManagerInitializeAppliesMTUFwMarkAndTable(t *testing.T) {
+	var (
+		mtu          int
+		fwMark       *int
+		rules        []netlink.Rule
+		deletedRules int
+	)
+	mock := mockNetlinkOps{
+		parseAddrFn: func(_ string) (*netlink.Addr, error) {
+			return &netlink.Addr{}, nil
+		},
+		linkAddFn: func(_ netlink.Link) error { return nil },
+		linkByName: func(name string) (netlink.Link, error) {
+			return &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: name}}, nil
+		},
+		addrAddFn:   func(_ netlink.Link, _ *netlink.Addr) error { return nil },
+		linkSetUpFn: func(_ netlink.Link) error { return nil },
+		linkDelFn:   func(_ netlink.Link) error { return nil },
+		linkMTUFn: func(_ netlink.Link, value int) error {
+			mtu = value
+			return nil
+		},
+		ruleListFn: func(_ int) ([]netlink.Rule, error) { return nil, nil },
+		ruleAddFn: func(rule *netlink.Rule) error {
+			if rule.Family == syscall.AF_INET6 {
+				return syscall.EAFNOSUPPORT
+			}
+			rules = append(rules, *rule)
+			return nil
+		},
+		ruleDelFn: func(_ *netlink.Rule) error {
+			deletedRules++
+			return syscall.ENOENT
+		},
+	}
+
+	manager := &Manager{
+		iFaceName: "wg-test",
+		client:    &fakeWGClient{},
+		nl:        mock,
+		mtu:       1380,
+		fwMark:    51820,
+		table:     200,
+		configure: func(_ wgClient, _ string, cfg wgtypes.Config) error {
+			fwMark = cfg.FirewallMark
+			return nil
+		},
+	}
+	if err := manager.InitializeWithPeers(wgtypes.Key{}, 51820, []string{"10.0.0.1/24"}, nil); err != nil {
+		t.Fatalf("unexpected initialize error: %v", err)
+	}
+
+	if mtu != 1380 {
+		t.Fatalf("expected mtu 1380, got %d", mtu)
+	}
+	if fwMark == nil || *fwMark != 51820 {
+		t.Fatalf("expected fwmark 51820, got %v", fwMark)
+	}
+	if len(rules) != 1 || rules[0].Mark != 51820 || rules[0].Table != 200 || rules[0].Family != syscall.AF_INET {
+		t.Fatalf("unexpected rules: %+v", rules)
+	}
+
+	deletedRules = 0
+	if err := manager.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+	if deletedRules != 1 {
+		t.Fatalf("expected the rule to be deleted on close, got %d deletions", deletedRules)
+	}
+}
+
+func TestManagerLeavesExistingRuleAlone(t *testing.T) {
+	var added, deleted []netlink.Rule
+	manager := &Manager{
+		iFaceName: "wg-test",
+		fwMark:    51820,
+		table:     200,
+		nl: mockNetlinkOps{
+			ruleListFn: func(family int) ([]netlink.Rule, error) {
+				if family == syscall.AF_INET {
+					// An admin already routes the mark through the table.
+					return []netlink.Rule{{Mark: 51820, Table: 200, Priority: 100}}, nil
+				}
+				return nil, nil
+			},
+			ruleAddFn: func(rule *netlink.Rule) error {
+				added = append(added, *rule)
+				return nil
+			},
+			ruleDelFn: func(rule *netlink.Rule) error {
+				deleted = append(deleted, *rule)
+				return nil
+			},
+		},
+	}
+
+	if err := manager.addRulesLocked(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(added) != 1 || added[0].Family != syscall.AF_INET6 {
+		t.Fatalf("expected only the missing IPv6 rule to be added, got %+v", added)
+	}
+	if len(deleted) != 0 {
+		t.Fatalf("expected no rule to be deleted before adding, got %+v", deleted)
+	}
+
+	if err := manager.removeRulesLocked(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0].Family != syscall.AF_INET6 {
+		t.Fatalf("expected only the added rule to be deleted, got %+v", deleted)
+	}
+}
+
 func TestManagerCleanupExistingInterfaceReturnsLookupError(t *testing.T) {
 	manager := &Manager{
 		iFaceName: "wg-test",
@@ -367,3 +515,98 @@ func TestManagerApplyPeersReplaceAllNilClient(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+type fakeUserspaceDevice struct {
+	closed int
+}
+
+func (d *fakeUserspaceDevice) Close() error {
+	d.closed++
+	return nil
+}
+
+func newUserspaceTestManager(mode string, linkAddErr error, device *fakeUserspaceDevice) (*Manager, *int) {
+	linkAddCalls := 0
+	mock := mockNetlinkOps{
+		parseAddrFn: func(_ string) (*netlink.Addr, error) {
+			return &netlink.Addr{}, nil
+		},
+		linkAddFn: func(_ netlink.Link) error {
+			linkAddCalls++
+			return linkAddErr
+		},
+		linkByName: func(name string) (netlink.Link, error) {
+			if device.closed > 0 {
+				return nil, linkNotFoundError()
+			}
+			return &netlink.Tuntap{LinkAttrs: netlink.LinkAttrs{Name: name}}, nil
+		},
+		addrAddFn: func(_ netlink.Link, _ *netlink.Addr) error {
+			return nil
+		},
+		linkSetUpFn: func(_ netlink.Link) error {
+			return nil
+		},
+		linkDelFn: func(_ netlink.Link) error {
+			return nil
+		},
+	}
+
+	return &Manager{
+		iFaceName: "wg-test",
+		client:    &fakeWGClient{},
+		nl:        mock,
+		mode:      mode,
+		newUserspace: func(_ string) (io.Closer, error) {
+			device.closed = 0
+			return device, nil
+		},
+	}, &linkAddCalls
+}
+
+func TestManagerInitializeFallsBackToUserspace(t *testing.T) {
+	device := &fakeUserspaceDevice{}
+	manager, _ := newUserspaceTestManager(ModeAuto, errors.New("operation not supported"), device)
+
+	if err := manager.InitializeWithPeers(wgtypes.Key{}, 51820, []string{"10.0.0.1/24"}, nil); err != nil {
+		t.Fatalf("expected userspace fallback, got %v", err)
+	}
+	if !manager.Userspace() {
+		t.Fatal("expected manager to report userspace mode")
+	}
+
+	if err := manager.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	if device.closed != 1 {
+		t.Fatalf("expected userspace device to be closed once, got %d", device.closed)
+	}
+}
+
+func TestManagerInitializeKernelModeDoesNotFallBack(t *testing.T) {
+	device := &fakeUserspaceDevice{}
+	manager, _ := newUserspaceTestManager(ModeKernel, errors.New("operation not supported"), device)
+
+	err := manager.InitializeWithPeers(wgtypes.Key{}, 51820, []string{"10.0.0.1/24"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "failed to add link") {
+		t.Fatalf("expected link error, got %v", err)
+	}
+	if manager.Userspace() {
+		t.Fatal("kernel mode must not start wireguard-go")
+	}
+}
+
+func TestManagerInitializeUserspaceModeSkipsKernel(t *testing.T) {
+	device := &fakeUserspaceDevice{}
+	manager, linkAddCalls := newUserspaceTestManager(ModeUserspace, nil, device)
+
+	if err := manager.InitializeWithPeers(wgtypes.Key{}, 51820, []string{"10.0.0.1/24"}, nil); err != nil {
+		t.Fatalf("initialize failed: %v", err)
+	}
+	if *linkAddCalls != 0 {
+		t.Fatalf("expected no kernel link, got %d LinkAdd calls", *linkAddCalls)
+	}
+	if !manager.Userspace() {
+		t.Fatal("expected manager to report userspace mode")
+	}
+}
@@ -0,0 +1,109 @@
+package wireguard
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ObfuscationAmneziaWG is reported in GetBaseInfo while obfuscation is on.
+const ObfuscationAmneziaWG = "amneziawg"
+
+// ObfuscationConfig holds the AmneziaWG junk packet and header parameters.
+// Clients need the same values, so they are written into generated configs.
+type ObfuscationConfig struct {
+	// Jc junk packets of Jmin to Jmax bytes are sent before each handshake.
+	Jc   int `json:"jc,omitempty"`
+	Jmin int `json:"jmin,omitempty"`
+	Jmax int `json:"jmax,omitempty"`
+	// S1 and S2 pad handshake initiation and response packets.
+	S1 int `json:"s1,omitempty"`
+	S2 int `json:"s2,omitempty"`
+	// H1 to H4 replace the message type headers; 0 keeps the standard one.
+	H1 uint32 `json:"h1,omitempty"`
+	H2 uint32 `json:"h2,omitempty"`
+	H3 uint32 `json:"h3,omitempty"`
+	H4 uint32 `json:"h4,omitempty"`
+}
+
+func (o *ObfuscationConfig) enabled() bool {
+	return o != nil && *o != ObfuscationConfig{}
+}
+
+// validate applies the limits of the AmneziaWG protocol.
+func (o *ObfuscationConfig) validate() error {
+	if !o.enabled() {
+		return nil
+	}
+	if o.Jc < 0 || o.Jc > 128 {
+		return fmt.Errorf("jc must be between 0 and 128")
+	}
+	if o.Jc > 0 && (o.Jmin < 0 || o.Jmin > o.Jmax || o.Jmax > 1280) {
+		return fmt.Errorf("jmin and jmax must satisfy 0 <= jmin <= jmax <= 1280")
+	}
+	if o.S1 < 0 || o.S1 > 1132 {
+		return fmt.Errorf("s1 must be between 0 and 1132")
+	}
+	if o.S2 < 0 || o.S2 > 1188 {
+		return fmt.Errorf("s2 must be between 0 and 1188")
+	}
+	// Padded initiation and response packets would otherwise have the same size.
+	if o.S1+56 == o.S2 {
+		return fmt.Errorf("s1 + 56 must not equal s2")
+	}
+
+	seen := make(map[uint32]bool, 4)
+	for i, header := range []uint32{o.H1, o.H2, o.H3, o.H4} {
+		if header == 0 {
+			continue
+		}
+		if header <= 4 {
+			return fmt.Errorf("h%d must be 0 or greater than 4", i+1)
+		}
+		if seen[header] {
+			return fmt.Errorf("h1 to h4 must be distinct")
+		}
+		seen[header] = true
+	}
+	return nil
+}
+
+// fields returns the parameters as key/value pairs in wg-quick order,
+// skipping the ones left at their default.
+func (o *ObfuscationConfig) fields() [][2]string {
+	if !o.enabled() {
+		return nil
+	}
+	var fields [][2]string
+	for _, field := range []struct {
+		name  string
+		value uint64
+	}{
+		{"Jc", uint64(o.Jc)}, {"Jmin", uint64(o.Jmin)}, {"Jmax", uint64(o.Jmax)},
+		{"S1", uint64(o.S1)}, {"S2", uint64(o.S2)},
+		{"H1", uint64(o.H1)}, {"H2", uint64(o.H2)}, {"H3", uint64(o.H3)}, {"H4", uint64(o.H4)},
+	} {
+		if field.value != 0 {
+			fields = append(fields, [2]string{field.name, fmt.Sprint(field.value)})
+		}
+	}
+	return fields
+}
+
+// uapi renders the parameters as an AmneziaWG UAPI set operation.
+func (o *ObfuscationConfig) uapi() string {
+	var b strings.Builder
+	for _, field := range o.fields() {
+		fmt.Fprintf(&b, "%s=%s\n", strings.ToLower(field[0]), field[1])
+	}
+	return b.String()
+}
+
+// Obfuscation reports ObfuscationAmneziaWG when the interface obfuscates its traffic.
+func (wg *WireGuard) Obfuscation() string {
+	wg.mu.RLock()
+	defer wg.mu.RUnlock()
+	if wg.config == nil || !wg.config.Obfuscation.enabled() {
+		return ""
+	}
+	return ObfuscationAmneziaWG
+}
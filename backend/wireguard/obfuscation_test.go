@@ -0,0 +1,88 @@
+package wireguard
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pasarguard/node/common"
+)
+
+func TestObfuscationValidate(t *testing.T) {
+	valid := ObfuscationConfig{Jc: 4, Jmin: 40, Jmax: 70, S1: 15, S2: 92, H1: 1010, H2: 2020, H3: 3030, H4: 4040}
+	if err := valid.validate(); err != nil {
+		t.Fatalf("expected valid obfuscation, got: %v", err)
+	}
+
+	for name, mutate := range map[string]func(*ObfuscationConfig){
+		"jc too large":        func(o *ObfuscationConfig) { o.Jc = 129 },
+		"jmin above jmax":     func(o *ObfuscationConfig) { o.Jmin = 80 },
+		"jmax too large":      func(o *ObfuscationConfig) { o.Jmax = 1281 },
+		"s1 too large":        func(o *ObfuscationConfig) { o.S1 = 1133 },
+		"s2 equals s1 + 56":   func(o *ObfuscationConfig) { o.S2 = o.S1 + 56 },
+		"standard header":     func(o *ObfuscationConfig) { o.H2 = 2 },
+		"duplicate header":    func(o *ObfuscationConfig) { o.H4 = o.H1 },
+		"negative padding s2": func(o *ObfuscationConfig) { o.S2 = -1 },
+	} {
+		t.Run(name, func(t *testing.T) {
+			obfuscation := valid
+			mutate(&obfuscation)
+			if err := obfuscation.validate(); err == nil {
+				t.Fatal("expected validation error")
+			}
+		})
+	}
+}
+
+func TestObfuscationUAPISkipsDefaults(t *testing.T) {
+	obfuscation := &ObfuscationConfig{Jc: 3, Jmin: 10, Jmax: 50, H1: 77}
+	if got, want := obfuscation.uapi(), "jc=3\njmin=10\njmax=50\nh1=77\n"; got != want {
+		t.Fatalf("unexpected uapi %q, want %q", got, want)
+	}
+	if (*ObfuscationConfig)(nil).uapi() != "" || (&ObfuscationConfig{}).enabled() {
+		t.Fatal("expected an empty obfuscation to be disabled")
+	}
+}
+
+func TestNewConfigObfuscation(t *testing.T) {
+	cfg, err := NewConfig(`{"obfuscation":{"jc":4,"jmin":40,"jmax":70,"s1":15,"s2":92}}`)
+	if err != nil {
+		t.Fatalf("NewConfig failed: %v", err)
+	}
+	if cfg.Obfuscation.Jc != 4 || cfg.Obfuscation.S2 != 92 {
+		t.Fatalf("unexpected obfuscation: %+v", cfg.Obfuscation)
+	}
+
+	if _, err := NewConfig(`{"mode":"kernel","obfuscation":{"jc":4,"jmin":40,"jmax":70}}`); err == nil {
+		t.Fatal("expected kernel mode with obfuscation to be rejected")
+	}
+	if _, err := NewConfig(`{"obfuscation":{"s1":10,"s2":66}}`); err == nil {
+		t.Fatal("expected invalid obfuscation to be rejected")
+	}
+}
+
+func TestObfuscationInClientConfigAndBaseInfo(t *testing.T) {
+	wg, _ := newClientConfigTestBackend(t)
+	if wg.Obfuscation() != "" {
+		t.Fatalf("expected no obfuscation, got %q", wg.Obfuscation())
+	}
+
+	wg.config.Obfuscation = &ObfuscationConfig{Jc: 4, Jmin: 40, Jmax: 70, S1: 15, S2: 92, H1: 1010, H2: 2020, H3: 3030, H4: 4040}
+	if wg.Obfuscation() != ObfuscationAmneziaWG {
+		t.Fatalf("expected %q, got %q", ObfuscationAmneziaWG, wg.Obfuscation())
+	}
+
+	response, err := wg.GetClientConfig(context.Background(), &common.ClientConfigRequest{
+		Email:    "user@example.com",
+		Endpoint: "vpn.example.com",
+	})
+	if err != nil {
+		t.Fatalf("GetClientConfig failed: %v", err)
+	}
+	iface, _, _ := strings.Cut(response.GetConfig(), "[Peer]")
+	for _, line := range []string{"Jc = 4", "Jmin = 40", "Jmax = 70", "S1 = 15", "S2 = 92", "H1 = 1010", "H4 = 4040"} {
+		if !strings.Contains(iface, line+"\n") {
+			t.Fatalf("expected interface section to contain %q, got:\n%s", line, response.GetConfig())
+		}
+	}
+}
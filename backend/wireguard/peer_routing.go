@@ -0,0 +1,78 @@
+package wireguard
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+)
+
+// PeerOutboundConfig sends the forwarded traffic of peers through a routing
+// table, for example one whose default route leaves through a residential
+// uplink. Packets are marked with Mark, which defaults to the table number,
+// and masqueraded on the way out.
+type PeerOutboundConfig struct {
+	Table int    `json:"table"`
+	Mark  uint32 `json:"mark,omitempty"`
+}
+
+// peerRouter keeps the per-peer outbound routing in sync with the peer store.
+type peerRouter interface {
+	// Sync routes each peer with an outbound tag through that outbound.
+	// Peers without one, or with an unknown one, use the default route.
+	Sync(peers []*PeerInfo) error
+	// Close removes every rule this instance installed.
+	Close() error
+}
+
+// validateOutbounds fills in the marks and rejects marks and tables clashing
+// with each other or with the interface routing.
+func (c *Config) validateOutbounds() error {
+	if len(c.Outbounds) == 0 {
+		return nil
+	}
+	if c.XrayTProxy != nil {
+		return errors.New("outbounds cannot be combined with xray_tproxy, route users in xray instead")
+	}
+
+	marks := make(map[uint32]string, len(c.Outbounds))
+	for tag, outbound := range c.Outbounds {
+		if outbound == nil {
+			return fmt.Errorf("outbound %q: table is required", tag)
+		}
+		// 253 to 255 are the default, main and local tables.
+		if outbound.Table <= 0 || (outbound.Table >= 253 && outbound.Table <= 255) {
+			return fmt.Errorf("outbound %q: invalid routing table %d", tag, outbound.Table)
+		}
+		if outbound.Mark == 0 {
+			outbound.Mark = uint32(outbound.Table)
+		}
+		if outbound.Mark > math.MaxInt32 {
+			return fmt.Errorf("outbound %q: mark must not exceed %d", tag, math.MaxInt32)
+		}
+		if outbound.Mark == c.FwMark || (c.Table != 0 && outbound.Table == c.Table) {
+			return fmt.Errorf("outbound %q: mark and table must differ from the interface fwmark and table", tag)
+		}
+		if other, ok := marks[outbound.Mark]; ok {
+			return fmt.Errorf("outbounds %q and %q share mark %d", other, tag, outbound.Mark)
+		}
+		marks[outbound.Mark] = tag
+	}
+	return nil
+}
+
+// syncPeerRoutes applies the outbound routing of the current peer store. Peers
+// are already committed at this point, so a failure is logged instead of failing the sync.
+func (wg *WireGuard) syncPeerRoutes() {
+	wg.mu.RLock()
+	router := wg.peerRouter
+	wg.mu.RUnlock()
+
+	if router == nil {
+		return
+	}
+	if err := router.Sync(wg.peerStore.GetAll()); err != nil {
+		log.Printf("wireguard peer outbounds: %v", err)
+		wg.emitErrorLogf("failed to apply peer outbounds: %v", err)
+	}
+}
@@ -0,0 +1,245 @@
+//go:build linux
+
+package wireguard
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	nftRouteTableFamily = "inet"
+	nftRouteTableName   = "pg_node_wg_route"
+	nftRouteMarkChain   = "prerouting"
+	nftRouteNATChain    = "postrouting"
+)
+
+// peerOutboundRules are the policy routing rules of each mark and table pair,
+// kept until the last interface using them shuts down.
+var peerOutboundRules = newSharedHostState[[2]int]()
+
+// nftPeerRouter marks the forwarded traffic of peers with their outbound's
+// mark, which a policy routing rule sends through the outbound's table.
+// Nothing is installed until a peer uses an outbound.
+type nftPeerRouter struct {
+	mu        sync.Mutex
+	iface     string
+	outbounds map[string]*PeerOutboundConfig
+	networks  []*net.IPNet
+	ownerID   string
+	acquired  [][2]int
+	peers     *peerRuleSet
+	closed    bool
+}
+
+func newPeerRouter(iface string, outbounds map[string]*PeerOutboundConfig, networks []*net.IPNet) peerRouter {
+	return &nftPeerRouter{
+		iface:     iface,
+		outbounds: outbounds,
+		networks:  networks,
+		peers: newPeerRuleSet(
+			nftBaseChain{family: nftRouteTableFamily, table: nftRouteTableName, name: nftRouteMarkChain},
+			samePeerRoute,
+		),
+	}
+}
+
+func (r *nftPeerRouter) markChain() nftBaseChain {
+	return nftBaseChain{family: nftRouteTableFamily, table: nftRouteTableName, name: nftRouteMarkChain}
+}
+
+func (r *nftPeerRouter) natChain() nftBaseChain {
+	return nftBaseChain{family: nftRouteTableFamily, table: nftRouteTableName, name: nftRouteNATChain}
+}
+
+func (r *nftPeerRouter) Sync(peers []*PeerInfo) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed || len(r.outbounds) == 0 {
+		return nil
+	}
+
+	desired := make(map[string]*PeerInfo, len(peers))
+	unknown := make(map[string]struct{})
+	for _, peer := range peers {
+		if peer.OutboundTag == "" {
+			continue
+		}
+		if _, ok := r.outbounds[peer.OutboundTag]; !ok {
+			unknown[peer.OutboundTag] = struct{}{}
+			continue
+		}
+		desired[peer.PublicKey.String()] = peer
+	}
+
+	stale, fresh := r.peers.changes(desired)
+	if len(stale) > 0 || len(fresh) > 0 {
+		if err := r.apply(desired, stale, fresh); err != nil {
+			return err
+		}
+	}
+
+	if len(unknown) > 0 {
+		tags := make([]string, 0, len(unknown))
+		for tag := range unknown {
+			tags = append(tags, tag)
+		}
+		sort.Strings(tags)
+		return fmt.Errorf("unknown outbound tags %s, peers using them keep the default route", strings.Join(tags, ", "))
+	}
+	return nil
+}
+
+func (r *nftPeerRouter) apply(desired map[string]*PeerInfo, stale, fresh []string) error {
+	if r.ownerID == "" {
+		if err := r.install(); err != nil {
+			return err
+		}
+	}
+
+	comment := func(publicKey string) string { return nftPeerRouteComment(r.ownerID, publicKey) }
+	rules := func(peer *PeerInfo) []string {
+		return nftPeerRouteRules(r.iface, peer, r.outbounds[peer.OutboundTag].Mark)
+	}
+	return r.peers.apply(desired, stale, fresh, comment, rules)
+}
+
+// install adds the chains, the rules shared by every peer and the policy
+// routing rules of each outbound.
+func (r *nftPeerRouter) install() error {
+	if err := ensureNFTRouteChains(); err != nil {
+		return err
+	}
+
+	tags := make([]string, 0, len(r.outbounds))
+	for tag := range r.outbounds {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	for _, tag := range tags {
+		outbound := r.outbounds[tag]
+		key := [2]int{int(outbound.Mark), outbound.Table}
+		if err := acquirePeerOutboundRules(key); err != nil {
+			r.releaseRules()
+			return err
+		}
+		r.acquired = append(r.acquired, key)
+	}
+
+	ownerID := newHostRoutingOwnerID(r.iface)
+	comment := nftString(fmt.Sprintf("%sowner=%s type=route", nftRuleCommentPrefix, ownerID))
+	var script strings.Builder
+	for _, rule := range nftForwardedOnlyRules(r.iface, r.networks) {
+		fmt.Fprintf(&script, "add rule %s %s %s %s comment %s\n", nftRouteTableFamily, nftRouteTableName, nftRouteMarkChain, rule, comment)
+	}
+	for _, tag := range tags {
+		fmt.Fprintf(&script, "add rule %s %s %s meta mark %d masquerade comment %s\n", nftRouteTableFamily, nftRouteTableName, nftRouteNATChain, r.outbounds[tag].Mark, comment)
+	}
+	if err := runNFTScript(script.String()); err != nil {
+		r.releaseRules()
+		return err
+	}
+	r.ownerID = ownerID
+	return nil
+}
+
+func (r *nftPeerRouter) releaseRules() error {
+	var errs []error
+	for _, key := range r.acquired {
+		errs = append(errs, releasePeerOutboundRules(key))
+	}
+	r.acquired = nil
+	return errors.Join(errs...)
+}
+
+func (r *nftPeerRouter) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.closed = true
+	if r.ownerID == "" {
+		return nil
+	}
+	r.peers.forget()
+	prefix := nftOwnerCommentPrefix(r.ownerID)
+	return errors.Join(
+		removeNFTRulesWithCommentPrefix(r.markChain(), prefix),
+		removeNFTRulesWithCommentPrefix(r.natChain(), prefix),
+		r.releaseRules(),
+	)
+}
+
+func ensureNFTRouteChains() error {
+	if err := runNFT("add", "table", nftRouteTableFamily, nftRouteTableName); err != nil && !nftAlreadyExists(err) {
+		return err
+	}
+	if err := runNFT(
+		"add", "chain", nftRouteTableFamily, nftRouteTableName, nftRouteMarkChain,
+		"{", "type", "filter", "hook", "prerouting", "priority", "mangle", ";", "policy", "accept", ";", "}",
+	); err != nil && !nftAlreadyExists(err) {
+		return err
+	}
+	if err := runNFT(
+		"add", "chain", nftRouteTableFamily, nftRouteTableName, nftRouteNATChain,
+		"{", "type", "nat", "hook", "postrouting", "priority", "srcnat", ";", "policy", "accept", ";", "}",
+	); err != nil && !nftAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// nftPeerRouteRules returns the rule bodies marking traffic from one peer.
+func nftPeerRouteRules(iface string, peer *PeerInfo, mark uint32) []string {
+	var v4, v6 []string
+	for _, ipNet := range peer.AllowedIPs {
+		if ipNet.IP.To4() != nil {
+			v4 = append(v4, ipNet.String())
+		} else {
+			v6 = append(v6, ipNet.String())
+		}
+	}
+
+	rules := make([]string, 0, 2)
+	if len(v4) > 0 {
+		rules = append(rules, fmt.Sprintf("iifname %q ip saddr { %s } meta mark set %d", iface, strings.Join(v4, ", "), mark))
+	}
+	if len(v6) > 0 {
+		rules = append(rules, fmt.Sprintf("iifname %q ip6 saddr { %s } meta mark set %d", iface, strings.Join(v6, ", "), mark))
+	}
+	return rules
+}
+
+func nftPeerRouteComment(ownerID, publicKey string) string {
+	return fmt.Sprintf("%sowner=%s type=route peer=%s", nftRuleCommentPrefix, ownerID, publicKey)
+}
+
+func samePeerRoute(a, b *PeerInfo) bool {
+	if a.OutboundTag != b.OutboundTag || len(a.AllowedIPs) != len(b.AllowedIPs) {
+		return false
+	}
+	for i := range a.AllowedIPs {
+		if a.AllowedIPs[i].String() != b.AllowedIPs[i].String() {
+			return false
+		}
+	}
+	return true
+}
+
+func acquirePeerOutboundRules(key [2]int) error {
+	return peerOutboundRules.acquire(key, func() (func() error, error) {
+		added, err := addPolicyRules(defaultNetlinkOps{}, markRules(uint32(key[0]), key[1]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to add rule for mark %d: %w", key[0], err)
+		}
+		return func() error { return deletePolicyRules(defaultNetlinkOps{}, added) }, nil
+	})
+}
+
+func releasePeerOutboundRules(key [2]int) error {
+	return peerOutboundRules.release(key)
+}
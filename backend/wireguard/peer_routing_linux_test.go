@@ -0,0 +1,23 @@
+//go:build linux
+
+package wireguard
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestNFTPeerRouteRules(t *testing.T) {
+	_, v4, _ := net.ParseCIDR("10.8.0.2/32")
+	_, v6, _ := net.ParseCIDR("fd00:8::2/128")
+	rules := nftPeerRouteRules("wg0", &PeerInfo{AllowedIPs: []net.IPNet{*v4, *v6}}, 100)
+
+	want := []string{
+		`iifname "wg0" ip saddr { 10.8.0.2/32 } meta mark set 100`,
+		`iifname "wg0" ip6 saddr { fd00:8::2/128 } meta mark set 100`,
+	}
+	if strings.Join(rules, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected rules:\n%s", strings.Join(rules, "\n"))
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+
+package wireguard
+
+import "net"
+
+// noopPeerRouter ignores peer outbounds on platforms without nftables.
+type noopPeerRouter struct{}
+
+func newPeerRouter(string, map[string]*PeerOutboundConfig, []*net.IPNet) peerRouter {
+	return noopPeerRouter{}
+}
+
+func (noopPeerRouter) Sync(_ []*PeerInfo) error { return nil }
+
+func (noopPeerRouter) Close() error { return nil }
@@ -0,0 +1,132 @@
+//go:build linux
+
+package wireguard
+
+import (
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// peerRuleSet tracks the nftables rules installed for each peer in one chain.
+// Every rule of a peer carries the peer's comment, so a changed peer is
+// rewritten without touching the others.
+type peerRuleSet struct {
+	chain   nftBaseChain
+	same    func(a, b *PeerInfo) bool
+	applied map[string]*PeerInfo
+}
+
+func newPeerRuleSet(chain nftBaseChain, same func(a, b *PeerInfo) bool) *peerRuleSet {
+	return &peerRuleSet{chain: chain, same: same, applied: make(map[string]*PeerInfo)}
+}
+
+// changes returns the applied peers whose rules must go and the desired peers
+// whose rules must be added, by public key. A changed peer is in both.
+func (s *peerRuleSet) changes(desired map[string]*PeerInfo) (stale, fresh []string) {
+	for key, applied := range s.applied {
+		if peer, ok := desired[key]; !ok || !s.same(applied, peer) {
+			stale = append(stale, key)
+		}
+	}
+	for key, peer := range desired {
+		if applied, ok := s.applied[key]; !ok || !s.same(applied, peer) {
+			fresh = append(fresh, key)
+		}
+	}
+	slices.Sort(stale)
+	slices.Sort(fresh)
+	return stale, fresh
+}
+
+// apply deletes the rules of the stale peers and adds the rules of the fresh
+// ones in a single nft transaction.
+func (s *peerRuleSet) apply(desired map[string]*PeerInfo, stale, fresh []string, comment func(publicKey string) string, rules func(peer *PeerInfo) []string) error {
+	chain := s.chain
+	var script strings.Builder
+	if len(stale) > 0 {
+		out, err := exec.Command("nft", "-a", "list", "chain", chain.family, chain.table, chain.name).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("nft -a list chain %s %s %s: %w: %s", chain.family, chain.table, chain.name, err, strings.TrimSpace(string(out)))
+		}
+		for _, key := range stale {
+			for _, handle := range nftRuleHandlesWithComment(out, comment(key)) {
+				fmt.Fprintf(&script, "delete rule %s %s %s handle %s\n", chain.family, chain.table, chain.name, handle)
+			}
+		}
+	}
+	for _, key := range fresh {
+		for _, rule := range rules(desired[key]) {
+			fmt.Fprintf(&script, "add rule %s %s %s %s comment %s\n", chain.family, chain.table, chain.name, rule, nftString(comment(key)))
+		}
+	}
+
+	if err := runNFTScript(script.String()); err != nil {
+		return err
+	}
+
+	for _, key := range stale {
+		delete(s.applied, key)
+	}
+	for _, key := range fresh {
+		s.applied[key] = clonePeerInfo(desired[key])
+	}
+	return nil
+}
+
+// forget drops the tracked peers once their rules are gone.
+func (s *peerRuleSet) forget() {
+	s.applied = make(map[string]*PeerInfo)
+}
+
+// sharedHostState is host state several interfaces can need at once, such as
+// the policy routing of a mark and table pair. The first acquire of a key
+// installs it and the last release undoes it.
+type sharedHostState[K comparable] struct {
+	mu      sync.Mutex
+	holders map[K]*sharedHostHold
+}
+
+type sharedHostHold struct {
+	users int
+	undo  func() error
+}
+
+func newSharedHostState[K comparable]() *sharedHostState[K] {
+	return &sharedHostState[K]{holders: make(map[K]*sharedHostHold)}
+}
+
+// acquire installs key with install unless another interface already holds
+// it. install returns the function that undoes exactly what it changed.
+func (s *sharedHostState[K]) acquire(key K, install func() (undo func() error, err error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if hold, ok := s.holders[key]; ok {
+		hold.users++
+		return nil
+	}
+	undo, err := install()
+	if err != nil {
+		return err
+	}
+	s.holders[key] = &sharedHostHold{users: 1, undo: undo}
+	return nil
+}
+
+func (s *sharedHostState[K]) release(key K) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hold, ok := s.holders[key]
+	if !ok {
+		return nil
+	}
+	if hold.users--; hold.users > 0 {
+		return nil
+	}
+	delete(s.holders, key)
+	return hold.undo()
+}
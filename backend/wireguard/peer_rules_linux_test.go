@@ -0,0 +1,60 @@
+//go:build linux
+
+package wireguard
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestPeerRuleSetChanges(t *testing.T) {
+	set := newPeerRuleSet(nftBaseChain{}, samePeerRates)
+	set.applied["kept"] = &PeerInfo{UploadRate: 100}
+	set.applied["changed"] = &PeerInfo{UploadRate: 100}
+	set.applied["removed"] = &PeerInfo{UploadRate: 100}
+
+	stale, fresh := set.changes(map[string]*PeerInfo{
+		"kept":    {UploadRate: 100},
+		"changed": {UploadRate: 200},
+		"added":   {DownloadRate: 100},
+	})
+	if !slices.Equal(stale, []string{"changed", "removed"}) {
+		t.Fatalf("unexpected stale peers: %v", stale)
+	}
+	if !slices.Equal(fresh, []string{"added", "changed"}) {
+		t.Fatalf("unexpected fresh peers: %v", fresh)
+	}
+}
+
+func TestSharedHostStateUndoesOnLastRelease(t *testing.T) {
+	state := newSharedHostState[int]()
+	installs, undos := 0, 0
+	install := func() (func() error, error) {
+		installs++
+		return func() error { undos++; return nil }, nil
+	}
+
+	for range 2 {
+		if err := state.acquire(1, install); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if installs != 1 {
+		t.Fatalf("expected one install for two holders, got %d", installs)
+	}
+	if err := state.release(1); err != nil || undos != 0 {
+		t.Fatalf("expected the state to stay while held, got %d undos (%v)", undos, err)
+	}
+	if err := state.release(1); err != nil || undos != 1 {
+		t.Fatalf("expected the last release to undo the state, got %d undos (%v)", undos, err)
+	}
+
+	failed := errors.New("no permission")
+	if err := state.acquire(2, func() (func() error, error) { return nil, failed }); !errors.Is(err, failed) {
+		t.Fatalf("expected the install error, got %v", err)
+	}
+	if err := state.acquire(2, install); err != nil || installs != 2 {
+		t.Fatalf("expected a failed install not to count as a holder, got %d installs (%v)", installs, err)
+	}
+}
@@ -0,0 +1,177 @@
+package wireguard
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pasarguard/node/common"
+)
+
+// PolicyConfig limits where peers may forward traffic to. Allow rules are
+// checked first, then deny rules, then the two blocks.
+type PolicyConfig struct {
+	BlockPeerToPeer      bool         `json:"block_peer_to_peer,omitempty"`
+	BlockPrivateNetworks bool         `json:"block_private_networks,omitempty"`
+	Allow                []PolicyRule `json:"allow,omitempty"`
+	Deny                 []PolicyRule `json:"deny,omitempty"`
+}
+
+// PolicyRule matches a destination network and, optionally, a protocol and ports.
+type PolicyRule struct {
+	CIDR string `json:"cidr"`
+	// Protocol is "tcp", "udp" or empty for both.
+	Protocol string `json:"protocol,omitempty"`
+	// Ports are single ports or ranges like "8000-8100"; empty matches every port.
+	Ports []string `json:"ports,omitempty"`
+}
+
+// privateNetworks are the destinations dropped by BlockPrivateNetworks.
+var privateNetworks = []string{
+	"10.0.0.0/8",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"100.64.0.0/10",
+	"169.254.0.0/16",
+	"fc00::/7",
+	"fe80::/10",
+}
+
+// policyFirewall keeps the forwarding policy of an interface installed.
+type policyFirewall interface {
+	// Apply replaces the installed rules with policy in one step; nil removes them.
+	Apply(policy *PolicyConfig) error
+	// Close removes every rule this instance installed.
+	Close() error
+}
+
+func (p *PolicyConfig) empty() bool {
+	return p == nil || (!p.BlockPeerToPeer && !p.BlockPrivateNetworks && len(p.Allow) == 0 && len(p.Deny) == 0)
+}
+
+func (p *PolicyConfig) validate() error {
+	if p == nil {
+		return nil
+	}
+	for i, rule := range p.Allow {
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("allow rule %d: %w", i, err)
+		}
+	}
+	for i, rule := range p.Deny {
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("deny rule %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (r PolicyRule) validate() error {
+	if _, err := netip.ParsePrefix(strings.TrimSpace(r.CIDR)); err != nil {
+		return fmt.Errorf("invalid cidr %q", r.CIDR)
+	}
+	switch r.Protocol {
+	case "", "tcp", "udp":
+	default:
+		return fmt.Errorf("invalid protocol %q (want tcp, udp or empty)", r.Protocol)
+	}
+	for _, port := range r.Ports {
+		if _, _, err := parsePortRange(port); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parsePortRange parses "443" or "8000-8100".
+func parsePortRange(s string) (uint16, uint16, error) {
+	first, last, isRange := strings.Cut(strings.TrimSpace(s), "-")
+	if !isRange {
+		last = first
+	}
+	from, err := strconv.ParseUint(first, 10, 16)
+	if err != nil || from == 0 {
+		return 0, 0, fmt.Errorf("invalid port %q", s)
+	}
+	to, err := strconv.ParseUint(last, 10, 16)
+	if err != nil || to < from {
+		return 0, 0, fmt.Errorf("invalid port %q", s)
+	}
+	return uint16(from), uint16(to), nil
+}
+
+func policyFromProto(policy *common.Policy) *PolicyConfig {
+	if policy == nil {
+		return nil
+	}
+	rules := func(in []*common.PolicyRule) []PolicyRule {
+		out := make([]PolicyRule, 0, len(in))
+		for _, rule := range in {
+			out = append(out, PolicyRule{CIDR: rule.GetCidr(), Protocol: rule.GetProtocol(), Ports: rule.GetPorts()})
+		}
+		return out
+	}
+	return &PolicyConfig{
+		BlockPeerToPeer:      policy.GetBlockPeerToPeer(),
+		BlockPrivateNetworks: policy.GetBlockPrivateNetworks(),
+		Allow:                rules(policy.GetAllow()),
+		Deny:                 rules(policy.GetDeny()),
+	}
+}
+
+// syncPolicy installs the configured policy at startup. A failure is logged
+// so the interface still comes up.
+func (wg *WireGuard) syncPolicy() {
+	wg.mu.RLock()
+	firewall := wg.policy
+	policy := wg.config.Policy
+	wg.mu.RUnlock()
+
+	if firewall == nil || policy.empty() {
+		return
+	}
+	if err := firewall.Apply(policy); err != nil {
+		log.Printf("wireguard policy: %v", err)
+		wg.emitErrorLogf("failed to apply forwarding policy: %v", err)
+	}
+}
+
+// UpdatePolicy replaces the forwarding policy of the running interface.
+func (wg *WireGuard) UpdatePolicy(_ context.Context, request *common.PolicyRequest) error {
+	wg.mu.RLock()
+	state := wg.state
+	cfg := wg.config
+	firewall := wg.policy
+	wg.mu.RUnlock()
+
+	if state != lifecycleRunning || firewall == nil {
+		return errWireGuardNotStarted
+	}
+	if request.GetInbound() != "" && request.GetInbound() != cfg.InterfaceName {
+		return status.Errorf(codes.NotFound, "unknown wireguard inbound %s", request.GetInbound())
+	}
+
+	policy := policyFromProto(request.GetPolicy())
+	if err := policy.validate(); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid policy: %v", err)
+	}
+	if cfg.XrayTProxy != nil && !policy.empty() {
+		return status.Errorf(codes.FailedPrecondition, "%v", errTProxyPolicy)
+	}
+	if err := firewall.Apply(policy); err != nil {
+		return status.Errorf(codes.Internal, "failed to apply policy: %v", err)
+	}
+
+	wg.mu.Lock()
+	cfg.Policy = policy
+	wg.mu.Unlock()
+
+	wg.emitInfoLogf("forwarding policy of %s updated", cfg.InterfaceName)
+	return nil
+}
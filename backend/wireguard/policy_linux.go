@@ -0,0 +1,172 @@
+//go:build linux
+
+package wireguard
+
+import (
+	"fmt"
+	"net/netip"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+const (
+	nftPolicyTableFamily = "inet"
+	nftPolicyTableName   = "pg_node_wg_policy"
+	nftPolicyChain       = "forward"
+)
+
+// nftPolicyFirewall installs the forwarding policy in its own forward base
+// chain. The chain runs before shaping and host routing accepts do not reach
+// it, so a drop here is final. Rules carry the owner comment used by host
+// routing and are replaced in a single nft transaction.
+type nftPolicyFirewall struct {
+	mu      sync.Mutex
+	iface   string
+	ownerID string
+	closed  bool
+}
+
+func newPolicyFirewall(iface string) policyFirewall {
+	return &nftPolicyFirewall{iface: iface}
+}
+
+func (f *nftPolicyFirewall) chain() nftBaseChain {
+	return nftBaseChain{family: nftPolicyTableFamily, table: nftPolicyTableName, name: nftPolicyChain}
+}
+
+func (f *nftPolicyFirewall) Apply(policy *PolicyConfig) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return nil
+	}
+
+	rules := nftPolicyRules(f.iface, policy)
+	if f.ownerID == "" {
+		if len(rules) == 0 {
+			return nil
+		}
+		if err := requireNFTFirewall("policy"); err != nil {
+			return err
+		}
+		if err := ensureNFTPolicyChain(); err != nil {
+			return err
+		}
+		f.ownerID = newHostRoutingOwnerID(f.iface)
+	}
+
+	chain := f.chain()
+	out, err := exec.Command("nft", "-a", "list", "chain", chain.family, chain.table, chain.name).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("nft -a list chain %s %s %s: %w: %s", chain.family, chain.table, chain.name, err, strings.TrimSpace(string(out)))
+	}
+
+	var script strings.Builder
+	for _, handle := range nftRuleHandlesWithComment(out, nftOwnerCommentPrefix(f.ownerID)) {
+		fmt.Fprintf(&script, "delete rule %s %s %s handle %s\n", chain.family, chain.table, chain.name, handle)
+	}
+	comment := nftPolicyComment(f.ownerID)
+	for _, rule := range rules {
+		fmt.Fprintf(&script, "add rule %s %s %s %s comment %s\n", chain.family, chain.table, chain.name, rule, nftString(comment))
+	}
+	if script.Len() == 0 {
+		return nil
+	}
+	return runNFTScript(script.String())
+}
+
+func (f *nftPolicyFirewall) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+	if f.ownerID == "" {
+		return nil
+	}
+	return removeNFTRulesWithCommentPrefix(f.chain(), nftOwnerCommentPrefix(f.ownerID))
+}
+
+func ensureNFTPolicyChain() error {
+	if err := runNFT("add", "table", nftPolicyTableFamily, nftPolicyTableName); err != nil && !nftAlreadyExists(err) {
+		return err
+	}
+	if err := runNFT(
+		"add", "chain", nftPolicyTableFamily, nftPolicyTableName, nftPolicyChain,
+		"{", "type", "filter", "hook", "forward", "priority", "-2", ";", "policy", "accept", ";", "}",
+	); err != nil && !nftAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// nftPolicyRules returns the rule bodies enforcing policy on traffic from
+// iface, in evaluation order. An accept only ends this chain, which lets
+// allow rules punch holes in the blocks below them.
+func nftPolicyRules(iface string, policy *PolicyConfig) []string {
+	if policy.empty() {
+		return nil
+	}
+
+	rules := make([]string, 0)
+	for _, rule := range policy.Allow {
+		rules = append(rules, fmt.Sprintf("iifname %q %s accept", iface, nftPolicyMatch(rule)))
+	}
+	for _, rule := range policy.Deny {
+		rules = append(rules, fmt.Sprintf("iifname %q %s drop", iface, nftPolicyMatch(rule)))
+	}
+	if policy.BlockPeerToPeer {
+		rules = append(rules, fmt.Sprintf("iifname %q oifname %q drop", iface, iface))
+	}
+	if policy.BlockPrivateNetworks {
+		var v4, v6 []string
+		for _, network := range privateNetworks {
+			if netip.MustParsePrefix(network).Addr().Is4() {
+				v4 = append(v4, network)
+			} else {
+				v6 = append(v6, network)
+			}
+		}
+		// Peer to peer traffic is left to BlockPeerToPeer.
+		rules = append(rules,
+			fmt.Sprintf("iifname %q oifname != %q ip daddr { %s } drop", iface, iface, strings.Join(v4, ", ")),
+			fmt.Sprintf("iifname %q oifname != %q ip6 daddr { %s } drop", iface, iface, strings.Join(v6, ", ")),
+		)
+	}
+	return rules
+}
+
+// nftPolicyMatch renders the destination match of a validated rule.
+func nftPolicyMatch(rule PolicyRule) string {
+	prefix := netip.MustParsePrefix(strings.TrimSpace(rule.CIDR)).Masked()
+	family := "ip"
+	if prefix.Addr().Is6() {
+		family = "ip6"
+	}
+	match := fmt.Sprintf("%s daddr %s", family, prefix)
+
+	ports := make([]string, 0, len(rule.Ports))
+	for _, port := range rule.Ports {
+		from, to, _ := parsePortRange(port)
+		if from == to {
+			ports = append(ports, fmt.Sprintf("%d", from))
+		} else {
+			ports = append(ports, fmt.Sprintf("%d-%d", from, to))
+		}
+	}
+
+	switch {
+	case rule.Protocol != "" && len(ports) > 0:
+		match += fmt.Sprintf(" %s dport { %s }", rule.Protocol, strings.Join(ports, ", "))
+	case rule.Protocol != "":
+		match += " meta l4proto " + rule.Protocol
+	case len(ports) > 0:
+		match += fmt.Sprintf(" meta l4proto { tcp, udp } th dport { %s }", strings.Join(ports, ", "))
+	}
+	return match
+}
+
+func nftPolicyComment(ownerID string) string {
+	return fmt.Sprintf("%sowner=%s type=policy", nftRuleCommentPrefix, ownerID)
+}
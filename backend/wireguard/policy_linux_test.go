@@ -0,0 +1,47 @@
+//go:build linux
+
+package wireguard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNFTPolicyRules(t *testing.T) {
+	rules := nftPolicyRules("wg0", &PolicyConfig{
+		BlockPeerToPeer:      true,
+		BlockPrivateNetworks: true,
+		Allow: []PolicyRule{
+			{CIDR: "192.168.1.10/24", Protocol: "udp", Ports: []string{"53"}},
+			{CIDR: "10.8.0.5/32"},
+		},
+		Deny: []PolicyRule{
+			{CIDR: "0.0.0.0/0", Protocol: "tcp"},
+			{CIDR: "2001:db8::/32", Ports: []string{"25", "8000-8100"}},
+		},
+	})
+
+	want := []string{
+		`iifname "wg0" ip daddr 192.168.1.0/24 udp dport { 53 } accept`,
+		`iifname "wg0" ip daddr 10.8.0.5/32 accept`,
+		`iifname "wg0" ip daddr 0.0.0.0/0 meta l4proto tcp drop`,
+		`iifname "wg0" ip6 daddr 2001:db8::/32 meta l4proto { tcp, udp } th dport { 25, 8000-8100 } drop`,
+		`iifname "wg0" oifname "wg0" drop`,
+		`iifname "wg0" oifname != "wg0" ip daddr { 10.0.0.0/8, 172.16.0.0/12, 192.168.0.0/16, 100.64.0.0/10, 169.254.0.0/16 } drop`,
+		`iifname "wg0" oifname != "wg0" ip6 daddr { fc00::/7, fe80::/10 } drop`,
+	}
+	if strings.Join(rules, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected rules:\n%s", strings.Join(rules, "\n"))
+	}
+
+	if rules := nftPolicyRules("wg0", &PolicyConfig{}); len(rules) != 0 {
+		t.Fatalf("expected no rules for an empty policy, got %v", rules)
+	}
+}
+
+func TestNFTPolicyCommentCarriesOwner(t *testing.T) {
+	owner := newHostRoutingOwnerID("wg0")
+	if comment := nftPolicyComment(owner); !strings.HasPrefix(comment, nftOwnerCommentPrefix(owner)) {
+		t.Fatalf("comment must carry the owner prefix for cleanup: %s", comment)
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package wireguard
+
+// noopPolicyFirewall ignores forwarding policies on platforms without nftables.
+type noopPolicyFirewall struct{}
+
+func newPolicyFirewall(_ string) policyFirewall { return noopPolicyFirewall{} }
+
+func (noopPolicyFirewall) Apply(_ *PolicyConfig) error { return nil }
+
+func (noopPolicyFirewall) Close() error { return nil }
@@ -0,0 +1,86 @@
+package wireguard
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pasarguard/node/common"
+)
+
+type fakePolicyFirewall struct {
+	applied []*PolicyConfig
+}
+
+func (f *fakePolicyFirewall) Apply(policy *PolicyConfig) error {
+	f.applied = append(f.applied, policy)
+	return nil
+}
+
+func (f *fakePolicyFirewall) Close() error { return nil }
+
+func TestPolicyValidate(t *testing.T) {
+	valid := &PolicyConfig{Allow: []PolicyRule{{CIDR: "10.0.0.0/8", Protocol: "tcp", Ports: []string{"443", "8000-8100"}}}}
+	if err := valid.validate(); err != nil {
+		t.Fatalf("expected valid policy, got %v", err)
+	}
+
+	for name, policy := range map[string]*PolicyConfig{
+		"bad cidr":     {Deny: []PolicyRule{{CIDR: "10.0.0.0"}}},
+		"bad protocol": {Deny: []PolicyRule{{CIDR: "10.0.0.0/8", Protocol: "icmp"}}},
+		"zero port":    {Allow: []PolicyRule{{CIDR: "10.0.0.0/8", Ports: []string{"0"}}}},
+		"bad range":    {Allow: []PolicyRule{{CIDR: "10.0.0.0/8", Ports: []string{"90-80"}}}},
+	} {
+		if err := policy.validate(); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestNewConfigRejectsInvalidPolicy(t *testing.T) {
+	if _, err := NewConfig(`{"policy":{"deny":[{"cidr":"nope"}]}}`); err == nil {
+		t.Fatal("expected invalid policy error")
+	}
+
+	cfg, err := NewConfig(`{"policy":{"block_peer_to_peer":true,"allow":[{"cidr":"10.0.0.1/32","ports":["53"]}]}}`)
+	if err != nil {
+		t.Fatalf("NewConfig failed: %v", err)
+	}
+	if !cfg.Policy.BlockPeerToPeer || len(cfg.Policy.Allow) != 1 {
+		t.Fatalf("unexpected policy: %+v", cfg.Policy)
+	}
+}
+
+func TestUpdatePolicyReplacesPolicy(t *testing.T) {
+	cfg, err := NewConfig(`{"interface_name":"wg-policy"}`)
+	if err != nil {
+		t.Fatalf("NewConfig failed: %v", err)
+	}
+	firewall := &fakePolicyFirewall{}
+	wg := &WireGuard{config: cfg, state: lifecycleRunning, policy: firewall, logChan: make(chan string, 4)}
+
+	request := &common.PolicyRequest{Policy: &common.Policy{
+		BlockPrivateNetworks: true,
+		Deny:                 []*common.PolicyRule{{Cidr: "203.0.113.0/24", Protocol: "tcp", Ports: []string{"25"}}},
+	}}
+	if err := wg.UpdatePolicy(context.Background(), request); err != nil {
+		t.Fatalf("UpdatePolicy failed: %v", err)
+	}
+	if len(firewall.applied) != 1 || !firewall.applied[0].BlockPrivateNetworks || cfg.Policy != firewall.applied[0] {
+		t.Fatalf("expected the policy to be applied and stored, got %+v", firewall.applied)
+	}
+
+	bad := &common.PolicyRequest{Policy: &common.Policy{Allow: []*common.PolicyRule{{Cidr: "bad"}}}}
+	if err := wg.UpdatePolicy(context.Background(), bad); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+	other := &common.PolicyRequest{Inbound: "wg-other"}
+	if err := wg.UpdatePolicy(context.Background(), other); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+	if len(firewall.applied) != 1 {
+		t.Fatalf("rejected requests must not touch the firewall, got %d applies", len(firewall.applied))
+	}
+}
@@ -0,0 +1,38 @@
+package wireguard
+
+import (
+	"log"
+	"strings"
+)
+
+// rateLimiter keeps per-peer bandwidth limits in sync with the peer store.
+type rateLimiter interface {
+	// Sync makes the installed limits match peers. Peers without rates are unshaped.
+	Sync(peers []*PeerInfo) error
+	// Close removes every limit this instance installed.
+	Close() error
+}
+
+// syncRateLimits applies the rate limits of the current peer store. Peers are
+// already committed at this point, so a failure is logged instead of failing the sync.
+func (wg *WireGuard) syncRateLimits() {
+	wg.mu.RLock()
+	limiter := wg.rateLimiter
+	tproxy := wg.config.XrayTProxy
+	wg.mu.RUnlock()
+
+	if limiter == nil {
+		return
+	}
+	peers := wg.peerStore.GetAll()
+	if err := limiter.Sync(peers); err != nil {
+		log.Printf("wireguard rate limits: %v", err)
+		wg.emitErrorLogf("failed to apply peer rate limits: %v", err)
+	}
+	if tproxy != nil {
+		if emails := tproxyRatedPeers(peers); len(emails) > 0 {
+			log.Printf("wireguard rate limits: xray_tproxy bypasses the rates of %s", strings.Join(emails, ", "))
+			wg.emitErrorLogf("rates of %d peers only apply to traffic not redirected to xray, limit them in xray instead", len(emails))
+		}
+	}
+}
@@ -0,0 +1,147 @@
+//go:build linux
+
+package wireguard
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const (
+	nftShapeTableFamily = "inet"
+	nftShapeTableName   = "pg_node_wg_shape"
+	nftShapeChain       = "forward"
+)
+
+// nftRateLimiter drops a peer's forwarded traffic above its rate with nftables
+// limit rules keyed on the peer's AllowedIPs. Rules carry the owner comment used
+// by host routing so Close only removes what this instance installed.
+//
+// Limits are kept per address family, so a dual-stack peer gets the rate on
+// IPv4 and IPv6 separately.
+type nftRateLimiter struct {
+	mu      sync.Mutex
+	iface   string
+	ownerID string
+	peers   *peerRuleSet
+	closed  bool
+}
+
+func newRateLimiter(iface string) rateLimiter {
+	chain := nftBaseChain{family: nftShapeTableFamily, table: nftShapeTableName, name: nftShapeChain}
+	return &nftRateLimiter{
+		iface: iface,
+		peers: newPeerRuleSet(chain, samePeerRates),
+	}
+}
+
+func (r *nftRateLimiter) Sync(peers []*PeerInfo) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil
+	}
+
+	desired := make(map[string]*PeerInfo, len(peers))
+	for _, peer := range peers {
+		if peer.UploadRate > 0 || peer.DownloadRate > 0 {
+			desired[peer.PublicKey.String()] = peer
+		}
+	}
+
+	stale, fresh := r.peers.changes(desired)
+	if len(stale) == 0 && len(fresh) == 0 {
+		return nil
+	}
+
+	if r.ownerID == "" {
+		if err := requireNFTFirewall("rate limits"); err != nil {
+			return err
+		}
+		if err := ensureNFTShapeChain(); err != nil {
+			return err
+		}
+		r.ownerID = newHostRoutingOwnerID(r.iface)
+	}
+
+	comment := func(publicKey string) string { return nftRateLimitComment(r.ownerID, publicKey) }
+	rules := func(peer *PeerInfo) []string { return nftRateLimitRules(r.iface, peer) }
+	return r.peers.apply(desired, stale, fresh, comment, rules)
+}
+
+func (r *nftRateLimiter) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.closed = true
+	if r.ownerID == "" {
+		return nil
+	}
+	r.peers.forget()
+	return removeNFTRulesWithCommentPrefix(r.peers.chain, nftOwnerCommentPrefix(r.ownerID))
+}
+
+func ensureNFTShapeChain() error {
+	if err := runNFT("add", "table", nftShapeTableFamily, nftShapeTableName); err != nil && !nftAlreadyExists(err) {
+		return err
+	}
+	if err := runNFT(
+		"add", "chain", nftShapeTableFamily, nftShapeTableName, nftShapeChain,
+		"{", "type", "filter", "hook", "forward", "priority", "-1", ";", "policy", "accept", ";", "}",
+	); err != nil && !nftAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// nftRateLimitRules returns the rule bodies shaping one peer: upload matches
+// packets arriving from the peer, download matches packets sent to it.
+func nftRateLimitRules(iface string, peer *PeerInfo) []string {
+	var v4, v6 []string
+	for _, ipNet := range peer.AllowedIPs {
+		if ipNet.IP.To4() != nil {
+			v4 = append(v4, ipNet.String())
+		} else {
+			v6 = append(v6, ipNet.String())
+		}
+	}
+
+	rules := make([]string, 0, 4)
+	add := func(ifaceMatch, addrMatch string, rate uint64) {
+		if rate == 0 {
+			return
+		}
+		for family, nets := range map[string][]string{"ip": v4, "ip6": v6} {
+			if len(nets) == 0 {
+				continue
+			}
+			rules = append(rules, fmt.Sprintf(
+				"%s %q %s %s { %s } limit rate over %d bytes/second burst %d bytes drop",
+				ifaceMatch, iface, family, addrMatch, strings.Join(nets, ", "), rate, rate,
+			))
+		}
+	}
+	add("iifname", "saddr", peer.UploadRate)
+	add("oifname", "daddr", peer.DownloadRate)
+	slices.Sort(rules)
+	return rules
+}
+
+func nftRateLimitComment(ownerID, publicKey string) string {
+	return fmt.Sprintf("%sowner=%s type=ratelimit peer=%s", nftRuleCommentPrefix, ownerID, publicKey)
+}
+
+func samePeerRates(a, b *PeerInfo) bool {
+	if a.UploadRate != b.UploadRate || a.DownloadRate != b.DownloadRate || len(a.AllowedIPs) != len(b.AllowedIPs) {
+		return false
+	}
+	for i := range a.AllowedIPs {
+		if a.AllowedIPs[i].String() != b.AllowedIPs[i].String() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,71 @@
+//go:build linux
+
+package wireguard
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+func TestNFTRateLimitRules(t *testing.T) {
+	_, v4, _ := net.ParseCIDR("10.0.0.2/32")
+	_, v6, _ := net.ParseCIDR("fd00::2/128")
+
+	peer := &PeerInfo{
+		Email:        "user@example.com",
+		AllowedIPs:   []net.IPNet{*v4, *v6},
+		UploadRate:   125000,
+		DownloadRate: 250000,
+	}
+
+	rules := nftRateLimitRules("wg0", peer)
+	want := []string{
+		`iifname "wg0" ip saddr { 10.0.0.2/32 } limit rate over 125000 bytes/second burst 125000 bytes drop`,
+		`iifname "wg0" ip6 saddr { fd00::2/128 } limit rate over 125000 bytes/second burst 125000 bytes drop`,
+		`oifname "wg0" ip daddr { 10.0.0.2/32 } limit rate over 250000 bytes/second burst 250000 bytes drop`,
+		`oifname "wg0" ip6 daddr { fd00::2/128 } limit rate over 250000 bytes/second burst 250000 bytes drop`,
+	}
+	if strings.Join(rules, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected rules:\n%s", strings.Join(rules, "\n"))
+	}
+
+	peer.UploadRate = 0
+	if rules := nftRateLimitRules("wg0", peer); len(rules) != 2 || strings.Contains(strings.Join(rules, "\n"), "iifname") {
+		t.Fatalf("expected only download rules, got %v", rules)
+	}
+}
+
+func TestNFTRateLimitCommentFitsNFTLimit(t *testing.T) {
+	key, err := wgtypes.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	owner := newHostRoutingOwnerID("wg-long-name15")
+	comment := nftRateLimitComment(owner, key.PublicKey().String())
+	if len(comment) > 128 {
+		t.Fatalf("comment exceeds nft's 128 byte limit (%d): %s", len(comment), comment)
+	}
+	if !strings.HasPrefix(comment, nftOwnerCommentPrefix(owner)) {
+		t.Fatalf("comment must carry the owner prefix for cleanup: %s", comment)
+	}
+}
+
+func TestSamePeerRates(t *testing.T) {
+	_, a, _ := net.ParseCIDR("10.0.0.2/32")
+	_, b, _ := net.ParseCIDR("10.0.0.3/32")
+
+	base := &PeerInfo{AllowedIPs: []net.IPNet{*a}, UploadRate: 1, DownloadRate: 2}
+	if !samePeerRates(base, clonePeerInfo(base)) {
+		t.Fatal("expected clone to match")
+	}
+	if samePeerRates(base, &PeerInfo{AllowedIPs: []net.IPNet{*b}, UploadRate: 1, DownloadRate: 2}) {
+		t.Fatal("expected different allowed ips to differ")
+	}
+	if samePeerRates(base, &PeerInfo{AllowedIPs: []net.IPNet{*a}, UploadRate: 1, DownloadRate: 3}) {
+		t.Fatal("expected different rates to differ")
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package wireguard
+
+// noopRateLimiter ignores rate limits on platforms without nftables.
+type noopRateLimiter struct{}
+
+func newRateLimiter(_ string) rateLimiter { return noopRateLimiter{} }
+
+func (noopRateLimiter) Sync(_ []*PeerInfo) error { return nil }
+
+func (noopRateLimiter) Close() error { return nil }
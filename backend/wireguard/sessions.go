@@ -0,0 +1,240 @@
+package wireguard
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pasarguard/node/common"
+)
+
+const (
+	// peerSessionHistorySize bounds the finished sessions kept per user.
+	peerSessionHistorySize = 20
+	peerEventBufferSize    = 64
+)
+
+// peerObservation is what one stats tick saw of a known peer.
+type peerObservation struct {
+	publicKey     string
+	email         string
+	endpoint      string
+	lastHandshake time.Time
+	rxBytes       int64
+}
+
+type peerSession struct {
+	email     string
+	endpoints []string
+	startedAt time.Time
+	rxBytes   int64
+}
+
+// sessionTracker turns the per-tick peer state into session events and keeps
+// a bounded session history per user.
+type sessionTracker struct {
+	mu          sync.Mutex
+	inbound     string
+	active      map[string]*peerSession // by public key
+	history     map[string][]*common.PeerSession
+	subscribers map[chan *common.PeerEvent]struct{}
+	closed      bool
+}
+
+func newSessionTracker(inbound string) *sessionTracker {
+	return &sessionTracker{
+		inbound:     inbound,
+		active:      make(map[string]*peerSession),
+		history:     make(map[string][]*common.PeerSession),
+		subscribers: make(map[chan *common.PeerEvent]struct{}),
+	}
+}
+
+// observe updates the sessions from one tick. A session is live while the
+// peer has a handshake within onlineActivityThreshold or keeps receiving
+// traffic; peers that are missing from peers end their session.
+func (t *sessionTracker) observe(now time.Time, peers []peerObservation) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return
+	}
+
+	cutoff := now.Add(-onlineActivityThreshold)
+	seen := make(map[string]struct{}, len(peers))
+	for _, peer := range peers {
+		session, ok := t.active[peer.publicKey]
+		fresh := !peer.lastHandshake.IsZero() && !peer.lastHandshake.Before(cutoff)
+		if ok && peer.rxBytes > session.rxBytes {
+			fresh = true
+		}
+		if !fresh || (ok && session.email != peer.email) {
+			continue
+		}
+		seen[peer.publicKey] = struct{}{}
+
+		if !ok {
+			t.active[peer.publicKey] = &peerSession{
+				email:     peer.email,
+				endpoints: []string{peer.endpoint},
+				startedAt: now,
+				rxBytes:   peer.rxBytes,
+			}
+			t.publish(&common.PeerEvent{
+				Type:      common.PeerEventType_CONNECTED,
+				Email:     peer.email,
+				Endpoint:  peer.endpoint,
+				Timestamp: now.Unix(),
+			})
+			continue
+		}
+
+		session.rxBytes = peer.rxBytes
+		previous := session.endpoints[len(session.endpoints)-1]
+		if peer.endpoint != "" && peer.endpoint != previous {
+			session.endpoints = append(session.endpoints, peer.endpoint)
+			t.publish(&common.PeerEvent{
+				Type:             common.PeerEventType_ROAMED,
+				Email:            peer.email,
+				Endpoint:         peer.endpoint,
+				PreviousEndpoint: previous,
+				Timestamp:        now.Unix(),
+				Duration:         int64(now.Sub(session.startedAt).Seconds()),
+			})
+		}
+	}
+
+	for key, session := range t.active {
+		if _, ok := seen[key]; !ok {
+			t.endLocked(key, session, now)
+		}
+	}
+}
+
+func (t *sessionTracker) endLocked(key string, session *peerSession, now time.Time) {
+	delete(t.active, key)
+
+	duration := int64(now.Sub(session.startedAt).Seconds())
+	history := append(t.history[session.email], &common.PeerSession{
+		Inbound:   t.inbound,
+		Endpoints: session.endpoints,
+		StartedAt: session.startedAt.Unix(),
+		EndedAt:   now.Unix(),
+		Duration:  duration,
+	})
+	if overflow := len(history) - peerSessionHistorySize; overflow > 0 {
+		history = slices.Delete(history, 0, overflow)
+	}
+	t.history[session.email] = history
+
+	t.publish(&common.PeerEvent{
+		Type:      common.PeerEventType_DISCONNECTED,
+		Email:     session.email,
+		Endpoint:  session.endpoints[len(session.endpoints)-1],
+		Timestamp: now.Unix(),
+		Duration:  duration,
+	})
+}
+
+// publish closes the channel of a subscriber that fell a full buffer behind,
+// so it sees the gap and subscribes again instead of silently missing events.
+func (t *sessionTracker) publish(event *common.PeerEvent) {
+	event.Inbound = t.inbound
+	for ch := range t.subscribers {
+		select {
+		case ch <- event:
+		default:
+			delete(t.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// subscribe returns a channel of events and a function that releases it.
+// The channel is closed on release or when the tracker closes.
+func (t *sessionTracker) subscribe() (<-chan *common.PeerEvent, func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ch := make(chan *common.PeerEvent, peerEventBufferSize)
+	if t.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	t.subscribers[ch] = struct{}{}
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if _, ok := t.subscribers[ch]; ok {
+				delete(t.subscribers, ch)
+				close(ch)
+			}
+		})
+	}
+}
+
+// sessions returns the history of email followed by its active session.
+func (t *sessionTracker) sessions(email string, now time.Time) []*common.PeerSession {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	sessions := slices.Clone(t.history[email])
+	for _, session := range t.active {
+		if session.email == email {
+			sessions = append(sessions, &common.PeerSession{
+				Inbound:   t.inbound,
+				Endpoints: slices.Clone(session.endpoints),
+				StartedAt: session.startedAt.Unix(),
+				Duration:  int64(now.Sub(session.startedAt).Seconds()),
+			})
+		}
+	}
+	return sessions
+}
+
+func (t *sessionTracker) close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.closed = true
+	for ch := range t.subscribers {
+		close(ch)
+	}
+	clear(t.subscribers)
+}
+
+// SubscribePeerEvents streams peer connect, roam and disconnect events.
+func (wg *WireGuard) SubscribePeerEvents(_ context.Context) (<-chan *common.PeerEvent, func(), error) {
+	wg.mu.RLock()
+	state := wg.state
+	wg.mu.RUnlock()
+
+	if state != lifecycleRunning {
+		return nil, nil, errWireGuardNotStarted
+	}
+	events, release := wg.sessions.subscribe()
+	return events, release, nil
+}
+
+// GetPeerSessions returns the recent sessions of a user.
+func (wg *WireGuard) GetPeerSessions(_ context.Context, email string) (*common.PeerSessionsResponse, error) {
+	wg.mu.RLock()
+	state := wg.state
+	wg.mu.RUnlock()
+
+	if state != lifecycleRunning {
+		return nil, errWireGuardNotStarted
+	}
+	if email == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+	return &common.PeerSessionsResponse{Email: email, Sessions: wg.sessions.sessions(email, time.Now())}, nil
+}
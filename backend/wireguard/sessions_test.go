@@ -0,0 +1,162 @@
+package wireguard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+
+	"github.com/pasarguard/node/common"
+	"github.com/pasarguard/node/config"
+	pkgstats "github.com/pasarguard/node/pkg/stats"
+)
+
+func nextPeerEvent(t *testing.T, events <-chan *common.PeerEvent) *common.PeerEvent {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	default:
+		t.Fatal("expected a peer event")
+		return nil
+	}
+}
+
+func TestSessionTrackerEmitsSessionEvents(t *testing.T) {
+	tracker := newSessionTracker("wg0")
+	events, release := tracker.subscribe()
+	defer release()
+
+	start := time.Unix(1_700_000_000, 0)
+	observe := func(now time.Time, endpoint string, handshake time.Time, rx int64) {
+		tracker.observe(now, []peerObservation{{publicKey: "key", email: "user@example.com", endpoint: endpoint, lastHandshake: handshake, rxBytes: rx}})
+	}
+
+	observe(start, "198.51.100.1:5000", start, 10)
+	if event := nextPeerEvent(t, events); event.GetType() != common.PeerEventType_CONNECTED || event.GetEndpoint() != "198.51.100.1:5000" || event.GetInbound() != "wg0" {
+		t.Fatalf("unexpected connect event: %v", event)
+	}
+
+	// The handshake is stale but traffic keeps the session alive across a roam.
+	roamAt := start.Add(2 * time.Minute)
+	observe(roamAt, "203.0.113.7:6000", start, 20)
+	if event := nextPeerEvent(t, events); event.GetType() != common.PeerEventType_ROAMED || event.GetPreviousEndpoint() != "198.51.100.1:5000" || event.GetDuration() != 120 {
+		t.Fatalf("unexpected roam event: %v", event)
+	}
+
+	endAt := start.Add(5 * time.Minute)
+	observe(endAt, "203.0.113.7:6000", start, 20)
+	if event := nextPeerEvent(t, events); event.GetType() != common.PeerEventType_DISCONNECTED || event.GetDuration() != 300 {
+		t.Fatalf("unexpected disconnect event: %v", event)
+	}
+
+	sessions := tracker.sessions("user@example.com", endAt)
+	if len(sessions) != 1 || len(sessions[0].GetEndpoints()) != 2 || sessions[0].GetEndedAt() != endAt.Unix() {
+		t.Fatalf("unexpected session history: %v", sessions)
+	}
+}
+
+func TestSessionTrackerBoundsHistory(t *testing.T) {
+	tracker := newSessionTracker("wg0")
+	now := time.Unix(1_700_000_000, 0)
+	for i := 0; i < peerSessionHistorySize+5; i++ {
+		tracker.observe(now, []peerObservation{{publicKey: "key", email: "user@example.com", lastHandshake: now}})
+		now = now.Add(time.Minute)
+		tracker.observe(now, nil)
+	}
+	tracker.observe(now, []peerObservation{{publicKey: "key", email: "user@example.com", lastHandshake: now}})
+
+	sessions := tracker.sessions("user@example.com", now)
+	if len(sessions) != peerSessionHistorySize+1 {
+		t.Fatalf("expected %d finished sessions and the active one, got %d", peerSessionHistorySize, len(sessions))
+	}
+	if last := sessions[len(sessions)-1]; last.GetEndedAt() != 0 {
+		t.Fatalf("expected the active session last, got %v", last)
+	}
+}
+
+func TestSessionTrackerCloseEndsSubscriptions(t *testing.T) {
+	tracker := newSessionTracker("wg0")
+	events, release := tracker.subscribe()
+	tracker.close()
+	if _, ok := <-events; ok {
+		t.Fatal("expected closed channel after close")
+	}
+	release()
+
+	late, _ := tracker.subscribe()
+	if _, ok := <-late; ok {
+		t.Fatal("expected closed channel when subscribing after close")
+	}
+}
+
+func TestSessionTrackerClosesSlowSubscribers(t *testing.T) {
+	tracker := newSessionTracker("wg0")
+	events, release := tracker.subscribe()
+	defer release()
+
+	now := time.Unix(1_700_000_000, 0)
+	for i := 0; i <= peerEventBufferSize; i++ {
+		tracker.observe(now, []peerObservation{{publicKey: "key", email: "user@example.com", lastHandshake: now}})
+		now = now.Add(time.Minute)
+		tracker.observe(now, nil)
+	}
+
+	received := 0
+	for range events {
+		received++
+	}
+	if received != peerEventBufferSize {
+		t.Fatalf("expected the buffered events before the close, got %d", received)
+	}
+
+	// A new subscription starts receiving again.
+	again, releaseAgain := tracker.subscribe()
+	defer releaseAgain()
+	tracker.observe(now, []peerObservation{{publicKey: "key", email: "user@example.com", lastHandshake: now}})
+	if event := nextPeerEvent(t, again); event.GetType() != common.PeerEventType_CONNECTED {
+		t.Fatalf("unexpected event after resubscribing: %v", event)
+	}
+}
+
+func TestUpdateConnectedPeersFeedsSessions(t *testing.T) {
+	key, _ := wgtypes.GeneratePrivateKey()
+	publicKey := key.PublicKey()
+
+	peerStore := NewPeerStore()
+	peerStore.Init([]*PeerInfo{{Email: "user@example.com", PublicKey: publicKey}})
+
+	wg := &WireGuard{
+		manager: &Manager{
+			iFaceName: "wg-test",
+			client: &fakeWGClient{
+				deviceFn: func(_ string) (*wgtypes.Device, error) {
+					return &wgtypes.Device{Peers: []wgtypes.Peer{{PublicKey: publicKey, LastHandshakeTime: time.Now()}}}, nil
+				},
+			},
+		},
+		cfg:          &config.Config{},
+		config:       &Config{InterfaceName: "wg-test"},
+		peerStore:    peerStore,
+		statsTracker: pkgstats.New(),
+		sessions:     newSessionTracker("wg-test"),
+		state:        lifecycleRunning,
+	}
+
+	events, release, err := wg.SubscribePeerEvents(context.Background())
+	if err != nil {
+		t.Fatalf("SubscribePeerEvents failed: %v", err)
+	}
+	defer release()
+
+	wg.updateConnectedPeers(context.Background())
+	if event := nextPeerEvent(t, events); event.GetEmail() != "user@example.com" || event.GetType() != common.PeerEventType_CONNECTED {
+		t.Fatalf("unexpected event: %v", event)
+	}
+
+	response, err := wg.GetPeerSessions(context.Background(), "user@example.com")
+	if err != nil || len(response.GetSessions()) != 1 {
+		t.Fatalf("expected one active session, got %v (%v)", response, err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"runtime"
+	"sort"
 	"time"
 
 	"github.com/pasarguard/node/common"
@@ -13,6 +14,9 @@ import (
 
 const onlineActivityThreshold = 45 * time.Second
 
+// statLinkTraffic is the link Xray reports for inbound and outbound traffic.
+const statLinkTraffic = "traffic"
+
 func (wg *WireGuard) getInterfaceCounters() (int64, int64, error) {
 	wg.mu.RLock()
 	mgr := wg.manager
@@ -41,9 +45,11 @@ func (wg *WireGuard) handleUsersStats(ctx context.Context, request *common.StatR
 	return wg.statsTracker.GetUsersStats(ctx, request.GetReset_()), nil
 }
 
-func (wg *WireGuard) handleInterfaceOutboundStats(name string, reset bool) (*common.StatResponse, error) {
-	if name == "" {
-		name = wg.config.InterfaceName
+// handleInboundStats reports the interface as the inbound, from the same link
+// counters the peers' traffic passes through. An empty name means the interface.
+func (wg *WireGuard) handleInboundStats(name string, reset bool) (*common.StatResponse, error) {
+	if name != "" && name != wg.config.InterfaceName {
+		return &common.StatResponse{Stats: []*common.Stat{}}, nil
 	}
 
 	currentRx, currentTx, err := wg.getInterfaceCounters()
@@ -51,12 +57,58 @@ func (wg *WireGuard) handleInterfaceOutboundStats(name string, reset bool) (*com
 		return nil, err
 	}
 
+	// Peers upload what the interface receives.
 	deltaRx, deltaTx := wg.interfaceStats.Delta(currentRx, currentTx, reset)
 	return &common.StatResponse{
-		Stats: stats.BuildInterfaceStats(name, "interface", deltaRx, deltaTx),
+		Stats: stats.BuildInterfaceStats(wg.config.InterfaceName, statLinkTraffic, deltaTx, deltaRx),
 	}, nil
 }
 
+// handleOutboundStats reports each egress interface as an outbound, with the
+// "traffic" link like Xray. An empty name means every egress. The interface
+// totals, once reported here under the interface name with the "interface"
+// link, are the inbound stat now. Without nftables counters the list is empty.
+func (wg *WireGuard) handleOutboundStats(name string, reset bool) (*common.StatResponse, error) {
+	wg.mu.RLock()
+	counters := wg.counters
+	wg.mu.RUnlock()
+
+	response := &common.StatResponse{Stats: []*common.Stat{}}
+	if counters == nil {
+		return response, nil
+	}
+	traffic, err := counters.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	egresses := make([]string, 0, len(traffic))
+	for egress := range traffic {
+		if name == "" || name == egress {
+			egresses = append(egresses, egress)
+		}
+	}
+	sort.Strings(egresses)
+
+	for _, egress := range egresses {
+		deltaDown, deltaUp := wg.egressTracker(egress).Delta(traffic[egress].down, traffic[egress].up, reset)
+		response.Stats = append(response.Stats, stats.BuildInterfaceStats(egress, statLinkTraffic, deltaDown, deltaUp)...)
+	}
+	return response, nil
+}
+
+func (wg *WireGuard) egressTracker(egress string) *stats.InterfaceCountersTracker {
+	wg.mu.Lock()
+	defer wg.mu.Unlock()
+
+	tracker, ok := wg.egressStats[egress]
+	if !ok {
+		tracker = stats.NewInterfaceCountersTracker()
+		wg.egressStats[egress] = tracker
+	}
+	return tracker
+}
+
 func (wg *WireGuard) GetStats(ctx context.Context, request *common.StatRequest) (*common.StatResponse, error) {
 	wg.mu.RLock()
 	state := wg.state
@@ -73,13 +125,22 @@ func (wg *WireGuard) GetStats(ctx context.Context, request *common.StatRequest)
 	case common.StatType_UsersStat:
 		return wg.handleUsersStats(ctx, request)
 
-	case common.StatType_Outbound, common.StatType_Outbounds:
-		// handleInterfaceOutboundStats falls back to the configured interface name when GetName() is empty,
-		// so both stat types are satisfied by the same call.
-		return wg.handleInterfaceOutboundStats(request.GetName(), request.GetReset_())
+	case common.StatType_Outbounds:
+		return wg.handleOutboundStats("", request.GetReset_())
+	case common.StatType_Outbound:
+		if request.GetName() == "" {
+			return nil, errors.New("tag required")
+		}
+		return wg.handleOutboundStats(request.GetName(), request.GetReset_())
+
+	case common.StatType_Inbounds:
+		return wg.handleInboundStats("", request.GetReset_())
+	case common.StatType_Inbound:
+		if request.GetName() == "" {
+			return nil, errors.New("tag required")
+		}
+		return wg.handleInboundStats(request.GetName(), request.GetReset_())
 
-	case common.StatType_Inbound, common.StatType_Inbounds:
-		return nil, errors.New("inbound stats not applicable for wireguard")
 	default:
 		return nil, errors.New("unsupported stat type")
 	}
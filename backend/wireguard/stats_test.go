@@ -56,7 +56,7 @@ func statValueByType(t *testing.T, resp *common.StatResponse, typ string) int64
 	return 0
 }
 
-func TestGetStatsOutboundsUsesInterfaceDeltaAndLink(t *testing.T) {
+func TestGetStatsInboundsUsesInterfaceDelta(t *testing.T) {
 	manager := managerWithInterfaceStatsSequence(t, []rxTxPair{
 		{rx: 100, tx: 200}, // baseline
 		{rx: 150, tx: 260}, // delta
@@ -70,7 +70,7 @@ func TestGetStatsOutboundsUsesInterfaceDeltaAndLink(t *testing.T) {
 	}
 
 	_, err := wg.GetStats(context.Background(), &common.StatRequest{
-		Type:   common.StatType_Outbounds,
+		Type:   common.StatType_Inbounds,
 		Reset_: false,
 	})
 	if err != nil {
@@ -78,31 +78,31 @@ func TestGetStatsOutboundsUsesInterfaceDeltaAndLink(t *testing.T) {
 	}
 
 	resp, err := wg.GetStats(context.Background(), &common.StatRequest{
-		Type:   common.StatType_Outbounds,
+		Type:   common.StatType_Inbounds,
 		Reset_: false,
 	})
 	if err != nil {
 		t.Fatalf("GetStats failed: %v", err)
 	}
 
-	if got := statValueByType(t, resp, "downlink"); got != 50 {
-		t.Fatalf("unexpected downlink delta: got %d want 50", got)
+	if got := statValueByType(t, resp, "uplink"); got != 50 {
+		t.Fatalf("unexpected uplink delta: got %d want 50", got)
 	}
-	if got := statValueByType(t, resp, "uplink"); got != 60 {
-		t.Fatalf("unexpected uplink delta: got %d want 60", got)
+	if got := statValueByType(t, resp, "downlink"); got != 60 {
+		t.Fatalf("unexpected downlink delta: got %d want 60", got)
 	}
 
 	for _, stat := range resp.GetStats() {
 		if stat.GetName() != "wg-test" {
 			t.Fatalf("unexpected stat name: got %s want wg-test", stat.GetName())
 		}
-		if stat.GetLink() != "interface" {
-			t.Fatalf("unexpected stat link: got %s want interface", stat.GetLink())
+		if stat.GetLink() != statLinkTraffic {
+			t.Fatalf("unexpected stat link: got %s want %s", stat.GetLink(), statLinkTraffic)
 		}
 	}
 }
 
-func TestGetStatsOutboundsResetBaseline(t *testing.T) {
+func TestGetStatsInboundsResetBaseline(t *testing.T) {
 	manager := managerWithInterfaceStatsSequence(t, []rxTxPair{
 		{rx: 100, tx: 200}, // baseline
 		{rx: 140, tx: 260}, // non-reset
@@ -117,49 +117,49 @@ func TestGetStatsOutboundsResetBaseline(t *testing.T) {
 		interfaceStats: pkgstats.NewInterfaceCountersTracker(),
 	}
 
-	_, err := wg.GetStats(context.Background(), &common.StatRequest{Type: common.StatType_Outbounds})
+	_, err := wg.GetStats(context.Background(), &common.StatRequest{Type: common.StatType_Inbounds})
 	if err != nil {
 		t.Fatalf("failed to prime baseline: %v", err)
 	}
 
-	resp, err := wg.GetStats(context.Background(), &common.StatRequest{Type: common.StatType_Outbounds})
+	resp, err := wg.GetStats(context.Background(), &common.StatRequest{Type: common.StatType_Inbounds})
 	if err != nil {
 		t.Fatalf("GetStats failed: %v", err)
 	}
-	if got := statValueByType(t, resp, "downlink"); got != 40 {
-		t.Fatalf("unexpected downlink delta before reset: got %d want 40", got)
+	if got := statValueByType(t, resp, "uplink"); got != 40 {
+		t.Fatalf("unexpected uplink delta before reset: got %d want 40", got)
 	}
-	if got := statValueByType(t, resp, "uplink"); got != 60 {
-		t.Fatalf("unexpected uplink delta before reset: got %d want 60", got)
+	if got := statValueByType(t, resp, "downlink"); got != 60 {
+		t.Fatalf("unexpected downlink delta before reset: got %d want 60", got)
 	}
 
 	resp, err = wg.GetStats(context.Background(), &common.StatRequest{
-		Type:   common.StatType_Outbounds,
+		Type:   common.StatType_Inbounds,
 		Reset_: true,
 	})
 	if err != nil {
 		t.Fatalf("GetStats reset failed: %v", err)
 	}
-	if got := statValueByType(t, resp, "downlink"); got != 60 {
-		t.Fatalf("unexpected downlink delta on reset: got %d want 60", got)
+	if got := statValueByType(t, resp, "uplink"); got != 60 {
+		t.Fatalf("unexpected uplink delta on reset: got %d want 60", got)
 	}
-	if got := statValueByType(t, resp, "uplink"); got != 100 {
-		t.Fatalf("unexpected uplink delta on reset: got %d want 100", got)
+	if got := statValueByType(t, resp, "downlink"); got != 100 {
+		t.Fatalf("unexpected downlink delta on reset: got %d want 100", got)
 	}
 
-	resp, err = wg.GetStats(context.Background(), &common.StatRequest{Type: common.StatType_Outbounds})
+	resp, err = wg.GetStats(context.Background(), &common.StatRequest{Type: common.StatType_Inbounds})
 	if err != nil {
 		t.Fatalf("GetStats post-reset failed: %v", err)
 	}
-	if got := statValueByType(t, resp, "downlink"); got != 10 {
-		t.Fatalf("unexpected downlink delta after reset: got %d want 10", got)
-	}
 	if got := statValueByType(t, resp, "uplink"); got != 10 {
 		t.Fatalf("unexpected uplink delta after reset: got %d want 10", got)
 	}
+	if got := statValueByType(t, resp, "downlink"); got != 10 {
+		t.Fatalf("unexpected downlink delta after reset: got %d want 10", got)
+	}
 }
 
-func TestGetStatsOutboundsRebasesOnCounterRollback(t *testing.T) {
+func TestGetStatsInboundsRebasesOnCounterRollback(t *testing.T) {
 	manager := managerWithInterfaceStatsSequence(t, []rxTxPair{
 		{rx: 200, tx: 300}, // baseline
 		{rx: 150, tx: 250}, // rollback/restart
@@ -173,12 +173,12 @@ func TestGetStatsOutboundsRebasesOnCounterRollback(t *testing.T) {
 		interfaceStats: pkgstats.NewInterfaceCountersTracker(),
 	}
 
-	_, err := wg.GetStats(context.Background(), &common.StatRequest{Type: common.StatType_Outbounds})
+	_, err := wg.GetStats(context.Background(), &common.StatRequest{Type: common.StatType_Inbounds})
 	if err != nil {
 		t.Fatalf("failed to prime baseline: %v", err)
 	}
 
-	resp, err := wg.GetStats(context.Background(), &common.StatRequest{Type: common.StatType_Outbounds})
+	resp, err := wg.GetStats(context.Background(), &common.StatRequest{Type: common.StatType_Inbounds})
 	if err != nil {
 		t.Fatalf("GetStats rollback failed: %v", err)
 	}
@@ -186,19 +186,19 @@ func TestGetStatsOutboundsRebasesOnCounterRollback(t *testing.T) {
 		t.Fatalf("expected zero stats immediately after rollback, got %d", len(resp.GetStats()))
 	}
 
-	resp, err = wg.GetStats(context.Background(), &common.StatRequest{Type: common.StatType_Outbounds})
+	resp, err = wg.GetStats(context.Background(), &common.StatRequest{Type: common.StatType_Inbounds})
 	if err != nil {
 		t.Fatalf("GetStats after rollback failed: %v", err)
 	}
-	if got := statValueByType(t, resp, "downlink"); got != 20 {
-		t.Fatalf("unexpected downlink delta after rollback rebase: got %d want 20", got)
+	if got := statValueByType(t, resp, "uplink"); got != 20 {
+		t.Fatalf("unexpected uplink delta after rollback rebase: got %d want 20", got)
 	}
-	if got := statValueByType(t, resp, "uplink"); got != 10 {
-		t.Fatalf("unexpected uplink delta after rollback rebase: got %d want 10", got)
+	if got := statValueByType(t, resp, "downlink"); got != 10 {
+		t.Fatalf("unexpected downlink delta after rollback rebase: got %d want 10", got)
 	}
 }
 
-func TestGetStatsOutboundUsesRequestNameAndInterfaceLink(t *testing.T) {
+func TestGetStatsInboundMatchesInterfaceName(t *testing.T) {
 	manager := managerWithInterfaceStatsSequence(t, []rxTxPair{
 		{rx: 10, tx: 20}, // baseline
 		{rx: 30, tx: 50}, // delta
@@ -211,33 +211,94 @@ func TestGetStatsOutboundUsesRequestNameAndInterfaceLink(t *testing.T) {
 		interfaceStats: pkgstats.NewInterfaceCountersTracker(),
 	}
 
-	_, err := wg.GetStats(context.Background(), &common.StatRequest{
-		Type: common.StatType_Outbound,
-		Name: "custom-outbound",
-	})
-	if err != nil {
+	request := &common.StatRequest{Type: common.StatType_Inbound, Name: "wg-test"}
+	if _, err := wg.GetStats(context.Background(), request); err != nil {
 		t.Fatalf("failed to prime baseline: %v", err)
 	}
+	resp, err := wg.GetStats(context.Background(), request)
+	if err != nil {
+		t.Fatalf("GetStats failed: %v", err)
+	}
+	if got := statValueByType(t, resp, "uplink"); got != 20 {
+		t.Fatalf("unexpected uplink delta: got %d want 20", got)
+	}
 
-	resp, err := wg.GetStats(context.Background(), &common.StatRequest{
-		Type: common.StatType_Outbound,
-		Name: "custom-outbound",
-	})
+	resp, err = wg.GetStats(context.Background(), &common.StatRequest{Type: common.StatType_Inbound, Name: "other"})
 	if err != nil {
 		t.Fatalf("GetStats failed: %v", err)
 	}
+	if len(resp.GetStats()) != 0 {
+		t.Fatalf("expected no stats for another inbound, got %v", resp.GetStats())
+	}
+	if _, err := wg.GetStats(context.Background(), &common.StatRequest{Type: common.StatType_Inbound}); err == nil {
+		t.Fatal("expected an error without an inbound name")
+	}
+}
+
+type fakeTrafficCounters struct {
+	traffic map[string]egressTraffic
+}
+
+func (f *fakeTrafficCounters) Install(_ []string) error { return nil }
+
+func (f *fakeTrafficCounters) Read() (map[string]egressTraffic, error) { return f.traffic, nil }
+
+func (f *fakeTrafficCounters) Close() error { return nil }
+
+func TestGetStatsOutboundsReportsEgressInterfaces(t *testing.T) {
+	counters := &fakeTrafficCounters{traffic: map[string]egressTraffic{
+		"eth0": {up: 100, down: 1000},
+		"eth1": {up: 10, down: 20},
+	}}
+	wg := &WireGuard{
+		config:      &Config{InterfaceName: "wg-test"},
+		state:       lifecycleRunning,
+		counters:    counters,
+		egressStats: make(map[string]*pkgstats.InterfaceCountersTracker),
+	}
+
+	// The first read sets the baseline of each egress.
+	if _, err := wg.GetStats(context.Background(), &common.StatRequest{Type: common.StatType_Outbounds}); err != nil {
+		t.Fatalf("failed to prime baseline: %v", err)
+	}
+	counters.traffic = map[string]egressTraffic{
+		"eth0": {up: 150, down: 1300},
+		"eth1": {up: 10, down: 25},
+	}
 
+	resp, err := wg.GetStats(context.Background(), &common.StatRequest{Type: common.StatType_Outbounds})
+	if err != nil {
+		t.Fatalf("GetStats failed: %v", err)
+	}
+	got := make(map[string]int64)
 	for _, stat := range resp.GetStats() {
-		if stat.GetName() != "custom-outbound" {
-			t.Fatalf("unexpected stat name: got %s want custom-outbound", stat.GetName())
+		if stat.GetLink() != statLinkTraffic {
+			t.Fatalf("unexpected stat link: got %s want %s", stat.GetLink(), statLinkTraffic)
+		}
+		got[stat.GetName()+"/"+stat.GetType()] = stat.GetValue()
+	}
+	want := map[string]int64{"eth0/uplink": 50, "eth0/downlink": 300, "eth1/downlink": 5}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected stats: got %v want %v", got, want)
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Fatalf("unexpected stats: got %v want %v", got, want)
 		}
-		if stat.GetLink() != "interface" {
-			t.Fatalf("unexpected stat link: got %s want interface", stat.GetLink())
+	}
+
+	resp, err = wg.GetStats(context.Background(), &common.StatRequest{Type: common.StatType_Outbound, Name: "eth1", Reset_: true})
+	if err != nil {
+		t.Fatalf("GetStats failed: %v", err)
+	}
+	for _, stat := range resp.GetStats() {
+		if stat.GetName() != "eth1" {
+			t.Fatalf("expected only eth1 stats, got %v", resp.GetStats())
 		}
 	}
 }
 
-func TestGetStatsOutboundsReturnsErrorWhenInterfaceStatsUnavailable(t *testing.T) {
+func TestGetStatsInboundsReturnsErrorWhenInterfaceStatsUnavailable(t *testing.T) {
 	wg := &WireGuard{
 		config: &Config{InterfaceName: "wg-test"},
 		state:  lifecycleRunning,
@@ -252,7 +313,7 @@ func TestGetStatsOutboundsReturnsErrorWhenInterfaceStatsUnavailable(t *testing.T
 	}
 
 	if _, err := wg.GetStats(context.Background(), &common.StatRequest{
-		Type: common.StatType_Outbounds,
+		Type: common.StatType_Inbounds,
 	}); err == nil {
 		t.Fatal("expected error when interface stats are unavailable")
 	}
@@ -0,0 +1,73 @@
+package wireguard
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+const (
+	defaultXrayTProxyMark  = 0x7067
+	defaultXrayTProxyTable = 0x7067
+)
+
+// XrayTProxyConfig points at the TPROXY inbound of an Xray running on the
+// same host: a dokodemo-door inbound with followRedirect and the "tproxy"
+// sockopt. Traffic handed to Xray is delivered locally and no longer passes
+// the forward chain, so Xray routing takes over the forwarding policy and rate
+// limits for it; peer to peer traffic is not redirected. A forwarding policy
+// is rejected on such interfaces, and peers with rates are reported.
+type XrayTProxyConfig struct {
+	Port int `json:"port"`
+	// Mark and Table route redirected packets to the local socket.
+	Mark  uint32 `json:"mark,omitempty"`
+	Table int    `json:"table,omitempty"`
+}
+
+// validate fills in the defaults and rejects values clashing with the
+// interface fwmark and routing table.
+func (t *XrayTProxyConfig) validate(c *Config) error {
+	if t == nil {
+		return nil
+	}
+	if t.Port <= 0 || t.Port > 65535 {
+		return fmt.Errorf("invalid port %d", t.Port)
+	}
+	if t.Mark == 0 {
+		t.Mark = defaultXrayTProxyMark
+	}
+	if t.Table == 0 {
+		t.Table = defaultXrayTProxyTable
+	}
+	if t.Mark > math.MaxInt32 {
+		return fmt.Errorf("mark must not exceed %d", math.MaxInt32)
+	}
+	if t.Table < 0 || (t.Table >= 253 && t.Table <= 255) {
+		return fmt.Errorf("invalid routing table %d", t.Table)
+	}
+	if t.Mark == c.FwMark || (c.Table != 0 && t.Table == c.Table) {
+		return errors.New("mark and table must differ from the interface fwmark and table")
+	}
+	if !c.Policy.empty() {
+		return errTProxyPolicy
+	}
+	return nil
+}
+
+// errTProxyPolicy is returned for a forwarding policy on an interface whose
+// traffic Xray receives before the forward hook would apply the policy.
+var errTProxyPolicy = errors.New("policy is bypassed by traffic redirected to xray, enforce it in xray routing instead")
+
+// tproxyRatedPeers returns the emails of peers with rate limits, which the
+// forward hook only applies to traffic that is not redirected to Xray.
+func tproxyRatedPeers(peers []*PeerInfo) []string {
+	var emails []string
+	for _, peer := range peers {
+		if peer.UploadRate > 0 || peer.DownloadRate > 0 {
+			emails = append(emails, peer.Email)
+		}
+	}
+	sort.Strings(emails)
+	return emails
+}
@@ -0,0 +1,171 @@
+//go:build linux
+
+package wireguard
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	nftTProxyTableFamily = "inet"
+	nftTProxyTableName   = "pg_node_wg_tproxy"
+	nftTProxyChain       = "prerouting"
+)
+
+// xrayTProxyRouting are the rules and routes of each mark and table pair,
+// kept until the last interface using them shuts down.
+var xrayTProxyRouting = newSharedHostState[[2]int]()
+
+// setupXrayTProxy redirects TCP and UDP forwarded from iface to the Xray
+// TPROXY inbound and returns the function that undoes it. Destinations on the
+// host and inside the interface networks are left alone.
+func setupXrayTProxy(iface string, cfg *XrayTProxyConfig, networks []*net.IPNet) (func() error, error) {
+	key := [2]int{int(cfg.Mark), cfg.Table}
+	if err := acquireXrayTProxyRouting(key); err != nil {
+		return nil, err
+	}
+
+	ownerID := newHostRoutingOwnerID(iface)
+	cleanup := func() error {
+		chain := nftBaseChain{family: nftTProxyTableFamily, table: nftTProxyTableName, name: nftTProxyChain}
+		return errors.Join(
+			removeNFTRulesWithCommentPrefix(chain, nftOwnerCommentPrefix(ownerID)),
+			releaseXrayTProxyRouting(key),
+		)
+	}
+
+	if err := ensureNFTTProxyChain(); err != nil {
+		_ = releaseXrayTProxyRouting(key)
+		return nil, err
+	}
+
+	var script strings.Builder
+	comment := nftString(fmt.Sprintf("%sowner=%s type=tproxy port=%d", nftRuleCommentPrefix, ownerID, cfg.Port))
+	for _, rule := range nftTProxyRules(iface, cfg, networks) {
+		fmt.Fprintf(&script, "add rule %s %s %s %s comment %s\n", nftTProxyTableFamily, nftTProxyTableName, nftTProxyChain, rule, comment)
+	}
+	if err := runNFTScript(script.String()); err != nil {
+		_ = releaseXrayTProxyRouting(key)
+		return nil, err
+	}
+	return cleanup, nil
+}
+
+func ensureNFTTProxyChain() error {
+	if err := runNFT("add", "table", nftTProxyTableFamily, nftTProxyTableName); err != nil && !nftAlreadyExists(err) {
+		return err
+	}
+	if err := runNFT(
+		"add", "chain", nftTProxyTableFamily, nftTProxyTableName, nftTProxyChain,
+		"{", "type", "filter", "hook", "prerouting", "priority", "mangle", ";", "policy", "accept", ";", "}",
+	); err != nil && !nftAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// nftTProxyRules returns the rule bodies redirecting traffic from iface, in
+// evaluation order.
+func nftTProxyRules(iface string, cfg *XrayTProxyConfig, networks []*net.IPNet) []string {
+	return append(nftForwardedOnlyRules(iface, networks),
+		fmt.Sprintf("iifname %q meta nfproto ipv4 meta l4proto { tcp, udp } tproxy ip to 127.0.0.1:%d meta mark set %d accept", iface, cfg.Port, cfg.Mark),
+		fmt.Sprintf("iifname %q meta nfproto ipv6 meta l4proto { tcp, udp } tproxy ip6 to [::1]:%d meta mark set %d accept", iface, cfg.Port, cfg.Mark),
+	)
+}
+
+// nftForwardedOnlyRules end a prerouting chain for traffic from iface to the
+// host itself or to the interface networks, leaving only traffic headed out.
+func nftForwardedOnlyRules(iface string, networks []*net.IPNet) []string {
+	rules := []string{fmt.Sprintf("iifname %q fib daddr type local return", iface)}
+
+	var v4, v6 []string
+	for _, network := range networks {
+		if network.IP.To4() != nil {
+			v4 = append(v4, network.String())
+		} else {
+			v6 = append(v6, network.String())
+		}
+	}
+	if len(v4) > 0 {
+		rules = append(rules, fmt.Sprintf("iifname %q ip daddr { %s } return", iface, strings.Join(v4, ", ")))
+	}
+	if len(v6) > 0 {
+		rules = append(rules, fmt.Sprintf("iifname %q ip6 daddr { %s } return", iface, strings.Join(v6, ", ")))
+	}
+	return rules
+}
+
+// xrayTProxyRoutes deliver packets carrying the mark to the local socket
+// through a table holding only local default routes.
+func xrayTProxyRoutes(table int) ([]*netlink.Route, error) {
+	lo, err := netlink.LinkByName("lo")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get loopback link: %w", err)
+	}
+
+	routes := make([]*netlink.Route, 0, 2)
+	for _, family := range []int{unix.AF_INET, unix.AF_INET6} {
+		dst := &net.IPNet{IP: net.IPv4zero, Mask: net.CIDRMask(0, 32)}
+		if family == unix.AF_INET6 {
+			dst = &net.IPNet{IP: net.IPv6zero, Mask: net.CIDRMask(0, 128)}
+		}
+		routes = append(routes, &netlink.Route{
+			LinkIndex: lo.Attrs().Index,
+			Dst:       dst,
+			Table:     table,
+			Type:      unix.RTN_LOCAL,
+			Scope:     netlink.SCOPE_HOST,
+			Family:    family,
+		})
+	}
+	return routes, nil
+}
+
+func acquireXrayTProxyRouting(key [2]int) error {
+	return xrayTProxyRouting.acquire(key, func() (func() error, error) {
+		routes, err := xrayTProxyRoutes(key[1])
+		if err != nil {
+			return nil, err
+		}
+		var added []*netlink.Route
+		undoRoutes := func() error {
+			var errs []error
+			for _, route := range added {
+				if err := netlink.RouteDel(route); err != nil && !errors.Is(err, syscall.ESRCH) {
+					errs = append(errs, err)
+				}
+			}
+			return errors.Join(errs...)
+		}
+		for _, route := range routes {
+			if err := netlink.RouteReplace(route); err != nil {
+				if route.Family == unix.AF_INET6 && errors.Is(err, syscall.EAFNOSUPPORT) {
+					continue
+				}
+				_ = undoRoutes()
+				return nil, fmt.Errorf("failed to add local route to table %d: %w", key[1], err)
+			}
+			added = append(added, route)
+		}
+
+		rules, err := addPolicyRules(defaultNetlinkOps{}, markRules(uint32(key[0]), key[1]))
+		if err != nil {
+			_ = undoRoutes()
+			return nil, fmt.Errorf("failed to add rule for mark %d: %w", key[0], err)
+		}
+		return func() error {
+			return errors.Join(deletePolicyRules(defaultNetlinkOps{}, rules), undoRoutes())
+		}, nil
+	})
+}
+
+func releaseXrayTProxyRouting(key [2]int) error {
+	return xrayTProxyRouting.release(key)
+}
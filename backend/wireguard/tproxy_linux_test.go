@@ -0,0 +1,26 @@
+//go:build linux
+
+package wireguard
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestNFTTProxyRules(t *testing.T) {
+	_, v4, _ := net.ParseCIDR("10.8.0.1/24")
+	_, v6, _ := net.ParseCIDR("fd00:8::1/64")
+	rules := nftTProxyRules("wg0", &XrayTProxyConfig{Port: 12345, Mark: 100}, []*net.IPNet{v4, v6})
+
+	want := []string{
+		`iifname "wg0" fib daddr type local return`,
+		`iifname "wg0" ip daddr { 10.8.0.0/24 } return`,
+		`iifname "wg0" ip6 daddr { fd00:8::/64 } return`,
+		`iifname "wg0" meta nfproto ipv4 meta l4proto { tcp, udp } tproxy ip to 127.0.0.1:12345 meta mark set 100 accept`,
+		`iifname "wg0" meta nfproto ipv6 meta l4proto { tcp, udp } tproxy ip6 to [::1]:12345 meta mark set 100 accept`,
+	}
+	if strings.Join(rules, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected rules:\n%s", strings.Join(rules, "\n"))
+	}
+}
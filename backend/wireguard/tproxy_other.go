@@ -0,0 +1,12 @@
+//go:build !linux
+
+package wireguard
+
+import (
+	"errors"
+	"net"
+)
+
+func setupXrayTProxy(string, *XrayTProxyConfig, []*net.IPNet) (func() error, error) {
+	return nil, errors.New("xray tproxy is only supported on linux")
+}
@@ -0,0 +1,66 @@
+package wireguard
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pasarguard/node/common"
+)
+
+func TestNewConfigXrayTProxy(t *testing.T) {
+	config, err := NewConfig(`{"xray_tproxy":{"port":12345}}`)
+	if err != nil {
+		t.Fatalf("NewConfig failed: %v", err)
+	}
+	if config.XrayTProxy.Mark != defaultXrayTProxyMark || config.XrayTProxy.Table != defaultXrayTProxyTable {
+		t.Fatalf("expected default mark and table, got %+v", config.XrayTProxy)
+	}
+
+	for name, configJSON := range map[string]string{
+		"missing port":     `{"xray_tproxy":{}}`,
+		"port too large":   `{"xray_tproxy":{"port":70000}}`,
+		"local table":      `{"xray_tproxy":{"port":12345,"table":255}}`,
+		"same fwmark":      `{"fwmark":7,"xray_tproxy":{"port":12345,"mark":7}}`,
+		"same route table": `{"fwmark":7,"table":200,"xray_tproxy":{"port":12345,"table":200}}`,
+		"with policy":      `{"policy":{"block_private_networks":true},"xray_tproxy":{"port":12345}}`,
+	} {
+		if _, err := NewConfig(configJSON); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestXrayTProxyRejectsRuntimePolicy(t *testing.T) {
+	cfg, err := NewConfig(`{"interface_name":"wg-tproxy","xray_tproxy":{"port":12345}}`)
+	if err != nil {
+		t.Fatalf("NewConfig failed: %v", err)
+	}
+	firewall := &fakePolicyFirewall{}
+	wg := &WireGuard{config: cfg, state: lifecycleRunning, policy: firewall, logChan: make(chan string, 4)}
+
+	request := &common.PolicyRequest{Policy: &common.Policy{BlockPeerToPeer: true}}
+	if err := wg.UpdatePolicy(context.Background(), request); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition, got %v", err)
+	}
+	if err := wg.UpdatePolicy(context.Background(), &common.PolicyRequest{}); err != nil {
+		t.Fatalf("expected clearing the policy to succeed, got %v", err)
+	}
+	if len(firewall.applied) != 1 || firewall.applied[0] != nil {
+		t.Fatalf("expected only the empty policy to be applied, got %+v", firewall.applied)
+	}
+}
+
+func TestTProxyRatedPeers(t *testing.T) {
+	peers := []*PeerInfo{
+		{Email: "b@example.com", DownloadRate: 1000},
+		{Email: "c@example.com"},
+		{Email: "a@example.com", UploadRate: 1000},
+	}
+	if got := tproxyRatedPeers(peers); !slices.Equal(got, []string{"a@example.com", "b@example.com"}) {
+		t.Fatalf("unexpected rated peers: %v", got)
+	}
+}
@@ -17,18 +17,37 @@ var (
 	keepAlive     = &tempKeepAlive
 )
 
-func buildAddConfig(publicKey wgtypes.Key, allowedIPs []net.IPNet, presharedKey *wgtypes.Key) wgtypes.PeerConfig {
+// buildAddConfig builds the kernel config of peer. The peer's own preshared key
+// and keepalive win over the interface-wide ones.
+func buildAddConfig(peer *PeerInfo, presharedKey *wgtypes.Key) wgtypes.PeerConfig {
 	config := wgtypes.PeerConfig{
-		PublicKey:                   publicKey,
-		AllowedIPs:                  allowedIPs,
+		PublicKey:                   peer.PublicKey,
+		AllowedIPs:                  peer.AllowedIPs,
 		PersistentKeepaliveInterval: keepAlive,
 	}
-	if presharedKey != nil {
+	if peer.PersistentKeepalive > 0 {
+		interval := peer.PersistentKeepalive
+		config.PersistentKeepaliveInterval = &interval
+	}
+	if peer.PresharedKey != nil {
+		config.PresharedKey = peer.PresharedKey
+	} else if presharedKey != nil {
 		config.PresharedKey = presharedKey
 	}
 	return config
 }
 
+// samePeerSession reports whether a and b use the same preshared key and keepalive.
+func samePeerSession(a, b *PeerInfo) bool {
+	if a.PersistentKeepalive != b.PersistentKeepalive {
+		return false
+	}
+	if a.PresharedKey == nil || b.PresharedKey == nil {
+		return a.PresharedKey == b.PresharedKey
+	}
+	return *a.PresharedKey == *b.PresharedKey
+}
+
 func buildRemoveConfig(publicKey wgtypes.Key) wgtypes.PeerConfig {
 	return wgtypes.PeerConfig{PublicKey: publicKey, Remove: true}
 }
@@ -38,6 +57,11 @@ type DesiredPeer struct {
 	PublicKey     string
 	ParsedKey     wgtypes.Key
 	AllowedIPNets []net.IPNet
+	UploadRate    uint64
+	DownloadRate  uint64
+	OutboundTag   string
+	PresharedKey  *wgtypes.Key
+	Keepalive     time.Duration
 }
 
 type SyncDiff struct {
@@ -55,9 +79,14 @@ func (wg *WireGuard) buildSyncDiff(
 	targetPeers := make(map[string]*PeerInfo, len(desiredPeers))
 	for key, desired := range desiredPeers {
 		targetPeers[key] = &PeerInfo{
-			Email:      desired.Email,
-			PublicKey:  desired.ParsedKey,
-			AllowedIPs: desired.AllowedIPNets,
+			Email:               desired.Email,
+			PublicKey:           desired.ParsedKey,
+			AllowedIPs:          desired.AllowedIPNets,
+			UploadRate:          desired.UploadRate,
+			DownloadRate:        desired.DownloadRate,
+			OutboundTag:         desired.OutboundTag,
+			PresharedKey:        desired.PresharedKey,
+			PersistentKeepalive: desired.Keepalive,
 		}
 	}
 
@@ -84,14 +113,21 @@ func (wg *WireGuard) buildSyncDiff(
 			}
 		}
 
-		if existing.Email != target.Email || !ipnetsEqual {
-			if !ipnetsEqual {
+		ratesEqual := existing.UploadRate == target.UploadRate && existing.DownloadRate == target.DownloadRate
+		sessionEqual := samePeerSession(existing, target)
+
+		if existing.Email != target.Email || !ipnetsEqual || !ratesEqual || !sessionEqual || existing.OutboundTag != target.OutboundTag {
+			if !ipnetsEqual || !sessionEqual {
 				config, err := buildAddConfigFromPeerInfo(target, psk)
 				if err != nil {
 					log.Printf("quarantining peer update %s due to config error: %v", target.Email, err)
 					continue
 				}
 				config.ReplaceAllowedIPs = true
+				if config.PresharedKey == nil {
+					// Clear a previous key; leaving it nil keeps the old one.
+					config.PresharedKey = &wgtypes.Key{}
+				}
 				peerConfigs = append(peerConfigs, config)
 			}
 			upsertByKey[key] = target
@@ -184,7 +220,7 @@ func buildAddConfigFromPeerInfo(peer *PeerInfo, presharedKey *wgtypes.Key) (wgty
 		return wgtypes.PeerConfig{}, fmt.Errorf("peer %s has no allowed IPs", peer.Email)
 	}
 
-	return buildAddConfig(peer.PublicKey, peer.AllowedIPs, presharedKey), nil
+	return buildAddConfig(peer, presharedKey), nil
 }
 
 func peerIPAllowedOnInterface(peerNet *net.IPNet, ifaceNets []*net.IPNet) bool {
@@ -210,9 +246,42 @@ func peerIPAllowedOnInterface(peerNet *net.IPNet, ifaceNets []*net.IPNet) bool {
 	return false
 }
 
-func (wg *WireGuard) collectDesiredPeers(users []*common.User) (map[string]*DesiredPeer, error) {
+// collectDesiredPeers turns users into the peers this interface should have.
+// reserved maps addresses held by peers outside users to their owners. Users
+// that cannot be added are skipped and returned as conflicts, keyed by email.
+func (wg *WireGuard) collectDesiredPeers(users []*common.User, reserved map[string]string) (map[string]*DesiredPeer, map[string]peerConflict) {
 	desiredPeers := make(map[string]*DesiredPeer)
-	seenIPs := make(map[string]string)
+	conflicts := make(map[string]peerConflict)
+
+	// Sorted so the same user wins an address clash on every sync.
+	users = slices.Clone(users)
+	sort.Slice(users, func(i, j int) bool { return users[i].GetEmail() < users[j].GetEmail() })
+
+	seenIPs := make(map[string]string, len(reserved))
+	for ip, owner := range reserved {
+		seenIPs[ip] = owner
+	}
+
+	// Addresses the panel assigned explicitly are never handed out by IPAM.
+	var taken map[string]string
+	if wg.ipam != nil {
+		taken = make(map[string]string, len(seenIPs))
+		for ip, owner := range seenIPs {
+			taken[ip] = owner
+		}
+		for _, user := range users {
+			if !shouldIncludeUserInInterface(user, wg.config.InterfaceName) {
+				continue
+			}
+			for _, peerIp := range user.GetProxies().GetWireguard().GetPeerIps() {
+				if _, ipNet, err := net.ParseCIDR(peerIp); err == nil {
+					if _, exists := taken[ipNet.String()]; !exists {
+						taken[ipNet.String()] = user.GetEmail()
+					}
+				}
+			}
+		}
+	}
 
 	for _, user := range users {
 		if !shouldIncludeUserInInterface(user, wg.config.InterfaceName) {
@@ -220,15 +289,44 @@ func (wg *WireGuard) collectDesiredPeers(users []*common.User) (map[string]*Desi
 		}
 
 		email := user.GetEmail()
-		publicKey := user.GetProxies().GetWireguard().GetPublicKey()
-		peerIps := user.GetProxies().GetWireguard().GetPeerIps()
+		wireguard := user.GetProxies().GetWireguard()
+		publicKey := wireguard.GetPublicKey()
+		peerIps := wireguard.GetPeerIps()
 
 		parsedKey, err := wgtypes.ParseKey(publicKey)
 		if err != nil {
 			log.Printf("quarantining user %s due to invalid public key: %v", email, err)
+			conflicts[email] = peerConflict{detail: fmt.Sprintf("invalid public key: %v", err)}
 			continue
 		}
 
+		var presharedKey *wgtypes.Key
+		if raw := wireguard.GetPresharedKey(); raw != "" {
+			parsedPSK, err := wgtypes.ParseKey(raw)
+			if err != nil {
+				log.Printf("quarantining user %s due to invalid preshared key: %v", email, err)
+				conflicts[email] = peerConflict{detail: fmt.Sprintf("invalid preshared key: %v", err)}
+				continue
+			}
+			presharedKey = &parsedPSK
+		}
+
+		if len(peerIps) == 0 {
+			if wg.ipam == nil {
+				continue
+			}
+			allocated, err := wg.ipam.Allocate(email, taken)
+			if err != nil {
+				log.Printf("quarantining user %s: ipam allocation failed: %v", email, err)
+				conflicts[email] = peerConflict{detail: err.Error()}
+				continue
+			}
+			for _, ip := range allocated {
+				taken[ip] = email
+			}
+			peerIps = allocated
+		}
+
 		ifaceNets := wg.config.InterfaceNetworks()
 
 		var allowedIPNets []net.IPNet
@@ -238,6 +336,7 @@ func (wg *WireGuard) collectDesiredPeers(users []*common.User) (map[string]*Desi
 			_, ipNet, err := net.ParseCIDR(peerIp)
 			if err != nil {
 				log.Printf("quarantining user %s due to invalid provided IP %s: %v", email, peerIp, err)
+				conflicts[email] = peerConflict{peerIP: peerIp, detail: fmt.Sprintf("invalid peer IP: %v", err)}
 				hasInvalidIP = true
 				break
 			}
@@ -254,9 +353,11 @@ func (wg *WireGuard) collectDesiredPeers(users []*common.User) (map[string]*Desi
 
 			canonicalIP := ipNet.String()
 			if existingEmail, exists := seenIPs[canonicalIP]; exists && existingEmail != email {
-				return nil, fmt.Errorf("duplicate wireguard allowed IP %s assigned to users %s and %s", canonicalIP, existingEmail, email)
+				log.Printf("quarantining user %s: wireguard allowed IP %s is already assigned to %s", email, canonicalIP, existingEmail)
+				conflicts[email] = peerConflict{peerIP: canonicalIP, detail: fmt.Sprintf("already assigned to %s", existingEmail)}
+				hasInvalidIP = true
+				break
 			}
-			seenIPs[canonicalIP] = email
 			allowedIPNets = append(allowedIPNets, *ipNet)
 		}
 
@@ -265,18 +366,28 @@ func (wg *WireGuard) collectDesiredPeers(users []*common.User) (map[string]*Desi
 		}
 
 		if existing, exists := desiredPeers[publicKey]; exists && existing.Email != email {
-			return nil, fmt.Errorf("wireguard public key %s is assigned to multiple users: %s and %s", publicKey, existing.Email, email)
+			log.Printf("quarantining user %s: wireguard public key %s is already assigned to %s", email, publicKey, existing.Email)
+			conflicts[email] = peerConflict{detail: fmt.Sprintf("public key already assigned to %s", existing.Email)}
+			continue
 		}
 
+		for _, ipNet := range allowedIPNets {
+			seenIPs[ipNet.String()] = email
+		}
 		desiredPeers[publicKey] = &DesiredPeer{
 			Email:         email,
 			PublicKey:     publicKey,
 			ParsedKey:     parsedKey,
 			AllowedIPNets: allowedIPNets,
+			UploadRate:    wireguard.GetUploadRate(),
+			DownloadRate:  wireguard.GetDownloadRate(),
+			OutboundTag:   user.GetOutboundTag(),
+			PresharedKey:  presharedKey,
+			Keepalive:     time.Duration(wireguard.GetPersistentKeepalive()) * time.Second,
 		}
 	}
 
-	return desiredPeers, nil
+	return desiredPeers, conflicts
 }
 
 func buildRemoveConfigsForPeers(removeSet map[string]*PeerInfo) ([]string, []wgtypes.PeerConfig) {
@@ -306,7 +417,9 @@ func shouldIncludeUserInInterface(user *common.User, interfaceName string) bool
 	return slices.Contains(user.GetInbounds(), interfaceName)
 }
 
-func normalizeUsers(users []*common.User) []*common.User {
+// normalizeUsers keeps the last entry of every WireGuard user. Users without
+// peer_ips are kept only when IPAM can allocate addresses for them.
+func normalizeUsers(users []*common.User, allowEmptyPeerIPs bool) []*common.User {
 	lastByEmail := make(map[string]*common.User, len(users))
 
 	for _, user := range users {
@@ -316,7 +429,7 @@ func normalizeUsers(users []*common.User) []*common.User {
 			user.GetProxies() == nil,
 			user.GetProxies().GetWireguard() == nil,
 			user.GetProxies().GetWireguard().GetPublicKey() == "",
-			len(user.GetProxies().GetWireguard().GetPeerIps()) == 0:
+			len(user.GetProxies().GetWireguard().GetPeerIps()) == 0 && !allowEmptyPeerIPs:
 			continue
 		}
 		lastByEmail[user.GetEmail()] = user
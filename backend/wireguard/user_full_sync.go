@@ -7,13 +7,13 @@ import (
 )
 
 func (wg *WireGuard) syncUsersFull(users []*common.User) error {
-	normalizedUsers := normalizeUsers(users)
+	normalizedUsers := normalizeUsers(users, wg.ipam != nil)
 	existingByKey := wg.buildExistingPeersByKeySnapshot()
 
-	desiredPeers, err := wg.collectDesiredPeers(normalizedUsers)
-	if err != nil {
-		return err
-	}
+	desiredPeers, conflicts := wg.collectDesiredPeers(normalizedUsers, nil)
+	defer wg.commitAllocations()
+	wg.conflicts.replace(conflicts, nil)
+	wg.retainAllocations(users)
 
 	diff, err := wg.buildSyncDiff(existingByKey, desiredPeers)
 	if err != nil {
@@ -50,6 +50,8 @@ func (wg *WireGuard) syncUsersFull(users []*common.User) error {
 	for _, key := range removedKeys {
 		wg.statsTracker.RemoveStats(key)
 	}
+	wg.syncRateLimits()
+	wg.syncPeerRoutes()
 
 	return nil
 }
@@ -19,8 +19,23 @@ func (wg *WireGuard) buildExistingPeersSubsetForTouched(touchedEmails map[string
 	return result
 }
 
+// untouchedPeerIPs maps the allowed IPs of peers outside touchedEmails to their
+// owners, so a partial update cannot take an address another peer holds.
+func (wg *WireGuard) untouchedPeerIPs(touchedEmails map[string]struct{}) map[string]string {
+	reserved := make(map[string]string)
+	for _, peer := range wg.peerStore.GetAll() {
+		if _, touched := touchedEmails[peer.Email]; touched {
+			continue
+		}
+		for _, ipNet := range peer.AllowedIPs {
+			reserved[ipNet.String()] = peer.Email
+		}
+	}
+	return reserved
+}
+
 func (wg *WireGuard) syncUsersPartialReconcile(users []*common.User) error {
-	normalizedUsers := normalizeUsers(users)
+	normalizedUsers := normalizeUsers(users, wg.ipam != nil)
 	touchedEmails := make(map[string]struct{}, len(normalizedUsers))
 	for _, user := range normalizedUsers {
 		touchedEmails[user.GetEmail()] = struct{}{}
@@ -28,10 +43,9 @@ func (wg *WireGuard) syncUsersPartialReconcile(users []*common.User) error {
 
 	existingSubset := wg.buildExistingPeersSubsetForTouched(touchedEmails)
 
-	desiredPeers, err := wg.collectDesiredPeers(normalizedUsers)
-	if err != nil {
-		return err
-	}
+	desiredPeers, conflicts := wg.collectDesiredPeers(normalizedUsers, wg.untouchedPeerIPs(touchedEmails))
+	defer wg.commitAllocations()
+	wg.conflicts.replace(conflicts, touchedEmails)
 
 	currentOwners := wg.peerStore.GetEmailMap()
 	for key, desired := range desiredPeers {
@@ -67,6 +81,8 @@ func (wg *WireGuard) syncUsersPartialReconcile(users []*common.User) error {
 	for _, key := range removedKeys {
 		wg.statsTracker.RemoveStats(key)
 	}
+	wg.syncRateLimits()
+	wg.syncPeerRoutes()
 
 	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"net"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/pasarguard/node/common"
 	"github.com/pasarguard/node/pkg/stats"
@@ -671,7 +672,7 @@ func TestUpdateUsersAllowsKeyTransferWhenBothUsersTouched(t *testing.T) {
 	}
 }
 
-func TestSyncUsersRejectsDuplicateIPsAcrossUsers(t *testing.T) {
+func TestSyncUsersReportsDuplicateIPsPerUser(t *testing.T) {
 	cfg, err := NewConfig(`{
 		"interface_name":"wg-test",
 		"listen_port":51820,
@@ -684,11 +685,21 @@ func TestSyncUsersRejectsDuplicateIPsAcrossUsers(t *testing.T) {
 	_, key1, _ := GenerateKeyPair()
 	_, key2, _ := GenerateKeyPair()
 
+	var applied []wgtypes.PeerConfig
 	wg := &WireGuard{
 		config:       cfg,
 		peerStore:    NewPeerStore(),
 		statsTracker: stats.New(),
-		state:        lifecycleRunning,
+		manager: &Manager{
+			iFaceName: "wg-test",
+			client: &fakeWGClient{
+				configureDeviceFn: func(interfaceName string, cfg wgtypes.Config) error {
+					applied = cfg.Peers
+					return nil
+				},
+			},
+		},
+		state: lifecycleRunning,
 	}
 
 	users := []*common.User{
@@ -712,12 +723,95 @@ func TestSyncUsersRejectsDuplicateIPsAcrossUsers(t *testing.T) {
 		},
 	}
 
-	err = wg.SyncUsers(context.Background(), users)
-	if err == nil {
-		t.Fatal("expected error for duplicate IPs")
+	if err := wg.SyncUsers(context.Background(), users); err != nil {
+		t.Fatalf("expected duplicate IPs to be reported per user, got: %v", err)
 	}
-	if !strings.Contains(err.Error(), "duplicate wireguard allowed IP") {
-		t.Fatalf("unexpected error: %v", err)
+
+	if len(applied) != 1 || applied[0].PublicKey.String() != key1 {
+		t.Fatalf("expected only the first user to be applied, got %+v", applied)
+	}
+
+	response, err := wg.GetPeerAllocations(context.Background())
+	if err != nil {
+		t.Fatalf("GetPeerAllocations failed: %v", err)
+	}
+	conflicts := response.GetConflicts()
+	if len(conflicts) != 1 || conflicts[0].GetEmail() != "user2@example.com" || conflicts[0].GetPeerIp() != "10.69.0.2/32" {
+		t.Fatalf("expected a conflict for user2, got %v", conflicts)
+	}
+	if !strings.Contains(conflicts[0].GetDetail(), "user1@example.com") {
+		t.Fatalf("expected the conflict to name the owner, got %q", conflicts[0].GetDetail())
+	}
+}
+
+func TestSyncUsersReportsDuplicateKeysPerUser(t *testing.T) {
+	cfg, err := NewConfig(`{
+		"interface_name":"wg-test",
+		"listen_port":51820,
+		"address":["10.69.0.1/24"]
+	}`)
+	if err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+
+	_, key, _ := GenerateKeyPair()
+
+	var applied []wgtypes.PeerConfig
+	wg := &WireGuard{
+		config:       cfg,
+		peerStore:    NewPeerStore(),
+		statsTracker: stats.New(),
+		manager: &Manager{
+			iFaceName: "wg-test",
+			client: &fakeWGClient{
+				configureDeviceFn: func(interfaceName string, cfg wgtypes.Config) error {
+					applied = cfg.Peers
+					return nil
+				},
+			},
+		},
+		state: lifecycleRunning,
+	}
+
+	users := []*common.User{
+		{
+			Email:    "user1@example.com",
+			Inbounds: []string{"wg-test"},
+			Proxies: &common.Proxy{
+				Wireguard: &common.Wireguard{
+					PublicKey: key, PeerIps: []string{"10.69.0.2/32"},
+				},
+			},
+		},
+		{
+			Email:    "user2@example.com",
+			Inbounds: []string{"wg-test"},
+			Proxies: &common.Proxy{
+				Wireguard: &common.Wireguard{
+					PublicKey: key, PeerIps: []string{"10.69.0.3/32"},
+				},
+			},
+		},
+	}
+
+	if err := wg.SyncUsers(context.Background(), users); err != nil {
+		t.Fatalf("expected duplicate keys to be reported per user, got: %v", err)
+	}
+
+	if len(applied) != 1 || applied[0].AllowedIPs[0].String() != "10.69.0.2/32" {
+		t.Fatalf("expected only the first user to be applied, got %+v", applied)
+	}
+
+	response, err := wg.GetPeerAllocations(context.Background())
+	if err != nil {
+		t.Fatalf("GetPeerAllocations failed: %v", err)
+	}
+	conflicts := response.GetConflicts()
+	if len(conflicts) != 1 || conflicts[0].GetEmail() != "user2@example.com" {
+		t.Fatalf("expected a conflict for user2, got %v", conflicts)
+	}
+	if !strings.Contains(conflicts[0].GetDetail(), "user1@example.com") {
+		t.Fatalf("expected the conflict to name the owner, got %q", conflicts[0].GetDetail())
 	}
 }
 
@@ -900,3 +994,238 @@ func mustPeerInfo(email, pubStr string, ips []string) *PeerInfo {
 		AllowedIPs: parsedIPs,
 	}
 }
+
+type fakeRateLimiter struct {
+	synced [][]*PeerInfo
+}
+
+func (f *fakeRateLimiter) Sync(peers []*PeerInfo) error {
+	f.synced = append(f.synced, peers)
+	return nil
+}
+
+func (f *fakeRateLimiter) Close() error { return nil }
+
+func TestUpdateUsersRateChangeSyncsLimitsWithoutApply(t *testing.T) {
+	cfg, err := NewConfig(`{
+		"interface_name":"wg-test",
+		"listen_port":51820,
+		"address":["10.65.0.1/24"]
+	}`)
+	if err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+
+	_, key, err := GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	ps := NewPeerStore()
+	ps.ReplaceAll([]*PeerInfo{mustPeerInfo("shaped@example.com", key, []string{"10.65.0.2/32"})})
+
+	applyCalls := 0
+	limiter := &fakeRateLimiter{}
+	wg := &WireGuard{
+		config:       cfg,
+		peerStore:    ps,
+		statsTracker: stats.New(),
+		manager: &Manager{
+			iFaceName: "wg-test",
+			client: &fakeWGClient{
+				configureDeviceFn: func(interfaceName string, cfg wgtypes.Config) error {
+					applyCalls++
+					return nil
+				},
+			},
+		},
+		rateLimiter: limiter,
+		state:       lifecycleRunning,
+	}
+
+	users := []*common.User{
+		{
+			Email:    "shaped@example.com",
+			Inbounds: []string{"wg-test"},
+			Proxies: &common.Proxy{
+				Wireguard: &common.Wireguard{
+					PublicKey: key, PeerIps: []string{"10.65.0.2/32"}, UploadRate: 1000, DownloadRate: 2000,
+				},
+			},
+		},
+	}
+
+	if err := wg.UpdateUsers(context.Background(), users); err != nil {
+		t.Fatalf("UpdateUsers failed: %v", err)
+	}
+
+	if applyCalls != 0 {
+		t.Fatalf("expected a rate-only change to skip ConfigureDevice, got %d calls", applyCalls)
+	}
+	peer := ps.GetByEmail("shaped@example.com")
+	if peer == nil || peer.UploadRate != 1000 || peer.DownloadRate != 2000 {
+		t.Fatalf("expected peer store to carry the new rates, got %+v", peer)
+	}
+	if len(limiter.synced) != 1 || len(limiter.synced[0]) != 1 || limiter.synced[0][0].DownloadRate != 2000 {
+		t.Fatalf("expected rate limiter to be synced with the new rates, got %+v", limiter.synced)
+	}
+}
+
+func TestUpdateUsersAppliesPerPeerPresharedKeyAndKeepalive(t *testing.T) {
+	cfg, err := NewConfig(`{
+		"interface_name":"wg-test",
+		"listen_port":51820,
+		"address":["10.65.0.1/24"]
+	}`)
+	if err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+
+	_, key, err := GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	psk, err := wgtypes.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate preshared key: %v", err)
+	}
+
+	ps := NewPeerStore()
+	ps.ReplaceAll([]*PeerInfo{mustPeerInfo("psk@example.com", key, []string{"10.65.0.2/32"})})
+
+	var applied []wgtypes.PeerConfig
+	wg := &WireGuard{
+		config:       cfg,
+		peerStore:    ps,
+		statsTracker: stats.New(),
+		manager: &Manager{
+			iFaceName: "wg-test",
+			client: &fakeWGClient{
+				configureDeviceFn: func(interfaceName string, cfg wgtypes.Config) error {
+					applied = cfg.Peers
+					return nil
+				},
+			},
+		},
+		state: lifecycleRunning,
+	}
+
+	user := &common.User{
+		Email:    "psk@example.com",
+		Inbounds: []string{"wg-test"},
+		Proxies: &common.Proxy{
+			Wireguard: &common.Wireguard{
+				PublicKey:           key,
+				PeerIps:             []string{"10.65.0.2/32"},
+				PresharedKey:        psk.String(),
+				PersistentKeepalive: 25,
+			},
+		},
+	}
+
+	if err := wg.UpdateUsers(context.Background(), []*common.User{user}); err != nil {
+		t.Fatalf("UpdateUsers failed: %v", err)
+	}
+	if len(applied) != 1 || applied[0].PresharedKey == nil || *applied[0].PresharedKey != psk {
+		t.Fatalf("expected the peer's preshared key to be applied, got %+v", applied)
+	}
+	if applied[0].PersistentKeepaliveInterval == nil || *applied[0].PersistentKeepaliveInterval != 25*time.Second {
+		t.Fatalf("expected keepalive 25s, got %v", applied[0].PersistentKeepaliveInterval)
+	}
+
+	// Dropping the per-peer key must clear it in the kernel.
+	applied = nil
+	user.Proxies.Wireguard.PresharedKey = ""
+	user.Proxies.Wireguard.PersistentKeepalive = 0
+	if err := wg.UpdateUsers(context.Background(), []*common.User{user}); err != nil {
+		t.Fatalf("UpdateUsers failed: %v", err)
+	}
+	if len(applied) != 1 || applied[0].PresharedKey == nil || *applied[0].PresharedKey != (wgtypes.Key{}) {
+		t.Fatalf("expected the preshared key to be cleared, got %+v", applied)
+	}
+	if *applied[0].PersistentKeepaliveInterval != 0 {
+		t.Fatalf("expected keepalive back to the interface default, got %v", *applied[0].PersistentKeepaliveInterval)
+	}
+}
+
+func TestSyncUsersAllocatesPeerIPsWithIPAM(t *testing.T) {
+	cfg, err := NewConfig(`{
+		"interface_name":"wg-test",
+		"listen_port":51820,
+		"address":["10.74.0.1/24"],
+		"ipam":true
+	}`)
+	if err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+
+	ipam, err := NewIPAM(cfg.Address, "")
+	if err != nil {
+		t.Fatalf("failed to create ipam: %v", err)
+	}
+
+	_, autoKey, _ := GenerateKeyPair()
+	_, panelKey, _ := GenerateKeyPair()
+
+	wg := &WireGuard{
+		config:       cfg,
+		peerStore:    NewPeerStore(),
+		statsTracker: stats.New(),
+		ipam:         ipam,
+		manager: &Manager{
+			iFaceName: "wg-test",
+			client: &fakeWGClient{
+				configureDeviceFn: func(interfaceName string, cfg wgtypes.Config) error { return nil },
+			},
+		},
+		state: lifecycleRunning,
+	}
+
+	users := []*common.User{
+		{
+			Email:    "auto@example.com",
+			Inbounds: []string{"wg-test"},
+			Proxies:  &common.Proxy{Wireguard: &common.Wireguard{PublicKey: autoKey}},
+		},
+		{
+			Email:    "panel@example.com",
+			Inbounds: []string{"wg-test"},
+			Proxies: &common.Proxy{
+				Wireguard: &common.Wireguard{PublicKey: panelKey, PeerIps: []string{"10.74.0.2/32"}},
+			},
+		},
+		{
+			Email:    "bad-key@example.com",
+			Inbounds: []string{"wg-test"},
+			Proxies:  &common.Proxy{Wireguard: &common.Wireguard{PublicKey: "not-a-key"}},
+		},
+		{
+			Email:    "same-key@example.com",
+			Inbounds: []string{"wg-test"},
+			Proxies:  &common.Proxy{Wireguard: &common.Wireguard{PublicKey: autoKey}},
+		},
+	}
+
+	if err := wg.SyncUsers(context.Background(), users); err != nil {
+		t.Fatalf("SyncUsers failed: %v", err)
+	}
+
+	response, err := wg.GetPeerAllocations(context.Background())
+	if err != nil {
+		t.Fatalf("GetPeerAllocations failed: %v", err)
+	}
+	// Quarantined users are reported and keep no address.
+	if len(response.GetAllocations()) != 2 || len(response.GetConflicts()) != 2 {
+		t.Fatalf("unexpected allocations: %v", response)
+	}
+	if bad := response.GetConflicts()[0]; bad.GetEmail() != "bad-key@example.com" || !strings.Contains(bad.GetDetail(), "invalid public key") {
+		t.Fatalf("expected the invalid key to be reported, got %v", bad)
+	}
+	auto := response.GetAllocations()[0]
+	if auto.GetEmail() != "auto@example.com" || !auto.GetAllocated() || len(auto.GetPeerIps()) != 1 || auto.GetPeerIps()[0] != "10.74.0.3/32" {
+		t.Fatalf("expected auto user to get the first address not taken by the panel, got %v", auto)
+	}
+	if panel := response.GetAllocations()[1]; panel.GetAllocated() {
+		t.Fatalf("expected panel-assigned address not to be marked allocated, got %v", panel)
+	}
+}
@@ -0,0 +1,67 @@
+package wireguard
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+
+	"golang.zx2c4.com/wireguard/conn"
+	"golang.zx2c4.com/wireguard/device"
+	"golang.zx2c4.com/wireguard/ipc"
+	"golang.zx2c4.com/wireguard/tun"
+)
+
+// userspaceDevice is a wireguard-go or amneziawg-go device and its UAPI listener.
+type userspaceDevice struct {
+	device interface{ Close() }
+	uapi   net.Listener
+}
+
+// startUserspaceDevice runs wireguard-go on a new TUN device and serves its
+// UAPI socket so the wgctrl client can configure it like a kernel interface.
+func startUserspaceDevice(interfaceName string) (io.Closer, error) {
+	tunDevice, err := tun.CreateTUN(interfaceName, device.DefaultMTU)
+	if err != nil {
+		return nil, fmt.Errorf("create tun: %w", err)
+	}
+
+	logger := device.NewLogger(device.LogLevelError, fmt.Sprintf("wireguard-go(%s): ", interfaceName))
+	dev := device.NewDevice(tunDevice, conn.NewDefaultBind(), logger)
+
+	uapiFile, err := ipc.UAPIOpen(interfaceName)
+	if err != nil {
+		dev.Close()
+		return nil, fmt.Errorf("open uapi socket: %w", err)
+	}
+	uapi, err := ipc.UAPIListen(interfaceName, uapiFile)
+	uapiFile.Close()
+	if err != nil {
+		dev.Close()
+		return nil, fmt.Errorf("listen on uapi socket: %w", err)
+	}
+
+	go func() {
+		for {
+			conn, err := uapi.Accept()
+			if err != nil {
+				return
+			}
+			go dev.IpcHandle(conn)
+		}
+	}()
+
+	return &userspaceDevice{device: dev, uapi: uapi}, nil
+}
+
+func (d *userspaceDevice) Close() error {
+	socketPath := d.uapi.Addr().String()
+	err := d.uapi.Close()
+	d.device.Close()
+	// The listener comes from a file descriptor, so closing it leaves the socket behind.
+	if removeErr := os.Remove(socketPath); removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) {
+		err = errors.Join(err, removeErr)
+	}
+	return err
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package wireguard
+
+import (
+	"errors"
+	"io"
+)
+
+func startUserspaceDevice(string) (io.Closer, error) {
+	return nil, errors.New("userspace wireguard is only supported on linux")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os/exec"
 	"sort"
@@ -65,6 +66,15 @@ type WireGuard struct {
 	lastStatsErrAt time.Time
 	newManager     newManagerFunc
 	hostRouting    func()
+	xrayTProxy     func() error
+	rateLimiter    rateLimiter
+	peerRouter     peerRouter
+	policy         policyFirewall
+	counters       trafficCounters
+	egressStats    map[string]*stats.InterfaceCountersTracker
+	sessions       *sessionTracker
+	ipam           *IPAM
+	conflicts      peerConflicts
 }
 
 // getWireGuardVersion fetches the wireguard-tools version
@@ -132,6 +142,7 @@ func newWithManagerFactory(cfg *config.Config, wgConfig *Config, users []*common
 		cfg:            cfg,
 		statsTracker:   stats.New(),
 		interfaceStats: stats.NewInterfaceCountersTracker(),
+		egressStats:    make(map[string]*stats.InterfaceCountersTracker),
 		peerStore:      NewPeerStore(),
 		logChan:        make(chan string, cfg.LogBufferSize),
 		startTime:      time.Now(),
@@ -147,6 +158,7 @@ func newWithManagerFactory(cfg *config.Config, wgConfig *Config, users []*common
 	}
 
 	wg.config = wgConfig
+	wg.sessions = newSessionTracker(wgConfig.InterfaceName)
 
 	log.Println("config loaded in", time.Since(start).Seconds(), "second.")
 
@@ -157,13 +169,22 @@ func newWithManagerFactory(cfg *config.Config, wgConfig *Config, users []*common
 		return nil, fmt.Errorf("invalid wireguard private key: %w", err)
 	}
 
-	normalizedUsers := normalizeUsers(users)
-	startupExistingByKey := wg.buildExistingPeersByKeySnapshot()
-	startupDesiredPeers, err := wg.collectDesiredPeers(normalizedUsers)
-	if err != nil {
-		return nil, fmt.Errorf("failed to collect desired peers: %w", err)
+	if err := checkFirewallFeatures(wgConfig); err != nil {
+		return nil, err
+	}
+
+	if wgConfig.IPAM {
+		wg.ipam, err = NewIPAM(wgConfig.Address, ipamStatePath(cfg, wgConfig.InterfaceName))
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize ipam: %w", err)
+		}
 	}
 
+	normalizedUsers := normalizeUsers(users, wg.ipam != nil)
+	startupExistingByKey := wg.buildExistingPeersByKeySnapshot()
+	startupDesiredPeers, startupConflicts := wg.collectDesiredPeers(normalizedUsers, nil)
+	wg.conflicts.replace(startupConflicts, nil)
+
 	startupDiff, err := wg.buildSyncDiff(startupExistingByKey, startupDesiredPeers)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build sync diff: %w", err)
@@ -175,6 +196,16 @@ func newWithManagerFactory(cfg *config.Config, wgConfig *Config, users []*common
 	if err != nil {
 		return nil, fmt.Errorf("failed to create manager: %w", err)
 	}
+	manager.mode = wgConfig.Mode
+	manager.mtu = wgConfig.MTU
+	manager.fwMark = int(wgConfig.FwMark)
+	manager.table = wgConfig.Table
+	if obfuscation := wgConfig.Obfuscation; obfuscation.enabled() {
+		manager.mode = ModeUserspace
+		manager.newUserspace = func(interfaceName string) (io.Closer, error) {
+			return startAmneziaDevice(interfaceName, obfuscation)
+		}
+	}
 
 	// Initialize the WireGuard interface with peers in the same kernel configure call.
 	if err = manager.InitializeWithPeers(privateKey, wgConfig.ListenPort, wgConfig.Address, startupPeerConfigs); err != nil {
@@ -183,13 +214,40 @@ func newWithManagerFactory(cfg *config.Config, wgConfig *Config, users []*common
 	}
 
 	// After the tunnel exists, apply optional host routing so nft iifname matches the real interface.
-	wg.hostRouting = applyLinuxHostRouting(wgConfig.InterfaceName)
+	wg.hostRouting = applyLinuxHostRouting(wgConfig.InterfaceName, wgConfig.Address)
+
+	// Without the redirect traffic would bypass Xray routing, so a failure stops the start.
+	if wgConfig.XrayTProxy != nil {
+		cleanup, err := setupXrayTProxy(wgConfig.InterfaceName, wgConfig.XrayTProxy, wgConfig.InterfaceNetworks())
+		if err != nil {
+			if wg.hostRouting != nil {
+				wg.hostRouting()
+				wg.hostRouting = nil
+			}
+			manager.Close()
+			return nil, fmt.Errorf("failed to redirect traffic to xray: %w", err)
+		}
+		wg.xrayTProxy = cleanup
+		wg.emitInfoLogf("WireGuard interface %s forwards through xray tproxy port %d", wgConfig.InterfaceName, wgConfig.XrayTProxy.Port)
+	}
 
 	wg.manager = manager
 
 	// Initialize PeerStore with successfully committed peers.
 	// We use Init() because the store is empty on startup and there are no users to remove.
 	wg.peerStore.Init(filterUpsertsByAppliedKeys(startupDiff.UpsertPeers, appliedKeys))
+	wg.commitAllocations()
+
+	wg.mu.Lock()
+	wg.rateLimiter = newRateLimiter(wgConfig.InterfaceName)
+	wg.peerRouter = newPeerRouter(wgConfig.InterfaceName, wgConfig.Outbounds, wgConfig.InterfaceNetworks())
+	wg.policy = newPolicyFirewall(wgConfig.InterfaceName)
+	wg.counters = newTrafficCounters(wgConfig.InterfaceName)
+	wg.mu.Unlock()
+	wg.syncRateLimits()
+	wg.syncPeerRoutes()
+	wg.syncPolicy()
+	wg.syncTrafficCounters()
 
 	// Initialize stats tickers
 	wg.initStatsTickers(wgCtx)
@@ -198,6 +256,11 @@ func newWithManagerFactory(cfg *config.Config, wgConfig *Config, users []*common
 	wg.state = lifecycleRunning
 	wg.mu.Unlock()
 
+	if wgConfig.Obfuscation.enabled() {
+		wg.emitInfoLogf("WireGuard interface %s is running in userspace with AmneziaWG obfuscation", wgConfig.InterfaceName)
+	} else if manager.Userspace() {
+		wg.emitInfoLogf("WireGuard interface %s is running in userspace (wireguard-go)", wgConfig.InterfaceName)
+	}
 	log.Println("wireguard started, Version:", wg.Version())
 	wg.emitInfoLogf("WireGuard interface %s initialized successfully", wgConfig.InterfaceName)
 
@@ -225,6 +288,11 @@ func (wg *WireGuard) Logs() <-chan string {
 	return wg.logChan
 }
 
+// LogFiles returns nil; WireGuard only logs to the in-memory channel.
+func (wg *WireGuard) LogFiles() []string {
+	return nil
+}
+
 // Restart applies a new configuration dynamically to the WireGuard interface without tearing it down.
 func (wg *WireGuard) Restart() error {
 	// syncMu prevents a concurrent SyncUser/UpdateUsers call from racing between
@@ -310,7 +378,7 @@ func buildPeerConfigsForRestart(peers []*PeerInfo, presharedKey *wgtypes.Key) ([
 			return nil, fmt.Errorf("peer %s has no allowed IPs", peer.Email)
 		}
 
-		peerConfigs = append(peerConfigs, buildAddConfig(peer.PublicKey, peer.AllowedIPs, presharedKey))
+		peerConfigs = append(peerConfigs, buildAddConfig(peer, presharedKey))
 	}
 
 	return peerConfigs, nil
@@ -329,6 +397,45 @@ func (wg *WireGuard) shutdownLocked() {
 		wg.cleanupTicker.Stop()
 	}
 
+	if wg.rateLimiter != nil {
+		if err := wg.rateLimiter.Close(); err != nil {
+			log.Printf("wireguard rate limits: cleanup failed: %v", err)
+		}
+		wg.rateLimiter = nil
+	}
+
+	if wg.peerRouter != nil {
+		if err := wg.peerRouter.Close(); err != nil {
+			log.Printf("wireguard peer outbounds: cleanup failed: %v", err)
+		}
+		wg.peerRouter = nil
+	}
+
+	if wg.policy != nil {
+		if err := wg.policy.Close(); err != nil {
+			log.Printf("wireguard policy: cleanup failed: %v", err)
+		}
+		wg.policy = nil
+	}
+
+	if wg.counters != nil {
+		if err := wg.counters.Close(); err != nil {
+			log.Printf("wireguard traffic counters: cleanup failed: %v", err)
+		}
+		wg.counters = nil
+	}
+
+	if wg.sessions != nil {
+		wg.sessions.close()
+	}
+
+	if wg.xrayTProxy != nil {
+		if err := wg.xrayTProxy(); err != nil {
+			log.Printf("wireguard xray tproxy: cleanup failed: %v", err)
+		}
+		wg.xrayTProxy = nil
+	}
+
 	if wg.hostRouting != nil {
 		wg.hostRouting()
 		wg.hostRouting = nil
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/xtls/xray-core/app/proxyman/command"
+	routingService "github.com/xtls/xray-core/app/router/command"
 	statsService "github.com/xtls/xray-core/app/stats/command"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -15,6 +16,7 @@ import (
 type XrayHandler struct {
 	HandlerServiceClient *command.HandlerServiceClient
 	StatsServiceClient   *statsService.StatsServiceClient
+	RoutingServiceClient *routingService.RoutingServiceClient
 	GrpcClient           *grpc.ClientConn
 }
 
@@ -53,8 +55,10 @@ func NewXrayAPI(apiPort int) (*XrayHandler, error) {
 
 	hsClient := command.NewHandlerServiceClient(x.GrpcClient)
 	ssClient := statsService.NewStatsServiceClient(x.GrpcClient)
+	rsClient := routingService.NewRoutingServiceClient(x.GrpcClient)
 	x.HandlerServiceClient = &hsClient
 	x.StatsServiceClient = &ssClient
+	x.RoutingServiceClient = &rsClient
 
 	return x, nil
 }
@@ -65,4 +69,5 @@ func (x *XrayHandler) Close() {
 	}
 	x.StatsServiceClient = nil
 	x.HandlerServiceClient = nil
+	x.RoutingServiceClient = nil
 }
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"context"
+	"errors"
+
+	"github.com/xtls/xray-core/app/router"
+	"github.com/xtls/xray-core/app/router/command"
+	"github.com/xtls/xray-core/common/serial"
+)
+
+// ReplaceRules swaps the whole routing table, including balancers, for config.
+// Xray can only append rules at runtime, so anything that has to run before
+// existing rules is applied by replacing the table.
+func (x *XrayHandler) ReplaceRules(ctx context.Context, config *router.Config) error {
+	if x.RoutingServiceClient == nil {
+		return errors.New("routing service is not available")
+	}
+	client := *x.RoutingServiceClient
+	_, err := client.AddRule(ctx, &command.AddRuleRequest{
+		Config:       serial.ToTypedMessage(config),
+		ShouldAppend: false,
+	})
+	return err
+}
+
+// AppendRules adds rules after the rules of the running routing table. Xray
+// adds them all or none of them.
+func (x *XrayHandler) AppendRules(ctx context.Context, rules []*router.RoutingRule) error {
+	if x.RoutingServiceClient == nil {
+		return errors.New("routing service is not available")
+	}
+	client := *x.RoutingServiceClient
+	_, err := client.AddRule(ctx, &command.AddRuleRequest{
+		Config:       serial.ToTypedMessage(&router.Config{Rule: rules}),
+		ShouldAppend: true,
+	})
+	return err
+}
+
+// RemoveRule removes the rule tagged ruleTag from the running routing table.
+func (x *XrayHandler) RemoveRule(ctx context.Context, ruleTag string) error {
+	if x.RoutingServiceClient == nil {
+		return errors.New("routing service is not available")
+	}
+	client := *x.RoutingServiceClient
+	_, err := client.RemoveRule(ctx, &command.RemoveRuleRequest{RuleTag: ruleTag})
+	return err
+}
+
+// OverrideBalancerTarget pins balancerTag to the target outbound; an empty
+// target hands the choice back to the balancer's strategy.
+func (x *XrayHandler) OverrideBalancerTarget(ctx context.Context, balancerTag, target string) error {
+	if x.RoutingServiceClient == nil {
+		return errors.New("routing service is not available")
+	}
+	client := *x.RoutingServiceClient
+	_, err := client.OverrideBalancerTarget(ctx, &command.OverrideBalancerTargetRequest{
+		BalancerTag: balancerTag,
+		Target:      target,
+	})
+	return err
+}
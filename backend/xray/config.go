@@ -41,6 +41,9 @@ type Config struct {
 	FakeDNS          map[string]any     `json:"fakeDns,omitempty"`
 	Observatory      map[string]any     `json:"observatory,omitempty"`
 	BurstObservatory map[string]any     `json:"burstObservatory,omitempty"`
+
+	// managedObservatory marks a burst observatory added by the node.
+	managedObservatory bool
 }
 
 type Inbound struct {
@@ -64,6 +67,9 @@ func (c *Config) syncUsers(users []*common.User) {
 		}
 		i.syncUsers(users)
 	}
+	if _, err := c.setUserOutbounds(users, true); err != nil {
+		log.Println("failed to set user outbounds:", err)
+	}
 }
 
 type inboundUpdate struct {
@@ -116,6 +122,9 @@ func (c *Config) updateUsers(users []*common.User) {
 		}
 		inbound.updateUsers(update.accounts, removeEmails)
 	}
+	if _, err := c.setUserOutbounds(users, false); err != nil {
+		log.Println("failed to set user outbounds:", err)
+	}
 }
 
 func (i *Inbound) syncUsers(users []*common.User) {
@@ -705,7 +714,7 @@ func (c *Config) ApplyAPI(apiPort, metricPort int) (err error) {
 	apiTag := "API"
 
 	c.API = &conf.APIConfig{
-		Services: []string{"HandlerService", "LoggerService", "StatsService"},
+		Services: []string{"HandlerService", "LoggerService", "StatsService", "RoutingService"},
 		Tag:      apiTag,
 	}
 
@@ -25,7 +25,49 @@ type debugVarsResponse struct {
 	Observatory map[string]observatoryEntry `json:"observatory"`
 }
 
-const xrayObservatoryReadTimeout = 5 * time.Second
+const (
+	xrayObservatoryReadTimeout = 5 * time.Second
+	managedObservatoryInterval = "1m"
+	managedObservatorySampling = 3
+)
+
+// Latency sources reported in common.Latency.
+const (
+	latencySourceObservatory        = "xray-observatory"
+	latencySourceManagedObservatory = "node-burst-observatory"
+)
+
+// applyManagedObservatory adds a burst observatory probing every outbound
+// through testURL when the config has no observatory, so latency is available
+// without one. An empty testURL turns this off.
+func (c *Config) applyManagedObservatory(testURL string, timeout time.Duration) {
+	if testURL == "" || c.Observatory != nil || c.BurstObservatory != nil {
+		return
+	}
+
+	protocolByTag := c.outboundProtocolByTag()
+	tags := make([]string, 0, len(protocolByTag))
+	for tag := range protocolByTag {
+		if shouldIncludeObservatoryOutbound(protocolByTag, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) == 0 {
+		return
+	}
+	sort.Strings(tags)
+
+	c.BurstObservatory = map[string]any{
+		"subjectSelector": tags,
+		"pingConfig": map[string]any{
+			"destination": testURL,
+			"interval":    managedObservatoryInterval,
+			"sampling":    managedObservatorySampling,
+			"timeout":     timeout.String(),
+		},
+	}
+	c.managedObservatory = true
+}
 
 func shouldIncludeObservatoryOutbound(protocolByTag map[string]string, tag string) bool {
 	protocol, ok := protocolByTag[tag]
@@ -41,8 +83,12 @@ func (x *Xray) GetOutboundsLatency(ctx context.Context, request *common.LatencyR
 	started := x.core != nil && x.core.Started()
 	metricPort := x.metricPort
 	protocolByTag := map[string]string(nil)
+	source := latencySourceObservatory
 	if x.config != nil {
 		protocolByTag = x.config.outboundProtocolByTag()
+		if x.config.managedObservatory {
+			source = latencySourceManagedObservatory
+		}
 	}
 	x.mu.RUnlock()
 
@@ -102,7 +148,7 @@ func (x *Xray) GetOutboundsLatency(ctx context.Context, request *common.LatencyR
 			Link:         linkName,
 			LastSeenTime: entry.LastSeenTime,
 			LastTryTime:  entry.LastTryTime,
-			Source:       "xray-observatory",
+			Source:       source,
 		})
 	}
 
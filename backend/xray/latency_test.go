@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/xtls/xray-core/infra/conf"
 )
@@ -65,6 +66,42 @@ func TestApplyAPISanitizesObservatorySelectors(t *testing.T) {
 	}
 }
 
+func TestApplyManagedObservatory(t *testing.T) {
+	outbounds := []any{
+		map[string]any{"tag": "proxy", "protocol": "vless"},
+		map[string]any{"tag": "direct", "protocol": "freedom"},
+		map[string]any{"tag": "blocked", "protocol": "blackhole"},
+	}
+
+	cfg := &Config{OutboundConfigs: outbounds}
+	cfg.applyManagedObservatory("https://example.com/204", 3*time.Second)
+	if !cfg.managedObservatory {
+		t.Fatal("expected a managed observatory")
+	}
+	if got := cfg.BurstObservatory["subjectSelector"].([]string); strings.Join(got, ",") != "direct,proxy" {
+		t.Fatalf("unexpected selectors: %v", got)
+	}
+	ping := cfg.BurstObservatory["pingConfig"].(map[string]any)
+	if ping["destination"] != "https://example.com/204" || ping["timeout"] != "3s" {
+		t.Fatalf("unexpected ping config: %v", ping)
+	}
+	if _, err := json.Marshal(cfg.BurstObservatory); err != nil {
+		t.Fatalf("failed to marshal burst observatory: %v", err)
+	}
+
+	own := &Config{OutboundConfigs: outbounds, Observatory: map[string]any{"subjectSelector": []any{"proxy"}}}
+	own.applyManagedObservatory("https://example.com/204", 3*time.Second)
+	if own.managedObservatory || own.BurstObservatory != nil {
+		t.Fatal("expected the configured observatory to be kept")
+	}
+
+	disabled := &Config{OutboundConfigs: outbounds}
+	disabled.applyManagedObservatory("", 3*time.Second)
+	if disabled.managedObservatory || disabled.BurstObservatory != nil {
+		t.Fatal("expected no observatory without a test url")
+	}
+}
+
 func TestApplyAPIAddsMalformedDomainGuardWhenBlackholeExists(t *testing.T) {
 	cfg := &Config{
 		InboundConfigs: []*Inbound{},
@@ -0,0 +1,232 @@
+package xray
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pasarguard/node/common"
+
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/infra/conf"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func outboundTag(raw any) string {
+	obj, _ := raw.(map[string]any)
+	tag, _ := obj["tag"].(string)
+	return strings.TrimSpace(tag)
+}
+
+func (c *Config) outboundIndex(tag string) int {
+	outbounds, _ := c.OutboundConfigs.([]any)
+	for i, outbound := range outbounds {
+		if outboundTag(outbound) == tag {
+			return i
+		}
+	}
+	return -1
+}
+
+// buildOutbound checks an outbound object the way xray does and returns it in
+// both the config form and the form the handler service takes.
+func buildOutbound(raw []byte) (map[string]any, *core.OutboundHandlerConfig, error) {
+	var obj map[string]any
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, nil, fmt.Errorf("invalid outbound: %w", err)
+	}
+
+	var detour conf.OutboundDetourConfig
+	if err := json.Unmarshal(raw, &detour); err != nil {
+		return nil, nil, fmt.Errorf("invalid outbound: %w", err)
+	}
+	if strings.TrimSpace(detour.Tag) == "" {
+		return nil, nil, fmt.Errorf("outbound tag is required")
+	}
+
+	built, err := detour.Build()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid outbound %q: %w", detour.Tag, err)
+	}
+	return obj, built, nil
+}
+
+// selectorMatches reports whether a balancer selector picks tag; xray matches selectors by prefix.
+func selectorMatches(selectors conf.StringList, tag string) bool {
+	for _, selector := range selectors {
+		if strings.HasPrefix(tag, selector) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkOutboundUnused fails while routing can still send traffic to tag,
+// through a rule, a balancer fallback, or a balancer left without outbounds.
+// This also covers the blackhole outbound the node's own rules point at.
+func (c *Config) checkOutboundUnused(tag string) error {
+	if c.RouterConfig == nil {
+		return nil
+	}
+
+	for _, raw := range c.RouterConfig.RuleList {
+		var obj map[string]any
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			continue
+		}
+		if outboundTag, _ := obj["outboundTag"].(string); outboundTag != tag {
+			continue
+		}
+		if ruleTag, _ := obj["ruleTag"].(string); ruleTag != "" {
+			return fmt.Errorf("outbound %q is used by routing rule %q", tag, ruleTag)
+		}
+		return fmt.Errorf("outbound %q is used by a routing rule", tag)
+	}
+
+	outbounds, _ := c.OutboundConfigs.([]any)
+	for _, balancer := range c.RouterConfig.Balancers {
+		if balancer == nil {
+			continue
+		}
+		if balancer.FallbackTag == tag {
+			return fmt.Errorf("outbound %q is the fallback of balancer %q", tag, balancer.Tag)
+		}
+		if !selectorMatches(balancer.Selectors, tag) {
+			continue
+		}
+		remaining := false
+		for _, outbound := range outbounds {
+			if other := outboundTag(outbound); other != tag && selectorMatches(balancer.Selectors, other) {
+				remaining = true
+				break
+			}
+		}
+		if !remaining {
+			return fmt.Errorf("outbound %q is the last outbound of balancer %q", tag, balancer.Tag)
+		}
+	}
+	return nil
+}
+
+// refreshManagedObservatoryLocked rebuilds the observatory the node added so
+// its subjects follow the outbounds after the next restart. Callers hold x.mu.
+func (x *Xray) refreshManagedObservatoryLocked() {
+	if !x.config.managedObservatory {
+		return
+	}
+	x.config.BurstObservatory = nil
+	x.config.managedObservatory = false
+	x.config.applyManagedObservatory(x.cfg.XrayLatencyTestURL, time.Duration(x.cfg.XrayLatencyTimeoutSeconds)*time.Second)
+}
+
+// AddOutbound adds an outbound to the running core and to the config, so it
+// is kept across restarts. With replace set, an outbound with the same tag is
+// swapped in place.
+func (x *Xray) AddOutbound(ctx context.Context, request *common.AddOutboundRequest) error {
+	obj, built, err := buildOutbound([]byte(request.GetConfig()))
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	tag := built.Tag
+	if tag == "API" {
+		return status.Errorf(codes.InvalidArgument, "outbound tag %q is reserved", tag)
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	index := x.config.outboundIndex(tag)
+	if index >= 0 && !request.GetReplace() {
+		return status.Errorf(codes.AlreadyExists, "outbound %q already exists", tag)
+	}
+
+	outbounds, _ := x.config.OutboundConfigs.([]any)
+	if index >= 0 {
+		if err = x.handler.RemoveOutbound(ctx, tag); err != nil {
+			return fmt.Errorf("failed to remove outbound %q: %w", tag, err)
+		}
+	}
+	if err = x.handler.AddOutbound(ctx, built); err != nil {
+		if index >= 0 {
+			// Put the previous outbound back so the core keeps routing to the tag.
+			if previous, marshalErr := json.Marshal(outbounds[index]); marshalErr == nil {
+				if _, old, buildErr := buildOutbound(previous); buildErr == nil {
+					_ = x.handler.AddOutbound(ctx, old)
+				}
+			}
+		}
+		return fmt.Errorf("failed to add outbound %q: %w", tag, err)
+	}
+
+	if index >= 0 {
+		outbounds[index] = obj
+	} else {
+		outbounds = append(outbounds, obj)
+	}
+	x.config.OutboundConfigs = outbounds
+	x.refreshManagedObservatoryLocked()
+	return nil
+}
+
+// RemoveOutbound removes an outbound that routing no longer uses.
+// The first outbound is xray's default route, so it can only be replaced.
+func (x *Xray) RemoveOutbound(ctx context.Context, tag string) error {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return status.Errorf(codes.InvalidArgument, "outbound tag is required")
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	index := x.config.outboundIndex(tag)
+	if index < 0 {
+		return status.Errorf(codes.NotFound, "outbound %q not found", tag)
+	}
+	if index == 0 {
+		return status.Errorf(codes.FailedPrecondition, "outbound %q is the default outbound, replace it instead", tag)
+	}
+	if err := x.config.checkOutboundUnused(tag); err != nil {
+		return status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	for balancerTag, target := range x.balancerOverrides {
+		if target == tag {
+			return status.Errorf(codes.FailedPrecondition, "outbound %q is the override target of balancer %q", tag, balancerTag)
+		}
+	}
+
+	if err := x.handler.RemoveOutbound(ctx, tag); err != nil {
+		return fmt.Errorf("failed to remove outbound %q: %w", tag, err)
+	}
+
+	outbounds, _ := x.config.OutboundConfigs.([]any)
+	x.config.OutboundConfigs = append(outbounds[:index:index], outbounds[index+1:]...)
+	x.refreshManagedObservatoryLocked()
+	return nil
+}
+
+// ListOutbounds returns the outbounds of the config, in order.
+func (x *Xray) ListOutbounds(_ context.Context) (*common.OutboundsResponse, error) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	outbounds, _ := x.config.OutboundConfigs.([]any)
+	response := &common.OutboundsResponse{Outbounds: make([]*common.OutboundConfig, 0, len(outbounds))}
+	for _, outbound := range outbounds {
+		raw, err := json.Marshal(outbound)
+		if err != nil {
+			return nil, err
+		}
+		obj, _ := outbound.(map[string]any)
+		protocol, _ := obj["protocol"].(string)
+		response.Outbounds = append(response.Outbounds, &common.OutboundConfig{
+			Tag:      outboundTag(outbound),
+			Protocol: protocol,
+			Config:   string(raw),
+		})
+	}
+	return response, nil
+}
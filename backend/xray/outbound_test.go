@@ -0,0 +1,72 @@
+package xray
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/xtls/xray-core/infra/conf"
+)
+
+func TestBuildOutbound(t *testing.T) {
+	obj, built, err := buildOutbound([]byte(`{"tag":"proxy","protocol":"freedom","settings":{}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if built.Tag != "proxy" || obj["protocol"] != "freedom" {
+		t.Fatalf("unexpected outbound: %v %v", obj, built.Tag)
+	}
+
+	for _, raw := range []string{
+		`not json`,
+		`{"protocol":"freedom"}`,
+		`{"tag":"proxy","protocol":"unknown"}`,
+	} {
+		if _, _, err := buildOutbound([]byte(raw)); err == nil {
+			t.Errorf("expected %s to be rejected", raw)
+		}
+	}
+}
+
+func TestCheckOutboundUnused(t *testing.T) {
+	cfg := &Config{
+		OutboundConfigs: []any{
+			map[string]any{"tag": "direct", "protocol": "freedom"},
+			map[string]any{"tag": "Block", "protocol": "blackhole"},
+			map[string]any{"tag": "proxy-a", "protocol": "freedom"},
+			map[string]any{"tag": "proxy-b", "protocol": "freedom"},
+			map[string]any{"tag": "chain-a", "protocol": "freedom"},
+			map[string]any{"tag": "spare", "protocol": "freedom"},
+		},
+		RouterConfig: &conf.RouterConfig{
+			RuleList: []json.RawMessage{
+				json.RawMessage(`{"type":"field","ip":["geoip:private"],"outboundTag":"Block"}`),
+				json.RawMessage(`{"type":"field","domain":["example.com"],"balancerTag":"pool"}`),
+			},
+			Balancers: []*conf.BalancingRule{
+				{Tag: "pool", Selectors: conf.StringList{"proxy-"}},
+				{Tag: "chain", Selectors: conf.StringList{"chain-"}, FallbackTag: "direct"},
+			},
+		},
+	}
+
+	cases := map[string]string{
+		"Block":   "routing rule",
+		"direct":  "fallback of balancer",
+		"chain-a": "last outbound of balancer",
+		"proxy-a": "",
+		"spare":   "",
+	}
+	for tag, want := range cases {
+		err := cfg.checkOutboundUnused(tag)
+		if want == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tag, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", tag, want, err)
+		}
+	}
+}
@@ -0,0 +1,624 @@
+package xray
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/pasarguard/node/common"
+
+	"github.com/xtls/xray-core/app/router"
+	"github.com/xtls/xray-core/infra/conf"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ipLimitRuleTagPrefix marks routing rules the node inserts to block a
+// user's extra source IPs. The email follows the prefix.
+const ipLimitRuleTagPrefix = "PG_NODE_IP_LIMIT:"
+
+// userOutboundRuleTagPrefix marks the rules sending users with an outbound_tag
+// through that outbound. The outbound tag follows the prefix.
+const userOutboundRuleTagPrefix = "PG_NODE_USER_OUTBOUND:"
+
+// nodeRulePrefixLen returns how many leading rules ApplyAPI put in front of
+// the panel's rules (the API rule and the malformed-domain guard).
+func (c *Config) nodeRulePrefixLen() int {
+	if c.RouterConfig == nil {
+		return 0
+	}
+
+	n := 0
+	for _, raw := range c.RouterConfig.RuleList {
+		var obj map[string]any
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			break
+		}
+		outboundTag, _ := obj["outboundTag"].(string)
+		ruleTag, _ := obj["ruleTag"].(string)
+		if outboundTag != "API" && ruleTag != malformedDomainGuardRuleTag {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+// protectedRulePrefixLen returns how many leading rules runtime rule changes
+// must keep in place: the node's own rules followed by the IP-limit rules.
+func (c *Config) protectedRulePrefixLen() int {
+	n := c.nodeRulePrefixLen()
+	if c.RouterConfig == nil {
+		return n
+	}
+	for _, raw := range c.RouterConfig.RuleList[n:] {
+		var obj map[string]any
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			break
+		}
+		if ruleTag, _ := obj["ruleTag"].(string); !strings.HasPrefix(ruleTag, ipLimitRuleTagPrefix) {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+func isNodeRuleTag(tag string) bool {
+	return tag == malformedDomainGuardRuleTag ||
+		strings.HasPrefix(tag, ipLimitRuleTagPrefix) ||
+		strings.HasPrefix(tag, userOutboundRuleTagPrefix)
+}
+
+// userOutboundRuleStart returns where the user outbound rules begin. They
+// match every connection of their users, so they are kept last to let the
+// other rules apply to those users first.
+func (c *Config) userOutboundRuleStart() int {
+	if c.RouterConfig == nil {
+		return 0
+	}
+	rules := c.RouterConfig.RuleList
+	start := len(rules)
+	for start > 0 {
+		var obj map[string]any
+		if err := json.Unmarshal(rules[start-1], &obj); err != nil {
+			break
+		}
+		if ruleTag, _ := obj["ruleTag"].(string); !strings.HasPrefix(ruleTag, userOutboundRuleTagPrefix) {
+			break
+		}
+		start--
+	}
+	return start
+}
+
+// setUserOutbounds moves each user into the rule of its outbound_tag, or out
+// of every rule when it has none or no inbounds. With full, users that are not
+// listed are dropped too. It reports whether the rules changed.
+func (c *Config) setUserOutbounds(users []*common.User, full bool) (bool, error) {
+	if c.RouterConfig == nil {
+		return false, nil
+	}
+
+	start := c.userOutboundRuleStart()
+	current := c.RouterConfig.RuleList[start:]
+	emailsByTag := make(map[string]map[string]struct{})
+	if !full {
+		for _, raw := range current {
+			var rule struct {
+				User    []string `json:"user"`
+				RuleTag string   `json:"ruleTag"`
+			}
+			if err := json.Unmarshal(raw, &rule); err != nil {
+				continue
+			}
+			emails := make(map[string]struct{}, len(rule.User))
+			for _, email := range rule.User {
+				emails[email] = struct{}{}
+			}
+			emailsByTag[strings.TrimPrefix(rule.RuleTag, userOutboundRuleTagPrefix)] = emails
+		}
+	}
+
+	for _, user := range users {
+		email := user.GetEmail()
+		for _, emails := range emailsByTag {
+			delete(emails, email)
+		}
+		tag := user.GetOutboundTag()
+		if tag == "" || len(user.GetInbounds()) == 0 {
+			continue
+		}
+		if c.outboundIndex(tag) < 0 {
+			log.Printf("outbound %q of user %s not found, using the default route", tag, email)
+			continue
+		}
+		if emailsByTag[tag] == nil {
+			emailsByTag[tag] = make(map[string]struct{})
+		}
+		emailsByTag[tag][email] = struct{}{}
+	}
+
+	ruleFor := func(tag string) (json.RawMessage, error) {
+		emails := make([]string, 0, len(emailsByTag[tag]))
+		for email := range emailsByTag[tag] {
+			emails = append(emails, email)
+		}
+		slices.Sort(emails)
+		return json.Marshal(map[string]any{
+			"type":        "field",
+			"user":        emails,
+			"outboundTag": tag,
+			"ruleTag":     userOutboundRuleTagPrefix + tag,
+		})
+	}
+
+	// Unchanged rules keep their place and changed ones move to the end, so
+	// the running table only swaps the rules that changed. The rules match
+	// different users, so their order does not matter.
+	rules := make([]json.RawMessage, 0, len(emailsByTag))
+	var moved []json.RawMessage
+	done := make(map[string]struct{}, len(emailsByTag))
+	for _, raw := range current {
+		tag := strings.TrimPrefix(routingRuleTag(raw), userOutboundRuleTagPrefix)
+		if len(emailsByTag[tag]) == 0 {
+			continue
+		}
+		rule, err := ruleFor(tag)
+		if err != nil {
+			return false, err
+		}
+		done[tag] = struct{}{}
+		if bytes.Equal(rule, raw) {
+			rules = append(rules, raw)
+		} else {
+			moved = append(moved, rule)
+		}
+	}
+	tags := make([]string, 0, len(emailsByTag))
+	for tag, emails := range emailsByTag {
+		if _, ok := done[tag]; !ok && len(emails) > 0 {
+			tags = append(tags, tag)
+		}
+	}
+	slices.Sort(tags)
+	for _, tag := range tags {
+		rule, err := ruleFor(tag)
+		if err != nil {
+			return false, err
+		}
+		moved = append(moved, rule)
+	}
+	rules = append(rules, moved...)
+
+	changed := !slices.EqualFunc(current, rules, func(a, b json.RawMessage) bool { return bytes.Equal(a, b) })
+	if changed {
+		c.RouterConfig.RuleList = append(slices.Clone(c.RouterConfig.RuleList[:start]), rules...)
+	}
+	return changed, nil
+}
+
+func (c *Config) routingRuleIndex(tag string) int {
+	if c.RouterConfig == nil {
+		return -1
+	}
+	for i, raw := range c.RouterConfig.RuleList {
+		var obj map[string]any
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			continue
+		}
+		if ruleTag, _ := obj["ruleTag"].(string); ruleTag == tag {
+			return i
+		}
+	}
+	return -1
+}
+
+func (c *Config) hasBalancer(tag string) bool {
+	if c.RouterConfig == nil {
+		return false
+	}
+	for _, balancer := range c.RouterConfig.Balancers {
+		if balancer != nil && balancer.Tag == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// setIPLimitRule replaces the IP-limit rule of email, or removes it when ips is empty.
+// The rule goes right after the node's own rules so panel rules cannot bypass it.
+func (c *Config) setIPLimitRule(email string, ips []string, outboundTag string) error {
+	if c.RouterConfig == nil {
+		return fmt.Errorf("routing is not configured")
+	}
+
+	tag := ipLimitRuleTagPrefix + email
+	rules := make([]json.RawMessage, 0, len(c.RouterConfig.RuleList)+1)
+	for _, raw := range c.RouterConfig.RuleList {
+		var obj map[string]any
+		if err := json.Unmarshal(raw, &obj); err == nil {
+			if ruleTag, _ := obj["ruleTag"].(string); ruleTag == tag {
+				continue
+			}
+		}
+		rules = append(rules, raw)
+	}
+	c.RouterConfig.RuleList = rules
+
+	if len(ips) == 0 {
+		return nil
+	}
+
+	rawBytes, err := json.Marshal(map[string]any{
+		"type":        "field",
+		"user":        []string{email},
+		"source":      ips,
+		"outboundTag": outboundTag,
+		"ruleTag":     tag,
+	})
+	if err != nil {
+		return err
+	}
+
+	at := c.nodeRulePrefixLen()
+	rules = append(rules[:at], append([]json.RawMessage{rawBytes}, rules[at:]...)...)
+	c.RouterConfig.RuleList = rules
+	return nil
+}
+
+// BlockUserIPs routes traffic of email coming from ips to the config's
+// blackhole outbound. An empty ips removes the block.
+func (x *Xray) BlockUserIPs(ctx context.Context, email string, ips []string) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	blockTag := x.config.blackholeOutboundTag()
+	if blockTag == "" && len(ips) > 0 {
+		return status.Errorf(codes.FailedPrecondition, "ip blocking needs a blackhole outbound in the xray config")
+	}
+
+	previous := x.config.RouterConfig.RuleList
+	if err := x.config.setIPLimitRule(email, ips, blockTag); err != nil {
+		return err
+	}
+	if slices.EqualFunc(previous, x.config.RouterConfig.RuleList, func(a, b json.RawMessage) bool { return bytes.Equal(a, b) }) {
+		return nil
+	}
+	return x.applyRoutingLocked(ctx, previous)
+}
+
+// syncUserOutbounds updates the user outbound rules for users and applies
+// them to the running routing table when they changed.
+func (x *Xray) syncUserOutbounds(ctx context.Context, users []*common.User) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	previous := x.config.RouterConfig.RuleList
+	changed, err := x.config.setUserOutbounds(users, false)
+	if err != nil || !changed {
+		return err
+	}
+	return x.applyRoutingLocked(ctx, previous)
+}
+
+// applyRoutingLocked brings the running routing table from the rules in
+// previous to the rules of the config. When that fails, the config goes back
+// to previous and the rules that were already changed are restored. Callers
+// hold x.mu.
+func (x *Xray) applyRoutingLocked(ctx context.Context, previous []json.RawMessage) error {
+	running, err := x.pushRulesLocked(ctx, previous, x.config.RouterConfig.RuleList)
+	if err == nil {
+		x.pruneBuiltRulesLocked()
+		return nil
+	}
+
+	restore := previous
+	if running != nil {
+		restore = userOutboundRulesLast(running, previous)
+	}
+	x.config.RouterConfig.RuleList = restore
+	if running == nil {
+		_, rollbackErr := x.replaceRulesLocked(ctx, nil, restore)
+		if rollbackErr != nil {
+			log.Printf("failed to restore the routing rules: %v", rollbackErr)
+		}
+	} else if _, rollbackErr := x.pushRulesLocked(ctx, running, restore); rollbackErr != nil {
+		log.Printf("failed to restore the routing rules: %v", rollbackErr)
+	}
+	return err
+}
+
+// userOutboundRulesLast returns the rules to roll back to from running. When
+// running is previous without some of its user outbound rules, those go back
+// after running, which matches the same connections and lets the rollback
+// append just them. Otherwise it is previous.
+func userOutboundRulesLast(running, previous []json.RawMessage) []json.RawMessage {
+	left := make(map[string]int, len(running))
+	for _, raw := range running {
+		left[string(raw)]++
+	}
+	restore := slices.Clone(running)
+	for _, raw := range previous {
+		if left[string(raw)] > 0 {
+			left[string(raw)]--
+			continue
+		}
+		if !strings.HasPrefix(routingRuleTag(raw), userOutboundRuleTagPrefix) {
+			return previous
+		}
+		restore = append(restore, raw)
+	}
+	for _, n := range left {
+		if n > 0 {
+			return previous
+		}
+	}
+	return restore
+}
+
+// pushRulesLocked changes the running routing table from running to desired.
+// Rules that are gone are removed by tag and rules that only follow the kept
+// ones are appended, so the other rules stay as they are. Anything else, such
+// as a rule that has to go in front of others, replaces the whole table.
+//
+// It returns the rules the core runs afterwards, which is nil when a failed
+// replace left them unknown.
+func (x *Xray) pushRulesLocked(ctx context.Context, running, desired []json.RawMessage) ([]json.RawMessage, error) {
+	wanted := make(map[string]int, len(desired))
+	for _, raw := range desired {
+		wanted[string(raw)]++
+	}
+	kept := make([]json.RawMessage, 0, len(running))
+	var stale []string
+	for _, raw := range running {
+		if wanted[string(raw)] > 0 {
+			wanted[string(raw)]--
+			kept = append(kept, raw)
+			continue
+		}
+		tag := routingRuleTag(raw)
+		if tag == "" {
+			return x.replaceRulesLocked(ctx, running, desired)
+		}
+		stale = append(stale, tag)
+	}
+	if !slices.EqualFunc(kept, desired[:len(kept)], func(a, b json.RawMessage) bool { return bytes.Equal(a, b) }) {
+		return x.replaceRulesLocked(ctx, running, desired)
+	}
+
+	fresh, err := x.buildRulesLocked(desired[len(kept):])
+	if err != nil {
+		return running, err
+	}
+	for i, tag := range stale {
+		if err = x.handler.RemoveRule(ctx, tag); err != nil {
+			return withoutRuleTags(running, stale[:i]), fmt.Errorf("failed to remove routing rule %q: %w", tag, err)
+		}
+	}
+	if len(fresh) > 0 {
+		if err = x.handler.AppendRules(ctx, fresh); err != nil {
+			return kept, fmt.Errorf("failed to add routing rules: %w", err)
+		}
+	}
+	return desired, nil
+}
+
+// replaceRulesLocked replaces the running routing table with rules and the
+// config's balancers.
+func (x *Xray) replaceRulesLocked(ctx context.Context, running, rules []json.RawMessage) ([]json.RawMessage, error) {
+	built, err := x.buildRulesLocked(rules)
+	if err != nil {
+		return running, err
+	}
+	routerConfig, err := (&conf.RouterConfig{
+		DomainStrategy: x.config.RouterConfig.DomainStrategy,
+		Balancers:      x.config.RouterConfig.Balancers,
+	}).Build()
+	if err != nil {
+		return running, fmt.Errorf("failed to build routing config: %w", err)
+	}
+	routerConfig.Rule = built
+
+	if err = x.handler.ReplaceRules(ctx, routerConfig); err != nil {
+		return nil, err
+	}
+
+	// Replacing the table rebuilds the balancers, which drops their overrides.
+	for balancerTag, target := range x.balancerOverrides {
+		if err = x.handler.OverrideBalancerTarget(ctx, balancerTag, target); err != nil {
+			return rules, fmt.Errorf("failed to restore the target of balancer %q: %w", balancerTag, err)
+		}
+	}
+	return rules, nil
+}
+
+// buildRulesLocked builds rules for the routing service. Geoip and geosite
+// rules load their lists in the node process, from XRAY_LOCATION_ASSET or
+// Xray's default asset directories, so each rule is built once and kept for
+// as long as it is in the config.
+func (x *Xray) buildRulesLocked(rules []json.RawMessage) ([]*router.RoutingRule, error) {
+	if x.builtRules == nil {
+		x.builtRules = make(map[string]*router.RoutingRule)
+	}
+	built := make([]*router.RoutingRule, 0, len(rules))
+	for _, raw := range rules {
+		rule, ok := x.builtRules[string(raw)]
+		if !ok {
+			routerConfig, err := (&conf.RouterConfig{RuleList: []json.RawMessage{raw}}).Build()
+			if err != nil {
+				return nil, fmt.Errorf("failed to build routing rule: %w", err)
+			}
+			rule = routerConfig.Rule[0]
+			x.builtRules[string(raw)] = rule
+		}
+		built = append(built, rule)
+	}
+	return built, nil
+}
+
+// pruneBuiltRulesLocked drops the built rules that left the config.
+func (x *Xray) pruneBuiltRulesLocked() {
+	current := make(map[string]struct{}, len(x.config.RouterConfig.RuleList))
+	for _, raw := range x.config.RouterConfig.RuleList {
+		current[string(raw)] = struct{}{}
+	}
+	for raw := range x.builtRules {
+		if _, ok := current[raw]; !ok {
+			delete(x.builtRules, raw)
+		}
+	}
+}
+
+func routingRuleTag(raw json.RawMessage) string {
+	var rule struct {
+		RuleTag string `json:"ruleTag"`
+	}
+	_ = json.Unmarshal(raw, &rule)
+	return rule.RuleTag
+}
+
+// withoutRuleTags returns rules without the rules tagged with one of tags.
+func withoutRuleTags(rules []json.RawMessage, tags []string) []json.RawMessage {
+	return slices.DeleteFunc(slices.Clone(rules), func(raw json.RawMessage) bool {
+		return slices.Contains(tags, routingRuleTag(raw))
+	})
+}
+
+// AddRoutingRule adds a tagged rule to the running routing table and to the
+// config, after the config's rules or, with prepend, before them. The IP-limit
+// and API rules stay in front and the user outbound rules stay last.
+func (x *Xray) AddRoutingRule(ctx context.Context, request *common.AddRoutingRuleRequest) error {
+	var rule bytes.Buffer
+	if err := json.Compact(&rule, []byte(request.GetConfig())); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid routing rule: %v", err)
+	}
+	var obj map[string]any
+	if err := json.Unmarshal(rule.Bytes(), &obj); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid routing rule: %v", err)
+	}
+
+	tag, _ := obj["ruleTag"].(string)
+	if tag == "" {
+		return status.Errorf(codes.InvalidArgument, "ruleTag is required")
+	}
+	if isNodeRuleTag(tag) {
+		return status.Errorf(codes.InvalidArgument, "ruleTag %q is reserved", tag)
+	}
+	if inboundTags, ok := obj["inboundTag"].([]any); ok && slices.Contains(inboundTags, any("API_INBOUND")) {
+		return status.Errorf(codes.InvalidArgument, "rules cannot match the API inbound")
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	outboundTag, _ := obj["outboundTag"].(string)
+	balancerTag, _ := obj["balancerTag"].(string)
+	switch {
+	case outboundTag == "API":
+		return status.Errorf(codes.InvalidArgument, "rules cannot route to the API")
+	case outboundTag != "":
+		if x.config.outboundIndex(outboundTag) < 0 {
+			return status.Errorf(codes.NotFound, "outbound %q not found", outboundTag)
+		}
+	case balancerTag != "":
+		if !x.config.hasBalancer(balancerTag) {
+			return status.Errorf(codes.NotFound, "balancer %q not found", balancerTag)
+		}
+	default:
+		return status.Errorf(codes.InvalidArgument, "rule needs an outboundTag or a balancerTag")
+	}
+
+	if x.config.RouterConfig == nil {
+		return status.Errorf(codes.FailedPrecondition, "routing is not configured")
+	}
+	if x.config.routingRuleIndex(tag) >= 0 {
+		return status.Errorf(codes.AlreadyExists, "routing rule %q already exists", tag)
+	}
+
+	previous := x.config.RouterConfig.RuleList
+	rules := slices.Clone(previous)
+	if request.GetPrepend() {
+		rules = slices.Insert(rules, x.config.protectedRulePrefixLen(), json.RawMessage(rule.Bytes()))
+	} else {
+		rules = slices.Insert(rules, x.config.userOutboundRuleStart(), json.RawMessage(rule.Bytes()))
+	}
+	x.config.RouterConfig.RuleList = rules
+	return x.applyRoutingLocked(ctx, previous)
+}
+
+// RemoveRoutingRule removes a tagged rule. Rules the node manages cannot be removed.
+func (x *Xray) RemoveRoutingRule(ctx context.Context, tag string) error {
+	if tag == "" {
+		return status.Errorf(codes.InvalidArgument, "ruleTag is required")
+	}
+	if isNodeRuleTag(tag) {
+		return status.Errorf(codes.FailedPrecondition, "routing rule %q is managed by the node", tag)
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	index := x.config.routingRuleIndex(tag)
+	if index < 0 {
+		return status.Errorf(codes.NotFound, "routing rule %q not found", tag)
+	}
+
+	previous := x.config.RouterConfig.RuleList
+	x.config.RouterConfig.RuleList = slices.Delete(slices.Clone(previous), index, index+1)
+	return x.applyRoutingLocked(ctx, previous)
+}
+
+// ListRoutingRules returns the rules of the config in match order, including the node's own.
+func (x *Xray) ListRoutingRules(_ context.Context) (*common.RoutingRulesResponse, error) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	response := &common.RoutingRulesResponse{Rules: []*common.RoutingRule{}}
+	if x.config.RouterConfig == nil {
+		return response, nil
+	}
+	for _, raw := range x.config.RouterConfig.RuleList {
+		var obj map[string]any
+		_ = json.Unmarshal(raw, &obj)
+		tag, _ := obj["ruleTag"].(string)
+		response.Rules = append(response.Rules, &common.RoutingRule{Tag: tag, Config: string(raw)})
+	}
+	return response, nil
+}
+
+// OverrideBalancerTarget pins a balancer to one of the outbounds until it is
+// cleared with an empty target or the core restarts.
+func (x *Xray) OverrideBalancerTarget(ctx context.Context, balancerTag, target string) error {
+	if balancerTag == "" {
+		return status.Errorf(codes.InvalidArgument, "balancer tag is required")
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if !x.config.hasBalancer(balancerTag) {
+		return status.Errorf(codes.NotFound, "balancer %q not found", balancerTag)
+	}
+	if target != "" && x.config.outboundIndex(target) < 0 {
+		return status.Errorf(codes.NotFound, "outbound %q not found", target)
+	}
+
+	if err := x.handler.OverrideBalancerTarget(ctx, balancerTag, target); err != nil {
+		return fmt.Errorf("failed to override balancer %q: %w", balancerTag, err)
+	}
+
+	if target == "" {
+		delete(x.balancerOverrides, balancerTag)
+		return nil
+	}
+	if x.balancerOverrides == nil {
+		x.balancerOverrides = make(map[string]string)
+	}
+	x.balancerOverrides[balancerTag] = target
+	return nil
+}
@@ -0,0 +1,179 @@
+package xray
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pasarguard/node/common"
+
+	"github.com/xtls/xray-core/infra/conf"
+)
+
+func TestSetIPLimitRulePlacesRuleAfterNodeRules(t *testing.T) {
+	cfg := &Config{
+		InboundConfigs: []*Inbound{},
+		OutboundConfigs: []any{
+			map[string]any{"tag": "direct", "protocol": "freedom"},
+			map[string]any{"tag": "Block", "protocol": "blackhole"},
+		},
+		RouterConfig: &conf.RouterConfig{
+			RuleList: []json.RawMessage{
+				json.RawMessage(`{"type":"field","ip":["geoip:private"],"outboundTag":"Block"}`),
+			},
+		},
+	}
+
+	if err := cfg.ApplyAPI(10001, 10002); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.setIPLimitRule("alice", []string{"10.0.0.2"}, "Block"); err != nil {
+		t.Fatal(err)
+	}
+
+	rules := cfg.RouterConfig.RuleList
+	if len(rules) != 4 {
+		t.Fatalf("expected 4 rules, got %d", len(rules))
+	}
+	if rule := string(rules[2]); !containsAll(rule, ipLimitRuleTagPrefix+"alice", `"source":["10.0.0.2"]`, `"user":["alice"]`) {
+		t.Fatalf("unexpected ip limit rule: %s", rule)
+	}
+	if rule := string(rules[3]); !strings.Contains(rule, `"ip":[`) {
+		t.Fatalf("expected panel rule to follow, got %s", rule)
+	}
+
+	// Replacing keeps a single rule, and clearing removes it.
+	if err := cfg.setIPLimitRule("alice", []string{"10.0.0.3"}, "Block"); err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.RouterConfig.RuleList) != 4 || !strings.Contains(string(cfg.RouterConfig.RuleList[2]), "10.0.0.3") {
+		t.Fatalf("expected the rule to be replaced, got %d rules", len(cfg.RouterConfig.RuleList))
+	}
+	if err := cfg.setIPLimitRule("alice", nil, "Block"); err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.RouterConfig.RuleList) != 3 {
+		t.Fatalf("expected the rule to be removed, got %d rules", len(cfg.RouterConfig.RuleList))
+	}
+}
+
+func TestProtectedRulePrefixLenCoversIPLimitRules(t *testing.T) {
+	cfg := &Config{
+		InboundConfigs: []*Inbound{},
+		OutboundConfigs: []any{
+			map[string]any{"tag": "direct", "protocol": "freedom"},
+			map[string]any{"tag": "Block", "protocol": "blackhole"},
+		},
+		RouterConfig: &conf.RouterConfig{
+			RuleList: []json.RawMessage{
+				json.RawMessage(`{"type":"field","ip":["geoip:private"],"outboundTag":"Block"}`),
+			},
+		},
+	}
+
+	if err := cfg.ApplyAPI(10001, 10002); err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.protectedRulePrefixLen(); got != 2 {
+		t.Fatalf("expected the API and guard rules to be protected, got %d", got)
+	}
+
+	for _, email := range []string{"alice", "bob"} {
+		if err := cfg.setIPLimitRule(email, []string{"10.0.0.2"}, "Block"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := cfg.protectedRulePrefixLen(); got != 4 {
+		t.Fatalf("expected the ip limit rules to be protected as well, got %d", got)
+	}
+	if !isNodeRuleTag(ipLimitRuleTagPrefix+"alice") || !isNodeRuleTag(malformedDomainGuardRuleTag) || isNodeRuleTag("panel") {
+		t.Fatal("unexpected node rule tag classification")
+	}
+}
+
+func TestSetUserOutboundsKeepsRulesLast(t *testing.T) {
+	cfg := &Config{
+		InboundConfigs: []*Inbound{},
+		OutboundConfigs: []any{
+			map[string]any{"tag": "direct", "protocol": "freedom"},
+			map[string]any{"tag": "Block", "protocol": "blackhole"},
+			map[string]any{"tag": "residential", "protocol": "freedom"},
+		},
+		RouterConfig: &conf.RouterConfig{
+			RuleList: []json.RawMessage{
+				json.RawMessage(`{"type":"field","ip":["geoip:private"],"outboundTag":"Block"}`),
+			},
+		},
+	}
+	if err := cfg.ApplyAPI(10001, 10002); err != nil {
+		t.Fatal(err)
+	}
+
+	users := []*common.User{
+		{Email: "bob", Inbounds: []string{"in"}, OutboundTag: "residential"},
+		{Email: "alice", Inbounds: []string{"in"}, OutboundTag: "residential"},
+		{Email: "carol", Inbounds: []string{"in"}, OutboundTag: "missing"},
+		{Email: "dave", OutboundTag: "residential"},
+	}
+	changed, err := cfg.setUserOutbounds(users, true)
+	if err != nil || !changed {
+		t.Fatalf("expected the rules to change, got %v %v", changed, err)
+	}
+	rules := cfg.RouterConfig.RuleList
+	if len(rules) != 4 {
+		t.Fatalf("expected 4 rules, got %d", len(rules))
+	}
+	if rule := string(rules[3]); !containsAll(rule, userOutboundRuleTagPrefix+"residential", `"user":["alice","bob"]`, `"outboundTag":"residential"`) {
+		t.Fatalf("unexpected user outbound rule: %s", rule)
+	}
+
+	// Panel rules added later still go before the user outbound rules.
+	if cfg.userOutboundRuleStart() != 3 {
+		t.Fatalf("expected the user outbound rules to start at 3, got %d", cfg.userOutboundRuleStart())
+	}
+
+	if changed, err = cfg.setUserOutbounds(users[:1], false); err != nil || changed {
+		t.Fatalf("expected an unchanged user to keep the rules, got %v %v", changed, err)
+	}
+	if changed, err = cfg.setUserOutbounds([]*common.User{{Email: "bob", Inbounds: []string{"in"}}}, false); err != nil || !changed {
+		t.Fatalf("expected clearing the tag to change the rules, got %v %v", changed, err)
+	}
+	if rule := string(cfg.RouterConfig.RuleList[3]); !strings.Contains(rule, `"user":["alice"]`) {
+		t.Fatalf("expected only alice to remain, got %s", rule)
+	}
+
+	// A changed rule moves behind the unchanged ones, which keep their place.
+	if _, err = cfg.setUserOutbounds([]*common.User{{Email: "bob", Inbounds: []string{"in"}, OutboundTag: "direct"}}, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cfg.setUserOutbounds([]*common.User{{Email: "erin", Inbounds: []string{"in"}, OutboundTag: "residential"}}, false); err != nil {
+		t.Fatal(err)
+	}
+	if rule := string(cfg.RouterConfig.RuleList[3]); !containsAll(rule, `"user":["bob"]`, `"outboundTag":"direct"`) {
+		t.Fatalf("expected the unchanged rule to stay in place, got %s", rule)
+	}
+	if rule := string(cfg.RouterConfig.RuleList[4]); !containsAll(rule, `"user":["alice","erin"]`, `"outboundTag":"residential"`) {
+		t.Fatalf("expected the changed rule to move last, got %s", rule)
+	}
+	if changed, err = cfg.setUserOutbounds(nil, true); err != nil || !changed || len(cfg.RouterConfig.RuleList) != 3 {
+		t.Fatalf("expected a full sync without users to drop the rules, got %d rules", len(cfg.RouterConfig.RuleList))
+	}
+	if !isNodeRuleTag(userOutboundRuleTagPrefix + "residential") {
+		t.Fatal("expected user outbound rules to be node rules")
+	}
+}
+
+func TestUserOutboundRulesLastAppendsOnlyMissingRules(t *testing.T) {
+	panel := json.RawMessage(`{"type":"field","ip":["10.0.0.0/8"],"outboundTag":"direct","ruleTag":"panel"}`)
+	direct := json.RawMessage(`{"outboundTag":"direct","ruleTag":"` + userOutboundRuleTagPrefix + `direct","type":"field","user":["bob"]}`)
+	residential := json.RawMessage(`{"outboundTag":"residential","ruleTag":"` + userOutboundRuleTagPrefix + `residential","type":"field","user":["alice"]}`)
+	previous := []json.RawMessage{panel, residential, direct}
+
+	restore := userOutboundRulesLast([]json.RawMessage{panel, direct}, previous)
+	if len(restore) != 3 || string(restore[2]) != string(residential) {
+		t.Fatalf("expected the removed user outbound rule to go last, got %d rules", len(restore))
+	}
+	if restore = userOutboundRulesLast([]json.RawMessage{direct}, previous); string(restore[0]) != string(panel) {
+		t.Fatal("expected a missing panel rule to restore the previous rules")
+	}
+}
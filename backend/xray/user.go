@@ -149,6 +149,11 @@ func (x *Xray) SyncUser(ctx context.Context, user *common.User) error {
 		}
 	}
 
+	if err = x.syncUserOutbounds(ctx, []*common.User{user}); err != nil {
+		log.Println(err)
+		errMessage += "\n" + err.Error()
+	}
+
 	if errMessage != "" {
 		return errors.New("failed to add user:" + errMessage)
 	}
@@ -156,7 +161,9 @@ func (x *Xray) SyncUser(ctx context.Context, user *common.User) error {
 }
 
 func (x *Xray) SyncUsers(ctx context.Context, users []*common.User) error {
+	x.mu.Lock()
 	x.config.syncUsers(users)
+	x.mu.Unlock()
 	if err := x.Restart(); err != nil {
 		return err
 	}
@@ -193,6 +200,11 @@ func (x *Xray) UpdateUsers(ctx context.Context, users []*common.User) error {
 		}
 	}
 
+	if err := x.syncUserOutbounds(ctx, users); err != nil {
+		log.Println(err)
+		errMessage += "\n" + err.Error()
+	}
+
 	if errMessage != "" {
 		return errors.New("failed to update users:" + errMessage)
 	}
@@ -201,7 +213,9 @@ func (x *Xray) UpdateUsers(ctx context.Context, users []*common.User) error {
 }
 
 func (x *Xray) UpdateUsersAndRestart(ctx context.Context, users []*common.User) error {
+	x.mu.Lock()
 	x.config.updateUsers(users)
+	x.mu.Unlock()
 	if err := x.Restart(); err != nil {
 		return err
 	}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"log"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/pasarguard/node/backend/xray/api"
 	"github.com/pasarguard/node/common"
 	"github.com/pasarguard/node/config"
+
+	"github.com/xtls/xray-core/app/router"
 )
 
 type Xray struct {
@@ -18,6 +21,11 @@ type Xray struct {
 	core       *Core
 	handler    *api.XrayHandler
 	metricPort int
+	// balancerOverrides maps balancer tags to the outbound they are pinned to.
+	balancerOverrides map[string]string
+	// builtRules caches the routing rules of the config built for the
+	// routing service, by their JSON.
+	builtRules map[string]*router.RoutingRule
 	cancelFunc context.CancelFunc
 	mu         sync.RWMutex
 }
@@ -51,6 +59,7 @@ func New(ctx context.Context, xrayConfig *Config, users []*common.User, apiPort,
 	if err = xrayConfig.ApplyAPI(apiPort, metricPort); err != nil {
 		return nil, err
 	}
+	xrayConfig.applyManagedObservatory(cfg.XrayLatencyTestURL, time.Duration(cfg.XrayLatencyTimeoutSeconds)*time.Second)
 
 	if len(users) > 0 {
 		log.Printf("syncing %d users on startup", len(users))
@@ -109,12 +118,32 @@ func (x *Xray) Logs() <-chan string {
 	return x.core.Logs()
 }
 
+// LogFiles returns the access and error log paths written by the core.
+func (x *Xray) LogFiles() []string {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	accessFile, errorFile := x.config.GetLogFiles()
+	files := make([]string, 0, 2)
+	for _, file := range []string{accessFile, errorFile} {
+		if file == "" || strings.EqualFold(file, "none") {
+			continue
+		}
+		files = append(files, file)
+	}
+	return files
+}
+
 func (x *Xray) Version() string {
 	x.mu.RLock()
 	defer x.mu.RUnlock()
 	return x.core.Version()
 }
 
+func (x *Xray) Obfuscation() string {
+	return ""
+}
+
 func (x *Xray) Started() bool {
 	x.mu.RLock()
 	defer x.mu.RUnlock()
@@ -127,6 +156,9 @@ func (x *Xray) Restart() error {
 	if err := x.core.Restart(x.config, x.cfg.Debug); err != nil {
 		return err
 	}
+	// The new core starts without overrides and loads the assets again.
+	x.balancerOverrides = nil
+	x.builtRules = nil
 	return nil
 }
 
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/pasarguard/node/common"
 	"github.com/pasarguard/node/config"
@@ -134,6 +136,57 @@ func TestXrayBackend(t *testing.T) {
 
 	log.Println("user synced")
 
+	if err = back.BlockUserIPs(ctx1, user2.GetEmail(), []string{"203.0.113.7"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = back.BlockUserIPs(ctx1, user2.GetEmail(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = back.AddOutbound(ctx1, &common.AddOutboundRequest{Config: `{"tag":"upstream","protocol":"freedom"}`}); err != nil {
+		t.Fatal(err)
+	}
+	if err = back.AddOutbound(ctx1, &common.AddOutboundRequest{Config: `{"tag":"upstream","protocol":"freedom"}`}); status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("expected AlreadyExists, got %v", err)
+	}
+	if err = back.AddOutbound(ctx1, &common.AddOutboundRequest{Config: `{"tag":"upstream","protocol":"blackhole"}`, Replace: true}); err != nil {
+		t.Fatal(err)
+	}
+	outbounds, err := back.ListOutbounds(ctx1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := outbounds.GetOutbounds()[len(outbounds.GetOutbounds())-1]; last.GetTag() != "upstream" || last.GetProtocol() != "blackhole" {
+		t.Fatalf("expected the replaced outbound last, got %v", last)
+	}
+	if err = back.RemoveOutbound(ctx1, "upstream"); err != nil {
+		t.Fatal(err)
+	}
+	if err = back.RemoveOutbound(ctx1, "upstream"); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+
+	rule := `{"type":"field","user":["` + user2.GetEmail() + `"],"outboundTag":"direct","ruleTag":"user2-direct"}`
+	if err = back.AddRoutingRule(ctx1, &common.AddRoutingRuleRequest{Config: rule, Prepend: true}); err != nil {
+		t.Fatal(err)
+	}
+	rules, err := back.ListRoutingRules(ctx1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if at := back.config.protectedRulePrefixLen(); rules.GetRules()[at].GetTag() != "user2-direct" {
+		t.Fatalf("expected the rule right after the node's rules, got %v", rules.GetRules())
+	}
+	if err = back.RemoveRoutingRule(ctx1, malformedDomainGuardRuleTag); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition for a node rule, got %v", err)
+	}
+	if err = back.RemoveRoutingRule(ctx1, "user2-direct"); err != nil {
+		t.Fatal(err)
+	}
+	if err = back.OverrideBalancerTarget(ctx1, "missing", "direct"); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+
 	ctx1, cancel = context.WithTimeout(context.Background(), time.Second*10)
 	defer cancel()
 
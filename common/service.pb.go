@@ -67,6 +67,101 @@ func (BackendType) EnumDescriptor() ([]byte, []int) {
 	return file_common_service_proto_rawDescGZIP(), []int{0}
 }
 
+type EnforcementAction int32
+
+const (
+	EnforcementAction_DISABLED EnforcementAction = 0
+	EnforcementAction_ENABLED  EnforcementAction = 1
+)
+
+// Enum value maps for EnforcementAction.
+var (
+	EnforcementAction_name = map[int32]string{
+		0: "DISABLED",
+		1: "ENABLED",
+	}
+	EnforcementAction_value = map[string]int32{
+		"DISABLED": 0,
+		"ENABLED":  1,
+	}
+)
+
+func (x EnforcementAction) Enum() *EnforcementAction {
+	p := new(EnforcementAction)
+	*p = x
+	return p
+}
+
+func (x EnforcementAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EnforcementAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_common_service_proto_enumTypes[1].Descriptor()
+}
+
+func (EnforcementAction) Type() protoreflect.EnumType {
+	return &file_common_service_proto_enumTypes[1]
+}
+
+func (x EnforcementAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EnforcementAction.Descriptor instead.
+func (EnforcementAction) EnumDescriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{1}
+}
+
+type EnforcementReason int32
+
+const (
+	EnforcementReason_DATA_LIMIT EnforcementReason = 0
+	EnforcementReason_EXPIRED    EnforcementReason = 1
+	EnforcementReason_IP_LIMIT   EnforcementReason = 2
+)
+
+// Enum value maps for EnforcementReason.
+var (
+	EnforcementReason_name = map[int32]string{
+		0: "DATA_LIMIT",
+		1: "EXPIRED",
+		2: "IP_LIMIT",
+	}
+	EnforcementReason_value = map[string]int32{
+		"DATA_LIMIT": 0,
+		"EXPIRED":    1,
+		"IP_LIMIT":   2,
+	}
+)
+
+func (x EnforcementReason) Enum() *EnforcementReason {
+	p := new(EnforcementReason)
+	*p = x
+	return p
+}
+
+func (x EnforcementReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EnforcementReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_common_service_proto_enumTypes[2].Descriptor()
+}
+
+func (EnforcementReason) Type() protoreflect.EnumType {
+	return &file_common_service_proto_enumTypes[2]
+}
+
+func (x EnforcementReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EnforcementReason.Descriptor instead.
+func (EnforcementReason) EnumDescriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{2}
+}
+
 type StatType int32
 
 const (
@@ -109,11 +204,11 @@ func (x StatType) String() string {
 }
 
 func (StatType) Descriptor() protoreflect.EnumDescriptor {
-	return file_common_service_proto_enumTypes[1].Descriptor()
+	return file_common_service_proto_enumTypes[3].Descriptor()
 }
 
 func (StatType) Type() protoreflect.EnumType {
-	return &file_common_service_proto_enumTypes[1]
+	return &file_common_service_proto_enumTypes[3]
 }
 
 func (x StatType) Number() protoreflect.EnumNumber {
@@ -122,7 +217,157 @@ func (x StatType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use StatType.Descriptor instead.
 func (StatType) EnumDescriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{1}
+	return file_common_service_proto_rawDescGZIP(), []int{3}
+}
+
+type IpLimitAction int32
+
+const (
+	IpLimitAction_BLOCK_IPS    IpLimitAction = 0
+	IpLimitAction_DISABLE_USER IpLimitAction = 1
+)
+
+// Enum value maps for IpLimitAction.
+var (
+	IpLimitAction_name = map[int32]string{
+		0: "BLOCK_IPS",
+		1: "DISABLE_USER",
+	}
+	IpLimitAction_value = map[string]int32{
+		"BLOCK_IPS":    0,
+		"DISABLE_USER": 1,
+	}
+)
+
+func (x IpLimitAction) Enum() *IpLimitAction {
+	p := new(IpLimitAction)
+	*p = x
+	return p
+}
+
+func (x IpLimitAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (IpLimitAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_common_service_proto_enumTypes[4].Descriptor()
+}
+
+func (IpLimitAction) Type() protoreflect.EnumType {
+	return &file_common_service_proto_enumTypes[4]
+}
+
+func (x IpLimitAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use IpLimitAction.Descriptor instead.
+func (IpLimitAction) EnumDescriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{4}
+}
+
+type PeerEventType int32
+
+const (
+	PeerEventType_CONNECTED    PeerEventType = 0 // first fresh handshake of a session
+	PeerEventType_ROAMED       PeerEventType = 1 // endpoint changed during a session
+	PeerEventType_DISCONNECTED PeerEventType = 2 // no handshake or traffic within the online threshold
+)
+
+// Enum value maps for PeerEventType.
+var (
+	PeerEventType_name = map[int32]string{
+		0: "CONNECTED",
+		1: "ROAMED",
+		2: "DISCONNECTED",
+	}
+	PeerEventType_value = map[string]int32{
+		"CONNECTED":    0,
+		"ROAMED":       1,
+		"DISCONNECTED": 2,
+	}
+)
+
+func (x PeerEventType) Enum() *PeerEventType {
+	p := new(PeerEventType)
+	*p = x
+	return p
+}
+
+func (x PeerEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PeerEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_common_service_proto_enumTypes[5].Descriptor()
+}
+
+func (PeerEventType) Type() protoreflect.EnumType {
+	return &file_common_service_proto_enumTypes[5]
+}
+
+func (x PeerEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PeerEventType.Descriptor instead.
+func (PeerEventType) EnumDescriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{5}
+}
+
+type DataLimitResetStrategy int32
+
+const (
+	DataLimitResetStrategy_NO_RESET DataLimitResetStrategy = 0
+	DataLimitResetStrategy_DAY      DataLimitResetStrategy = 1
+	DataLimitResetStrategy_WEEK     DataLimitResetStrategy = 2
+	DataLimitResetStrategy_MONTH    DataLimitResetStrategy = 3
+	DataLimitResetStrategy_YEAR     DataLimitResetStrategy = 4
+)
+
+// Enum value maps for DataLimitResetStrategy.
+var (
+	DataLimitResetStrategy_name = map[int32]string{
+		0: "NO_RESET",
+		1: "DAY",
+		2: "WEEK",
+		3: "MONTH",
+		4: "YEAR",
+	}
+	DataLimitResetStrategy_value = map[string]int32{
+		"NO_RESET": 0,
+		"DAY":      1,
+		"WEEK":     2,
+		"MONTH":    3,
+		"YEAR":     4,
+	}
+)
+
+func (x DataLimitResetStrategy) Enum() *DataLimitResetStrategy {
+	p := new(DataLimitResetStrategy)
+	*p = x
+	return p
+}
+
+func (x DataLimitResetStrategy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DataLimitResetStrategy) Descriptor() protoreflect.EnumDescriptor {
+	return file_common_service_proto_enumTypes[6].Descriptor()
+}
+
+func (DataLimitResetStrategy) Type() protoreflect.EnumType {
+	return &file_common_service_proto_enumTypes[6]
+}
+
+func (x DataLimitResetStrategy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DataLimitResetStrategy.Descriptor instead.
+func (DataLimitResetStrategy) EnumDescriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{6}
 }
 
 type Empty struct {
@@ -163,10 +408,12 @@ func (*Empty) Descriptor() ([]byte, []int) {
 
 // Base info response message
 type BaseInfoResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Started       bool                   `protobuf:"varint,1,opt,name=started,proto3" json:"started,omitempty"`
-	CoreVersion   string                 `protobuf:"bytes,2,opt,name=core_version,json=coreVersion,proto3" json:"core_version,omitempty"`
-	NodeVersion   string                 `protobuf:"bytes,3,opt,name=node_version,json=nodeVersion,proto3" json:"node_version,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Started     bool                   `protobuf:"varint,1,opt,name=started,proto3" json:"started,omitempty"`
+	CoreVersion string                 `protobuf:"bytes,2,opt,name=core_version,json=coreVersion,proto3" json:"core_version,omitempty"`
+	NodeVersion string                 `protobuf:"bytes,3,opt,name=node_version,json=nodeVersion,proto3" json:"node_version,omitempty"`
+	// obfuscation is "amneziawg" when the wireguard backend obfuscates its traffic.
+	Obfuscation   string `protobuf:"bytes,4,opt,name=obfuscation,proto3" json:"obfuscation,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -222,6 +469,13 @@ func (x *BaseInfoResponse) GetNodeVersion() string {
 	return ""
 }
 
+func (x *BaseInfoResponse) GetObfuscation() string {
+	if x != nil {
+		return x.Obfuscation
+	}
+	return ""
+}
+
 type Backend struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
 	Type            BackendType            `protobuf:"varint,1,opt,name=type,proto3,enum=service.BackendType" json:"type,omitempty"`
@@ -415,6 +669,7 @@ func (x *Stat) GetValue() int64 {
 type StatResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Stats         []*Stat                `protobuf:"bytes,1,rep,name=stats,proto3" json:"stats,omitempty"`
+	Events        []*EnforcementEvent    `protobuf:"bytes,2,rep,name=events,proto3" json:"events,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -456,6 +711,90 @@ func (x *StatResponse) GetStats() []*Stat {
 	return nil
 }
 
+func (x *StatResponse) GetEvents() []*EnforcementEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+// EnforcementEvent records a user the node disabled or re-enabled on its own.
+type EnforcementEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Action        EnforcementAction      `protobuf:"varint,2,opt,name=action,proto3,enum=service.EnforcementAction" json:"action,omitempty"`
+	Reason        EnforcementReason      `protobuf:"varint,3,opt,name=reason,proto3,enum=service.EnforcementReason" json:"reason,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Detail        string                 `protobuf:"bytes,5,opt,name=detail,proto3" json:"detail,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnforcementEvent) Reset() {
+	*x = EnforcementEvent{}
+	mi := &file_common_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnforcementEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnforcementEvent) ProtoMessage() {}
+
+func (x *EnforcementEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnforcementEvent.ProtoReflect.Descriptor instead.
+func (*EnforcementEvent) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *EnforcementEvent) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *EnforcementEvent) GetAction() EnforcementAction {
+	if x != nil {
+		return x.Action
+	}
+	return EnforcementAction_DISABLED
+}
+
+func (x *EnforcementEvent) GetReason() EnforcementReason {
+	if x != nil {
+		return x.Reason
+	}
+	return EnforcementReason_DATA_LIMIT
+}
+
+func (x *EnforcementEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *EnforcementEvent) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
 type StatRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -467,7 +806,7 @@ type StatRequest struct {
 
 func (x *StatRequest) Reset() {
 	*x = StatRequest{}
-	mi := &file_common_service_proto_msgTypes[6]
+	mi := &file_common_service_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -479,7 +818,7 @@ func (x *StatRequest) String() string {
 func (*StatRequest) ProtoMessage() {}
 
 func (x *StatRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_common_service_proto_msgTypes[6]
+	mi := &file_common_service_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -492,7 +831,7 @@ func (x *StatRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StatRequest.ProtoReflect.Descriptor instead.
 func (*StatRequest) Descriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{6}
+	return file_common_service_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *StatRequest) GetName() string {
@@ -526,7 +865,7 @@ type OnlineStatResponse struct {
 
 func (x *OnlineStatResponse) Reset() {
 	*x = OnlineStatResponse{}
-	mi := &file_common_service_proto_msgTypes[7]
+	mi := &file_common_service_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -538,7 +877,7 @@ func (x *OnlineStatResponse) String() string {
 func (*OnlineStatResponse) ProtoMessage() {}
 
 func (x *OnlineStatResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_common_service_proto_msgTypes[7]
+	mi := &file_common_service_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -551,7 +890,7 @@ func (x *OnlineStatResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OnlineStatResponse.ProtoReflect.Descriptor instead.
 func (*OnlineStatResponse) Descriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{7}
+	return file_common_service_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *OnlineStatResponse) GetName() string {
@@ -578,7 +917,7 @@ type StatsOnlineIpListResponse struct {
 
 func (x *StatsOnlineIpListResponse) Reset() {
 	*x = StatsOnlineIpListResponse{}
-	mi := &file_common_service_proto_msgTypes[8]
+	mi := &file_common_service_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -590,7 +929,7 @@ func (x *StatsOnlineIpListResponse) String() string {
 func (*StatsOnlineIpListResponse) ProtoMessage() {}
 
 func (x *StatsOnlineIpListResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_common_service_proto_msgTypes[8]
+	mi := &file_common_service_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -603,7 +942,7 @@ func (x *StatsOnlineIpListResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StatsOnlineIpListResponse.ProtoReflect.Descriptor instead.
 func (*StatsOnlineIpListResponse) Descriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{8}
+	return file_common_service_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *StatsOnlineIpListResponse) GetName() string {
@@ -621,21 +960,29 @@ func (x *StatsOnlineIpListResponse) GetIps() map[string]int64 {
 }
 
 type Latency struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Alive         bool                   `protobuf:"varint,2,opt,name=alive,proto3" json:"alive,omitempty"`
-	Delay         int64                  `protobuf:"varint,3,opt,name=delay,proto3" json:"delay,omitempty"`
-	Link          string                 `protobuf:"bytes,4,opt,name=link,proto3" json:"link,omitempty"`
-	LastSeenTime  int64                  `protobuf:"varint,5,opt,name=last_seen_time,json=lastSeenTime,proto3" json:"last_seen_time,omitempty"`
-	LastTryTime   int64                  `protobuf:"varint,6,opt,name=last_try_time,json=lastTryTime,proto3" json:"last_try_time,omitempty"`
-	Source        string                 `protobuf:"bytes,7,opt,name=source,proto3" json:"source,omitempty"`
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Name         string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Alive        bool                   `protobuf:"varint,2,opt,name=alive,proto3" json:"alive,omitempty"`
+	Delay        int64                  `protobuf:"varint,3,opt,name=delay,proto3" json:"delay,omitempty"`
+	Link         string                 `protobuf:"bytes,4,opt,name=link,proto3" json:"link,omitempty"`
+	LastSeenTime int64                  `protobuf:"varint,5,opt,name=last_seen_time,json=lastSeenTime,proto3" json:"last_seen_time,omitempty"`
+	LastTryTime  int64                  `protobuf:"varint,6,opt,name=last_try_time,json=lastTryTime,proto3" json:"last_try_time,omitempty"`
+	Source       string                 `protobuf:"bytes,7,opt,name=source,proto3" json:"source,omitempty"`
+	// Window statistics over the probes recorded in the requested window.
+	Window        uint32  `protobuf:"varint,8,opt,name=window,proto3" json:"window,omitempty"` // seconds
+	Samples       uint32  `protobuf:"varint,9,opt,name=samples,proto3" json:"samples,omitempty"`
+	MinDelay      int64   `protobuf:"varint,10,opt,name=min_delay,json=minDelay,proto3" json:"min_delay,omitempty"`
+	AvgDelay      int64   `protobuf:"varint,11,opt,name=avg_delay,json=avgDelay,proto3" json:"avg_delay,omitempty"`
+	P95Delay      int64   `protobuf:"varint,12,opt,name=p95_delay,json=p95Delay,proto3" json:"p95_delay,omitempty"`
+	Jitter        int64   `protobuf:"varint,13,opt,name=jitter,proto3" json:"jitter,omitempty"`
+	Loss          float64 `protobuf:"fixed64,14,opt,name=loss,proto3" json:"loss,omitempty"` // percent of failed probes
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Latency) Reset() {
 	*x = Latency{}
-	mi := &file_common_service_proto_msgTypes[9]
+	mi := &file_common_service_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -647,7 +994,7 @@ func (x *Latency) String() string {
 func (*Latency) ProtoMessage() {}
 
 func (x *Latency) ProtoReflect() protoreflect.Message {
-	mi := &file_common_service_proto_msgTypes[9]
+	mi := &file_common_service_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -660,7 +1007,7 @@ func (x *Latency) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Latency.ProtoReflect.Descriptor instead.
 func (*Latency) Descriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{9}
+	return file_common_service_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *Latency) GetName() string {
@@ -712,31 +1059,81 @@ func (x *Latency) GetSource() string {
 	return ""
 }
 
-type LatencyRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *Latency) GetWindow() uint32 {
+	if x != nil {
+		return x.Window
+	}
+	return 0
 }
 
-func (x *LatencyRequest) Reset() {
-	*x = LatencyRequest{}
-	mi := &file_common_service_proto_msgTypes[10]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *Latency) GetSamples() uint32 {
+	if x != nil {
+		return x.Samples
+	}
+	return 0
 }
 
-func (x *LatencyRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *Latency) GetMinDelay() int64 {
+	if x != nil {
+		return x.MinDelay
+	}
+	return 0
 }
 
-func (*LatencyRequest) ProtoMessage() {}
-
-func (x *LatencyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_common_service_proto_msgTypes[10]
+func (x *Latency) GetAvgDelay() int64 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
+		return x.AvgDelay
+	}
+	return 0
+}
+
+func (x *Latency) GetP95Delay() int64 {
+	if x != nil {
+		return x.P95Delay
+	}
+	return 0
+}
+
+func (x *Latency) GetJitter() int64 {
+	if x != nil {
+		return x.Jitter
+	}
+	return 0
+}
+
+func (x *Latency) GetLoss() float64 {
+	if x != nil {
+		return x.Loss
+	}
+	return 0
+}
+
+type LatencyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Window        uint32                 `protobuf:"varint,2,opt,name=window,proto3" json:"window,omitempty"` // seconds, 0 means the default window
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LatencyRequest) Reset() {
+	*x = LatencyRequest{}
+	mi := &file_common_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LatencyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LatencyRequest) ProtoMessage() {}
+
+func (x *LatencyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
 		}
 		return ms
@@ -746,7 +1143,7 @@ func (x *LatencyRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LatencyRequest.ProtoReflect.Descriptor instead.
 func (*LatencyRequest) Descriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{10}
+	return file_common_service_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *LatencyRequest) GetName() string {
@@ -756,6 +1153,229 @@ func (x *LatencyRequest) GetName() string {
 	return ""
 }
 
+func (x *LatencyRequest) GetWindow() uint32 {
+	if x != nil {
+		return x.Window
+	}
+	return 0
+}
+
+type LatencyHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Start         int64                  `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"` // unix seconds, 0 means oldest available sample
+	End           int64                  `protobuf:"varint,3,opt,name=end,proto3" json:"end,omitempty"`     // unix seconds, 0 means now
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LatencyHistoryRequest) Reset() {
+	*x = LatencyHistoryRequest{}
+	mi := &file_common_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LatencyHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LatencyHistoryRequest) ProtoMessage() {}
+
+func (x *LatencyHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LatencyHistoryRequest.ProtoReflect.Descriptor instead.
+func (*LatencyHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *LatencyHistoryRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LatencyHistoryRequest) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *LatencyHistoryRequest) GetEnd() int64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+type LatencySample struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Alive         bool                   `protobuf:"varint,2,opt,name=alive,proto3" json:"alive,omitempty"`
+	Delay         int64                  `protobuf:"varint,3,opt,name=delay,proto3" json:"delay,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LatencySample) Reset() {
+	*x = LatencySample{}
+	mi := &file_common_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LatencySample) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LatencySample) ProtoMessage() {}
+
+func (x *LatencySample) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LatencySample.ProtoReflect.Descriptor instead.
+func (*LatencySample) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *LatencySample) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *LatencySample) GetAlive() bool {
+	if x != nil {
+		return x.Alive
+	}
+	return false
+}
+
+func (x *LatencySample) GetDelay() int64 {
+	if x != nil {
+		return x.Delay
+	}
+	return 0
+}
+
+type LatencySeries struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Samples       []*LatencySample       `protobuf:"bytes,2,rep,name=samples,proto3" json:"samples,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LatencySeries) Reset() {
+	*x = LatencySeries{}
+	mi := &file_common_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LatencySeries) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LatencySeries) ProtoMessage() {}
+
+func (x *LatencySeries) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LatencySeries.ProtoReflect.Descriptor instead.
+func (*LatencySeries) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *LatencySeries) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LatencySeries) GetSamples() []*LatencySample {
+	if x != nil {
+		return x.Samples
+	}
+	return nil
+}
+
+type LatencyHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Series        []*LatencySeries       `protobuf:"bytes,1,rep,name=series,proto3" json:"series,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LatencyHistoryResponse) Reset() {
+	*x = LatencyHistoryResponse{}
+	mi := &file_common_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LatencyHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LatencyHistoryResponse) ProtoMessage() {}
+
+func (x *LatencyHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LatencyHistoryResponse.ProtoReflect.Descriptor instead.
+func (*LatencyHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *LatencyHistoryResponse) GetSeries() []*LatencySeries {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
 type LatencyResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Latencies     []*Latency             `protobuf:"bytes,1,rep,name=latencies,proto3" json:"latencies,omitempty"`
@@ -765,7 +1385,7 @@ type LatencyResponse struct {
 
 func (x *LatencyResponse) Reset() {
 	*x = LatencyResponse{}
-	mi := &file_common_service_proto_msgTypes[11]
+	mi := &file_common_service_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -777,7 +1397,7 @@ func (x *LatencyResponse) String() string {
 func (*LatencyResponse) ProtoMessage() {}
 
 func (x *LatencyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_common_service_proto_msgTypes[11]
+	mi := &file_common_service_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -790,7 +1410,7 @@ func (x *LatencyResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LatencyResponse.ProtoReflect.Descriptor instead.
 func (*LatencyResponse) Descriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{11}
+	return file_common_service_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *LatencyResponse) GetLatencies() []*Latency {
@@ -816,21 +1436,2104 @@ type BackendStatsResponse struct {
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BackendStatsResponse) Reset() {
-	*x = BackendStatsResponse{}
-	mi := &file_common_service_proto_msgTypes[12]
+func (x *BackendStatsResponse) Reset() {
+	*x = BackendStatsResponse{}
+	mi := &file_common_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackendStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackendStatsResponse) ProtoMessage() {}
+
+func (x *BackendStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackendStatsResponse.ProtoReflect.Descriptor instead.
+func (*BackendStatsResponse) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *BackendStatsResponse) GetNumGoroutine() uint32 {
+	if x != nil {
+		return x.NumGoroutine
+	}
+	return 0
+}
+
+func (x *BackendStatsResponse) GetNumGc() uint32 {
+	if x != nil {
+		return x.NumGc
+	}
+	return 0
+}
+
+func (x *BackendStatsResponse) GetAlloc() uint64 {
+	if x != nil {
+		return x.Alloc
+	}
+	return 0
+}
+
+func (x *BackendStatsResponse) GetTotalAlloc() uint64 {
+	if x != nil {
+		return x.TotalAlloc
+	}
+	return 0
+}
+
+func (x *BackendStatsResponse) GetSys() uint64 {
+	if x != nil {
+		return x.Sys
+	}
+	return 0
+}
+
+func (x *BackendStatsResponse) GetMallocs() uint64 {
+	if x != nil {
+		return x.Mallocs
+	}
+	return 0
+}
+
+func (x *BackendStatsResponse) GetFrees() uint64 {
+	if x != nil {
+		return x.Frees
+	}
+	return 0
+}
+
+func (x *BackendStatsResponse) GetLiveObjects() uint64 {
+	if x != nil {
+		return x.LiveObjects
+	}
+	return 0
+}
+
+func (x *BackendStatsResponse) GetPauseTotalNs() uint64 {
+	if x != nil {
+		return x.PauseTotalNs
+	}
+	return 0
+}
+
+func (x *BackendStatsResponse) GetUptime() uint32 {
+	if x != nil {
+		return x.Uptime
+	}
+	return 0
+}
+
+type SystemStatsResponse struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	MemTotal               uint64                 `protobuf:"varint,1,opt,name=mem_total,json=memTotal,proto3" json:"mem_total,omitempty"`
+	MemUsed                uint64                 `protobuf:"varint,2,opt,name=mem_used,json=memUsed,proto3" json:"mem_used,omitempty"`
+	CpuCores               uint64                 `protobuf:"varint,3,opt,name=cpu_cores,json=cpuCores,proto3" json:"cpu_cores,omitempty"`
+	CpuUsage               float64                `protobuf:"fixed64,4,opt,name=cpu_usage,json=cpuUsage,proto3" json:"cpu_usage,omitempty"`
+	IncomingBandwidthSpeed uint64                 `protobuf:"varint,5,opt,name=incoming_bandwidth_speed,json=incomingBandwidthSpeed,proto3" json:"incoming_bandwidth_speed,omitempty"`
+	OutgoingBandwidthSpeed uint64                 `protobuf:"varint,6,opt,name=outgoing_bandwidth_speed,json=outgoingBandwidthSpeed,proto3" json:"outgoing_bandwidth_speed,omitempty"`
+	Uptime                 uint64                 `protobuf:"varint,7,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *SystemStatsResponse) Reset() {
+	*x = SystemStatsResponse{}
+	mi := &file_common_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SystemStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SystemStatsResponse) ProtoMessage() {}
+
+func (x *SystemStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SystemStatsResponse.ProtoReflect.Descriptor instead.
+func (*SystemStatsResponse) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SystemStatsResponse) GetMemTotal() uint64 {
+	if x != nil {
+		return x.MemTotal
+	}
+	return 0
+}
+
+func (x *SystemStatsResponse) GetMemUsed() uint64 {
+	if x != nil {
+		return x.MemUsed
+	}
+	return 0
+}
+
+func (x *SystemStatsResponse) GetCpuCores() uint64 {
+	if x != nil {
+		return x.CpuCores
+	}
+	return 0
+}
+
+func (x *SystemStatsResponse) GetCpuUsage() float64 {
+	if x != nil {
+		return x.CpuUsage
+	}
+	return 0
+}
+
+func (x *SystemStatsResponse) GetIncomingBandwidthSpeed() uint64 {
+	if x != nil {
+		return x.IncomingBandwidthSpeed
+	}
+	return 0
+}
+
+func (x *SystemStatsResponse) GetOutgoingBandwidthSpeed() uint64 {
+	if x != nil {
+		return x.OutgoingBandwidthSpeed
+	}
+	return 0
+}
+
+func (x *SystemStatsResponse) GetUptime() uint64 {
+	if x != nil {
+		return x.Uptime
+	}
+	return 0
+}
+
+type SystemStatsHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         int64                  `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"` // unix seconds, 0 means oldest available sample
+	End           int64                  `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`     // unix seconds, 0 means now
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SystemStatsHistoryRequest) Reset() {
+	*x = SystemStatsHistoryRequest{}
+	mi := &file_common_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SystemStatsHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SystemStatsHistoryRequest) ProtoMessage() {}
+
+func (x *SystemStatsHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SystemStatsHistoryRequest.ProtoReflect.Descriptor instead.
+func (*SystemStatsHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SystemStatsHistoryRequest) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *SystemStatsHistoryRequest) GetEnd() int64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+type SystemStatsSample struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp              int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	CpuUsage               float64                `protobuf:"fixed64,2,opt,name=cpu_usage,json=cpuUsage,proto3" json:"cpu_usage,omitempty"`
+	MemUsed                uint64                 `protobuf:"varint,3,opt,name=mem_used,json=memUsed,proto3" json:"mem_used,omitempty"`
+	MemTotal               uint64                 `protobuf:"varint,4,opt,name=mem_total,json=memTotal,proto3" json:"mem_total,omitempty"`
+	IncomingBandwidthSpeed uint64                 `protobuf:"varint,5,opt,name=incoming_bandwidth_speed,json=incomingBandwidthSpeed,proto3" json:"incoming_bandwidth_speed,omitempty"`
+	OutgoingBandwidthSpeed uint64                 `protobuf:"varint,6,opt,name=outgoing_bandwidth_speed,json=outgoingBandwidthSpeed,proto3" json:"outgoing_bandwidth_speed,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *SystemStatsSample) Reset() {
+	*x = SystemStatsSample{}
+	mi := &file_common_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SystemStatsSample) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SystemStatsSample) ProtoMessage() {}
+
+func (x *SystemStatsSample) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SystemStatsSample.ProtoReflect.Descriptor instead.
+func (*SystemStatsSample) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SystemStatsSample) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *SystemStatsSample) GetCpuUsage() float64 {
+	if x != nil {
+		return x.CpuUsage
+	}
+	return 0
+}
+
+func (x *SystemStatsSample) GetMemUsed() uint64 {
+	if x != nil {
+		return x.MemUsed
+	}
+	return 0
+}
+
+func (x *SystemStatsSample) GetMemTotal() uint64 {
+	if x != nil {
+		return x.MemTotal
+	}
+	return 0
+}
+
+func (x *SystemStatsSample) GetIncomingBandwidthSpeed() uint64 {
+	if x != nil {
+		return x.IncomingBandwidthSpeed
+	}
+	return 0
+}
+
+func (x *SystemStatsSample) GetOutgoingBandwidthSpeed() uint64 {
+	if x != nil {
+		return x.OutgoingBandwidthSpeed
+	}
+	return 0
+}
+
+type SystemStatsHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Resolution    uint32                 `protobuf:"varint,1,opt,name=resolution,proto3" json:"resolution,omitempty"` // seconds covered by each sample
+	Samples       []*SystemStatsSample   `protobuf:"bytes,2,rep,name=samples,proto3" json:"samples,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SystemStatsHistoryResponse) Reset() {
+	*x = SystemStatsHistoryResponse{}
+	mi := &file_common_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SystemStatsHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SystemStatsHistoryResponse) ProtoMessage() {}
+
+func (x *SystemStatsHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SystemStatsHistoryResponse.ProtoReflect.Descriptor instead.
+func (*SystemStatsHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SystemStatsHistoryResponse) GetResolution() uint32 {
+	if x != nil {
+		return x.Resolution
+	}
+	return 0
+}
+
+func (x *SystemStatsHistoryResponse) GetSamples() []*SystemStatsSample {
+	if x != nil {
+		return x.Samples
+	}
+	return nil
+}
+
+type InterfaceStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	RxBytes       uint64                 `protobuf:"varint,2,opt,name=rx_bytes,json=rxBytes,proto3" json:"rx_bytes,omitempty"`
+	TxBytes       uint64                 `protobuf:"varint,3,opt,name=tx_bytes,json=txBytes,proto3" json:"tx_bytes,omitempty"`
+	RxPackets     uint64                 `protobuf:"varint,4,opt,name=rx_packets,json=rxPackets,proto3" json:"rx_packets,omitempty"`
+	TxPackets     uint64                 `protobuf:"varint,5,opt,name=tx_packets,json=txPackets,proto3" json:"tx_packets,omitempty"`
+	RxErrors      uint64                 `protobuf:"varint,6,opt,name=rx_errors,json=rxErrors,proto3" json:"rx_errors,omitempty"`
+	TxErrors      uint64                 `protobuf:"varint,7,opt,name=tx_errors,json=txErrors,proto3" json:"tx_errors,omitempty"`
+	RxDropped     uint64                 `protobuf:"varint,8,opt,name=rx_dropped,json=rxDropped,proto3" json:"rx_dropped,omitempty"`
+	TxDropped     uint64                 `protobuf:"varint,9,opt,name=tx_dropped,json=txDropped,proto3" json:"tx_dropped,omitempty"`
+	RxSpeed       uint64                 `protobuf:"varint,10,opt,name=rx_speed,json=rxSpeed,proto3" json:"rx_speed,omitempty"` // bytes per second
+	TxSpeed       uint64                 `protobuf:"varint,11,opt,name=tx_speed,json=txSpeed,proto3" json:"tx_speed,omitempty"` // bytes per second
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InterfaceStats) Reset() {
+	*x = InterfaceStats{}
+	mi := &file_common_service_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InterfaceStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InterfaceStats) ProtoMessage() {}
+
+func (x *InterfaceStats) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InterfaceStats.ProtoReflect.Descriptor instead.
+func (*InterfaceStats) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *InterfaceStats) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *InterfaceStats) GetRxBytes() uint64 {
+	if x != nil {
+		return x.RxBytes
+	}
+	return 0
+}
+
+func (x *InterfaceStats) GetTxBytes() uint64 {
+	if x != nil {
+		return x.TxBytes
+	}
+	return 0
+}
+
+func (x *InterfaceStats) GetRxPackets() uint64 {
+	if x != nil {
+		return x.RxPackets
+	}
+	return 0
+}
+
+func (x *InterfaceStats) GetTxPackets() uint64 {
+	if x != nil {
+		return x.TxPackets
+	}
+	return 0
+}
+
+func (x *InterfaceStats) GetRxErrors() uint64 {
+	if x != nil {
+		return x.RxErrors
+	}
+	return 0
+}
+
+func (x *InterfaceStats) GetTxErrors() uint64 {
+	if x != nil {
+		return x.TxErrors
+	}
+	return 0
+}
+
+func (x *InterfaceStats) GetRxDropped() uint64 {
+	if x != nil {
+		return x.RxDropped
+	}
+	return 0
+}
+
+func (x *InterfaceStats) GetTxDropped() uint64 {
+	if x != nil {
+		return x.TxDropped
+	}
+	return 0
+}
+
+func (x *InterfaceStats) GetRxSpeed() uint64 {
+	if x != nil {
+		return x.RxSpeed
+	}
+	return 0
+}
+
+func (x *InterfaceStats) GetTxSpeed() uint64 {
+	if x != nil {
+		return x.TxSpeed
+	}
+	return 0
+}
+
+type DiskUsage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Mountpoint    string                 `protobuf:"bytes,2,opt,name=mountpoint,proto3" json:"mountpoint,omitempty"`
+	Fstype        string                 `protobuf:"bytes,3,opt,name=fstype,proto3" json:"fstype,omitempty"`
+	Total         uint64                 `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	Used          uint64                 `protobuf:"varint,5,opt,name=used,proto3" json:"used,omitempty"`
+	Free          uint64                 `protobuf:"varint,6,opt,name=free,proto3" json:"free,omitempty"`
+	UsedPercent   float64                `protobuf:"fixed64,7,opt,name=used_percent,json=usedPercent,proto3" json:"used_percent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiskUsage) Reset() {
+	*x = DiskUsage{}
+	mi := &file_common_service_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiskUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiskUsage) ProtoMessage() {}
+
+func (x *DiskUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiskUsage.ProtoReflect.Descriptor instead.
+func (*DiskUsage) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *DiskUsage) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DiskUsage) GetMountpoint() string {
+	if x != nil {
+		return x.Mountpoint
+	}
+	return ""
+}
+
+func (x *DiskUsage) GetFstype() string {
+	if x != nil {
+		return x.Fstype
+	}
+	return ""
+}
+
+func (x *DiskUsage) GetTotal() uint64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *DiskUsage) GetUsed() uint64 {
+	if x != nil {
+		return x.Used
+	}
+	return 0
+}
+
+func (x *DiskUsage) GetFree() uint64 {
+	if x != nil {
+		return x.Free
+	}
+	return 0
+}
+
+func (x *DiskUsage) GetUsedPercent() float64 {
+	if x != nil {
+		return x.UsedPercent
+	}
+	return 0
+}
+
+type LoadAverage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Load1         float64                `protobuf:"fixed64,1,opt,name=load1,proto3" json:"load1,omitempty"`
+	Load5         float64                `protobuf:"fixed64,2,opt,name=load5,proto3" json:"load5,omitempty"`
+	Load15        float64                `protobuf:"fixed64,3,opt,name=load15,proto3" json:"load15,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoadAverage) Reset() {
+	*x = LoadAverage{}
+	mi := &file_common_service_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoadAverage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadAverage) ProtoMessage() {}
+
+func (x *LoadAverage) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadAverage.ProtoReflect.Descriptor instead.
+func (*LoadAverage) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *LoadAverage) GetLoad1() float64 {
+	if x != nil {
+		return x.Load1
+	}
+	return 0
+}
+
+func (x *LoadAverage) GetLoad5() float64 {
+	if x != nil {
+		return x.Load5
+	}
+	return 0
+}
+
+func (x *LoadAverage) GetLoad15() float64 {
+	if x != nil {
+		return x.Load15
+	}
+	return 0
+}
+
+type SocketStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Protocol      string                 `protobuf:"bytes,1,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	Total         uint64                 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	States        map[string]uint64      `protobuf:"bytes,3,rep,name=states,proto3" json:"states,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SocketStats) Reset() {
+	*x = SocketStats{}
+	mi := &file_common_service_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SocketStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SocketStats) ProtoMessage() {}
+
+func (x *SocketStats) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SocketStats.ProtoReflect.Descriptor instead.
+func (*SocketStats) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *SocketStats) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *SocketStats) GetTotal() uint64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *SocketStats) GetStates() map[string]uint64 {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
+type DetailedSystemStatsResponse struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	MemTotal            uint64                 `protobuf:"varint,1,opt,name=mem_total,json=memTotal,proto3" json:"mem_total,omitempty"`
+	MemUsed             uint64                 `protobuf:"varint,2,opt,name=mem_used,json=memUsed,proto3" json:"mem_used,omitempty"`
+	CpuCores            uint64                 `protobuf:"varint,3,opt,name=cpu_cores,json=cpuCores,proto3" json:"cpu_cores,omitempty"`
+	CpuUsage            float64                `protobuf:"fixed64,4,opt,name=cpu_usage,json=cpuUsage,proto3" json:"cpu_usage,omitempty"`
+	CpuCoreUsage        []float64              `protobuf:"fixed64,5,rep,packed,name=cpu_core_usage,json=cpuCoreUsage,proto3" json:"cpu_core_usage,omitempty"`
+	Load                *LoadAverage           `protobuf:"bytes,6,opt,name=load,proto3" json:"load,omitempty"`
+	Interfaces          []*InterfaceStats      `protobuf:"bytes,7,rep,name=interfaces,proto3" json:"interfaces,omitempty"`
+	Disks               []*DiskUsage           `protobuf:"bytes,8,rep,name=disks,proto3" json:"disks,omitempty"`
+	OpenFileDescriptors uint64                 `protobuf:"varint,9,opt,name=open_file_descriptors,json=openFileDescriptors,proto3" json:"open_file_descriptors,omitempty"`
+	MaxFileDescriptors  uint64                 `protobuf:"varint,10,opt,name=max_file_descriptors,json=maxFileDescriptors,proto3" json:"max_file_descriptors,omitempty"`
+	Sockets             []*SocketStats         `protobuf:"bytes,11,rep,name=sockets,proto3" json:"sockets,omitempty"`
+	Uptime              uint64                 `protobuf:"varint,12,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *DetailedSystemStatsResponse) Reset() {
+	*x = DetailedSystemStatsResponse{}
+	mi := &file_common_service_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DetailedSystemStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DetailedSystemStatsResponse) ProtoMessage() {}
+
+func (x *DetailedSystemStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DetailedSystemStatsResponse.ProtoReflect.Descriptor instead.
+func (*DetailedSystemStatsResponse) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *DetailedSystemStatsResponse) GetMemTotal() uint64 {
+	if x != nil {
+		return x.MemTotal
+	}
+	return 0
+}
+
+func (x *DetailedSystemStatsResponse) GetMemUsed() uint64 {
+	if x != nil {
+		return x.MemUsed
+	}
+	return 0
+}
+
+func (x *DetailedSystemStatsResponse) GetCpuCores() uint64 {
+	if x != nil {
+		return x.CpuCores
+	}
+	return 0
+}
+
+func (x *DetailedSystemStatsResponse) GetCpuUsage() float64 {
+	if x != nil {
+		return x.CpuUsage
+	}
+	return 0
+}
+
+func (x *DetailedSystemStatsResponse) GetCpuCoreUsage() []float64 {
+	if x != nil {
+		return x.CpuCoreUsage
+	}
+	return nil
+}
+
+func (x *DetailedSystemStatsResponse) GetLoad() *LoadAverage {
+	if x != nil {
+		return x.Load
+	}
+	return nil
+}
+
+func (x *DetailedSystemStatsResponse) GetInterfaces() []*InterfaceStats {
+	if x != nil {
+		return x.Interfaces
+	}
+	return nil
+}
+
+func (x *DetailedSystemStatsResponse) GetDisks() []*DiskUsage {
+	if x != nil {
+		return x.Disks
+	}
+	return nil
+}
+
+func (x *DetailedSystemStatsResponse) GetOpenFileDescriptors() uint64 {
+	if x != nil {
+		return x.OpenFileDescriptors
+	}
+	return 0
+}
+
+func (x *DetailedSystemStatsResponse) GetMaxFileDescriptors() uint64 {
+	if x != nil {
+		return x.MaxFileDescriptors
+	}
+	return 0
+}
+
+func (x *DetailedSystemStatsResponse) GetSockets() []*SocketStats {
+	if x != nil {
+		return x.Sockets
+	}
+	return nil
+}
+
+func (x *DetailedSystemStatsResponse) GetUptime() uint64 {
+	if x != nil {
+		return x.Uptime
+	}
+	return 0
+}
+
+type IpLimitViolation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	MaxIps        uint32                 `protobuf:"varint,2,opt,name=max_ips,json=maxIps,proto3" json:"max_ips,omitempty"`
+	Ips           []string               `protobuf:"bytes,3,rep,name=ips,proto3" json:"ips,omitempty"` // online IPs when the violation was detected
+	BlockedIps    []string               `protobuf:"bytes,4,rep,name=blocked_ips,json=blockedIps,proto3" json:"blocked_ips,omitempty"`
+	Action        IpLimitAction          `protobuf:"varint,5,opt,name=action,proto3,enum=service.IpLimitAction" json:"action,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IpLimitViolation) Reset() {
+	*x = IpLimitViolation{}
+	mi := &file_common_service_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IpLimitViolation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IpLimitViolation) ProtoMessage() {}
+
+func (x *IpLimitViolation) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IpLimitViolation.ProtoReflect.Descriptor instead.
+func (*IpLimitViolation) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *IpLimitViolation) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *IpLimitViolation) GetMaxIps() uint32 {
+	if x != nil {
+		return x.MaxIps
+	}
+	return 0
+}
+
+func (x *IpLimitViolation) GetIps() []string {
+	if x != nil {
+		return x.Ips
+	}
+	return nil
+}
+
+func (x *IpLimitViolation) GetBlockedIps() []string {
+	if x != nil {
+		return x.BlockedIps
+	}
+	return nil
+}
+
+func (x *IpLimitViolation) GetAction() IpLimitAction {
+	if x != nil {
+		return x.Action
+	}
+	return IpLimitAction_BLOCK_IPS
+}
+
+func (x *IpLimitViolation) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type IpLimitViolationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`  // empty means every user
+	Since         int64                  `protobuf:"varint,2,opt,name=since,proto3" json:"since,omitempty"` // unix seconds
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IpLimitViolationsRequest) Reset() {
+	*x = IpLimitViolationsRequest{}
+	mi := &file_common_service_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IpLimitViolationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IpLimitViolationsRequest) ProtoMessage() {}
+
+func (x *IpLimitViolationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IpLimitViolationsRequest.ProtoReflect.Descriptor instead.
+func (*IpLimitViolationsRequest) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *IpLimitViolationsRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *IpLimitViolationsRequest) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+type IpLimitViolationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Violations    []*IpLimitViolation    `protobuf:"bytes,1,rep,name=violations,proto3" json:"violations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IpLimitViolationsResponse) Reset() {
+	*x = IpLimitViolationsResponse{}
+	mi := &file_common_service_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IpLimitViolationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IpLimitViolationsResponse) ProtoMessage() {}
+
+func (x *IpLimitViolationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IpLimitViolationsResponse.ProtoReflect.Descriptor instead.
+func (*IpLimitViolationsResponse) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *IpLimitViolationsResponse) GetViolations() []*IpLimitViolation {
+	if x != nil {
+		return x.Violations
+	}
+	return nil
+}
+
+// User
+type PeerAllocation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	PeerIps       []string               `protobuf:"bytes,2,rep,name=peer_ips,json=peerIps,proto3" json:"peer_ips,omitempty"`
+	Allocated     bool                   `protobuf:"varint,3,opt,name=allocated,proto3" json:"allocated,omitempty"` // assigned by the node rather than sent by the panel
+	Inbound       string                 `protobuf:"bytes,4,opt,name=inbound,proto3" json:"inbound,omitempty"`      // wireguard interface holding the peer
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PeerAllocation) Reset() {
+	*x = PeerAllocation{}
+	mi := &file_common_service_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PeerAllocation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerAllocation) ProtoMessage() {}
+
+func (x *PeerAllocation) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerAllocation.ProtoReflect.Descriptor instead.
+func (*PeerAllocation) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *PeerAllocation) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *PeerAllocation) GetPeerIps() []string {
+	if x != nil {
+		return x.PeerIps
+	}
+	return nil
+}
+
+func (x *PeerAllocation) GetAllocated() bool {
+	if x != nil {
+		return x.Allocated
+	}
+	return false
+}
+
+func (x *PeerAllocation) GetInbound() string {
+	if x != nil {
+		return x.Inbound
+	}
+	return ""
+}
+
+type PeerConflict struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	PeerIp        string                 `protobuf:"bytes,2,opt,name=peer_ip,json=peerIp,proto3" json:"peer_ip,omitempty"`
+	Detail        string                 `protobuf:"bytes,3,opt,name=detail,proto3" json:"detail,omitempty"`
+	Inbound       string                 `protobuf:"bytes,4,opt,name=inbound,proto3" json:"inbound,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PeerConflict) Reset() {
+	*x = PeerConflict{}
+	mi := &file_common_service_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PeerConflict) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerConflict) ProtoMessage() {}
+
+func (x *PeerConflict) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerConflict.ProtoReflect.Descriptor instead.
+func (*PeerConflict) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *PeerConflict) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *PeerConflict) GetPeerIp() string {
+	if x != nil {
+		return x.PeerIp
+	}
+	return ""
+}
+
+func (x *PeerConflict) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+func (x *PeerConflict) GetInbound() string {
+	if x != nil {
+		return x.Inbound
+	}
+	return ""
+}
+
+type PeerAllocationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Allocations   []*PeerAllocation      `protobuf:"bytes,1,rep,name=allocations,proto3" json:"allocations,omitempty"`
+	Conflicts     []*PeerConflict        `protobuf:"bytes,2,rep,name=conflicts,proto3" json:"conflicts,omitempty"` // users left out of the last sync
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PeerAllocationsResponse) Reset() {
+	*x = PeerAllocationsResponse{}
+	mi := &file_common_service_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PeerAllocationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerAllocationsResponse) ProtoMessage() {}
+
+func (x *PeerAllocationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerAllocationsResponse.ProtoReflect.Descriptor instead.
+func (*PeerAllocationsResponse) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *PeerAllocationsResponse) GetAllocations() []*PeerAllocation {
+	if x != nil {
+		return x.Allocations
+	}
+	return nil
+}
+
+func (x *PeerAllocationsResponse) GetConflicts() []*PeerConflict {
+	if x != nil {
+		return x.Conflicts
+	}
+	return nil
+}
+
+type ClientConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Endpoint      string                 `protobuf:"bytes,2,opt,name=endpoint,proto3" json:"endpoint,omitempty"` // host or host:port clients connect to, the listen port is used when omitted
+	Dns           []string               `protobuf:"bytes,3,rep,name=dns,proto3" json:"dns,omitempty"`
+	AllowedIps    []string               `protobuf:"bytes,4,rep,name=allowed_ips,json=allowedIps,proto3" json:"allowed_ips,omitempty"` // routes sent through the tunnel, all traffic when empty
+	PrivateKey    string                 `protobuf:"bytes,5,opt,name=private_key,json=privateKey,proto3" json:"private_key,omitempty"` // client private key, a placeholder is written when empty
+	QrPng         bool                   `protobuf:"varint,6,opt,name=qr_png,json=qrPng,proto3" json:"qr_png,omitempty"`
+	QrAscii       bool                   `protobuf:"varint,7,opt,name=qr_ascii,json=qrAscii,proto3" json:"qr_ascii,omitempty"`
+	Inbound       string                 `protobuf:"bytes,8,opt,name=inbound,proto3" json:"inbound,omitempty"` // interface to build the config for, the first one holding the user when empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClientConfigRequest) Reset() {
+	*x = ClientConfigRequest{}
+	mi := &file_common_service_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClientConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientConfigRequest) ProtoMessage() {}
+
+func (x *ClientConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientConfigRequest.ProtoReflect.Descriptor instead.
+func (*ClientConfigRequest) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ClientConfigRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *ClientConfigRequest) GetEndpoint() string {
+	if x != nil {
+		return x.Endpoint
+	}
+	return ""
+}
+
+func (x *ClientConfigRequest) GetDns() []string {
+	if x != nil {
+		return x.Dns
+	}
+	return nil
+}
+
+func (x *ClientConfigRequest) GetAllowedIps() []string {
+	if x != nil {
+		return x.AllowedIps
+	}
+	return nil
+}
+
+func (x *ClientConfigRequest) GetPrivateKey() string {
+	if x != nil {
+		return x.PrivateKey
+	}
+	return ""
+}
+
+func (x *ClientConfigRequest) GetQrPng() bool {
+	if x != nil {
+		return x.QrPng
+	}
+	return false
+}
+
+func (x *ClientConfigRequest) GetQrAscii() bool {
+	if x != nil {
+		return x.QrAscii
+	}
+	return false
+}
+
+func (x *ClientConfigRequest) GetInbound() string {
+	if x != nil {
+		return x.Inbound
+	}
+	return ""
+}
+
+type ClientConfigResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Config        string                 `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"` // wg-quick config
+	QrPng         []byte                 `protobuf:"bytes,2,opt,name=qr_png,json=qrPng,proto3" json:"qr_png,omitempty"`
+	QrAscii       string                 `protobuf:"bytes,3,opt,name=qr_ascii,json=qrAscii,proto3" json:"qr_ascii,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClientConfigResponse) Reset() {
+	*x = ClientConfigResponse{}
+	mi := &file_common_service_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClientConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientConfigResponse) ProtoMessage() {}
+
+func (x *ClientConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientConfigResponse.ProtoReflect.Descriptor instead.
+func (*ClientConfigResponse) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ClientConfigResponse) GetConfig() string {
+	if x != nil {
+		return x.Config
+	}
+	return ""
+}
+
+func (x *ClientConfigResponse) GetQrPng() []byte {
+	if x != nil {
+		return x.QrPng
+	}
+	return nil
+}
+
+func (x *ClientConfigResponse) GetQrAscii() string {
+	if x != nil {
+		return x.QrAscii
+	}
+	return ""
+}
+
+type PeerEvent struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Type             PeerEventType          `protobuf:"varint,1,opt,name=type,proto3,enum=service.PeerEventType" json:"type,omitempty"`
+	Email            string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Endpoint         string                 `protobuf:"bytes,3,opt,name=endpoint,proto3" json:"endpoint,omitempty"`                                         // ip:port
+	PreviousEndpoint string                 `protobuf:"bytes,4,opt,name=previous_endpoint,json=previousEndpoint,proto3" json:"previous_endpoint,omitempty"` // set on ROAMED
+	Timestamp        int64                  `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                                      // unix seconds
+	Duration         int64                  `protobuf:"varint,6,opt,name=duration,proto3" json:"duration,omitempty"`                                        // seconds since the session started
+	Inbound          string                 `protobuf:"bytes,7,opt,name=inbound,proto3" json:"inbound,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *PeerEvent) Reset() {
+	*x = PeerEvent{}
+	mi := &file_common_service_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PeerEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerEvent) ProtoMessage() {}
+
+func (x *PeerEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerEvent.ProtoReflect.Descriptor instead.
+func (*PeerEvent) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *PeerEvent) GetType() PeerEventType {
+	if x != nil {
+		return x.Type
+	}
+	return PeerEventType_CONNECTED
+}
+
+func (x *PeerEvent) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *PeerEvent) GetEndpoint() string {
+	if x != nil {
+		return x.Endpoint
+	}
+	return ""
+}
+
+func (x *PeerEvent) GetPreviousEndpoint() string {
+	if x != nil {
+		return x.PreviousEndpoint
+	}
+	return ""
+}
+
+func (x *PeerEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *PeerEvent) GetDuration() int64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *PeerEvent) GetInbound() string {
+	if x != nil {
+		return x.Inbound
+	}
+	return ""
+}
+
+type PeerSession struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Inbound       string                 `protobuf:"bytes,1,opt,name=inbound,proto3" json:"inbound,omitempty"`
+	Endpoints     []string               `protobuf:"bytes,2,rep,name=endpoints,proto3" json:"endpoints,omitempty"`                   // in the order they were used
+	StartedAt     int64                  `protobuf:"varint,3,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"` // unix seconds
+	EndedAt       int64                  `protobuf:"varint,4,opt,name=ended_at,json=endedAt,proto3" json:"ended_at,omitempty"`       // 0 while the session is active
+	Duration      int64                  `protobuf:"varint,5,opt,name=duration,proto3" json:"duration,omitempty"`                    // seconds
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PeerSession) Reset() {
+	*x = PeerSession{}
+	mi := &file_common_service_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PeerSession) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerSession) ProtoMessage() {}
+
+func (x *PeerSession) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerSession.ProtoReflect.Descriptor instead.
+func (*PeerSession) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *PeerSession) GetInbound() string {
+	if x != nil {
+		return x.Inbound
+	}
+	return ""
+}
+
+func (x *PeerSession) GetEndpoints() []string {
+	if x != nil {
+		return x.Endpoints
+	}
+	return nil
+}
+
+func (x *PeerSession) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *PeerSession) GetEndedAt() int64 {
+	if x != nil {
+		return x.EndedAt
+	}
+	return 0
+}
+
+func (x *PeerSession) GetDuration() int64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+type PeerSessionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PeerSessionsRequest) Reset() {
+	*x = PeerSessionsRequest{}
+	mi := &file_common_service_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PeerSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerSessionsRequest) ProtoMessage() {}
+
+func (x *PeerSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerSessionsRequest.ProtoReflect.Descriptor instead.
+func (*PeerSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *PeerSessionsRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type PeerSessionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Sessions      []*PeerSession         `protobuf:"bytes,2,rep,name=sessions,proto3" json:"sessions,omitempty"` // oldest first, the active session last
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PeerSessionsResponse) Reset() {
+	*x = PeerSessionsResponse{}
+	mi := &file_common_service_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PeerSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerSessionsResponse) ProtoMessage() {}
+
+func (x *PeerSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerSessionsResponse.ProtoReflect.Descriptor instead.
+func (*PeerSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *PeerSessionsResponse) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *PeerSessionsResponse) GetSessions() []*PeerSession {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+type PolicyRule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cidr          string                 `protobuf:"bytes,1,opt,name=cidr,proto3" json:"cidr,omitempty"`         // destination network
+	Protocol      string                 `protobuf:"bytes,2,opt,name=protocol,proto3" json:"protocol,omitempty"` // tcp, udp or empty for both
+	Ports         []string               `protobuf:"bytes,3,rep,name=ports,proto3" json:"ports,omitempty"`       // destination ports or ranges like 8000-8100, all ports when empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PolicyRule) Reset() {
+	*x = PolicyRule{}
+	mi := &file_common_service_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PolicyRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PolicyRule) ProtoMessage() {}
+
+func (x *PolicyRule) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PolicyRule.ProtoReflect.Descriptor instead.
+func (*PolicyRule) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *PolicyRule) GetCidr() string {
+	if x != nil {
+		return x.Cidr
+	}
+	return ""
+}
+
+func (x *PolicyRule) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *PolicyRule) GetPorts() []string {
+	if x != nil {
+		return x.Ports
+	}
+	return nil
+}
+
+type Policy struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	BlockPeerToPeer      bool                   `protobuf:"varint,1,opt,name=block_peer_to_peer,json=blockPeerToPeer,proto3" json:"block_peer_to_peer,omitempty"`              // drop traffic between peers of the interface
+	BlockPrivateNetworks bool                   `protobuf:"varint,2,opt,name=block_private_networks,json=blockPrivateNetworks,proto3" json:"block_private_networks,omitempty"` // drop forwarding to RFC1918, CGNAT, ULA and link-local destinations
+	Allow                []*PolicyRule          `protobuf:"bytes,3,rep,name=allow,proto3" json:"allow,omitempty"`                                                              // checked before every block
+	Deny                 []*PolicyRule          `protobuf:"bytes,4,rep,name=deny,proto3" json:"deny,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *Policy) Reset() {
+	*x = Policy{}
+	mi := &file_common_service_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Policy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Policy) ProtoMessage() {}
+
+func (x *Policy) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Policy.ProtoReflect.Descriptor instead.
+func (*Policy) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *Policy) GetBlockPeerToPeer() bool {
+	if x != nil {
+		return x.BlockPeerToPeer
+	}
+	return false
+}
+
+func (x *Policy) GetBlockPrivateNetworks() bool {
+	if x != nil {
+		return x.BlockPrivateNetworks
+	}
+	return false
+}
+
+func (x *Policy) GetAllow() []*PolicyRule {
+	if x != nil {
+		return x.Allow
+	}
+	return nil
+}
+
+func (x *Policy) GetDeny() []*PolicyRule {
+	if x != nil {
+		return x.Deny
+	}
+	return nil
+}
+
+type PolicyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Inbound       string                 `protobuf:"bytes,1,opt,name=inbound,proto3" json:"inbound,omitempty"` // interface to update, every interface when empty
+	Policy        *Policy                `protobuf:"bytes,2,opt,name=policy,proto3" json:"policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PolicyRequest) Reset() {
+	*x = PolicyRequest{}
+	mi := &file_common_service_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PolicyRequest) ProtoMessage() {}
+
+func (x *PolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PolicyRequest.ProtoReflect.Descriptor instead.
+func (*PolicyRequest) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *PolicyRequest) GetInbound() string {
+	if x != nil {
+		return x.Inbound
+	}
+	return ""
+}
+
+func (x *PolicyRequest) GetPolicy() *Policy {
+	if x != nil {
+		return x.Policy
+	}
+	return nil
+}
+
+// OutboundConfig is one xray outbound object.
+type OutboundConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tag           string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	Protocol      string                 `protobuf:"bytes,2,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	Config        string                 `protobuf:"bytes,3,opt,name=config,proto3" json:"config,omitempty"` // the outbound as xray config JSON
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OutboundConfig) Reset() {
+	*x = OutboundConfig{}
+	mi := &file_common_service_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OutboundConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutboundConfig) ProtoMessage() {}
+
+func (x *OutboundConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutboundConfig.ProtoReflect.Descriptor instead.
+func (*OutboundConfig) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *OutboundConfig) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *OutboundConfig) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *OutboundConfig) GetConfig() string {
+	if x != nil {
+		return x.Config
+	}
+	return ""
+}
+
+type AddOutboundRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Config        string                 `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`    // the outbound as xray config JSON, tag required
+	Replace       bool                   `protobuf:"varint,2,opt,name=replace,proto3" json:"replace,omitempty"` // replace an outbound with the same tag instead of failing
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddOutboundRequest) Reset() {
+	*x = AddOutboundRequest{}
+	mi := &file_common_service_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddOutboundRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddOutboundRequest) ProtoMessage() {}
+
+func (x *AddOutboundRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddOutboundRequest.ProtoReflect.Descriptor instead.
+func (*AddOutboundRequest) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *AddOutboundRequest) GetConfig() string {
+	if x != nil {
+		return x.Config
+	}
+	return ""
+}
+
+func (x *AddOutboundRequest) GetReplace() bool {
+	if x != nil {
+		return x.Replace
+	}
+	return false
+}
+
+type RemoveOutboundRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tag           string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveOutboundRequest) Reset() {
+	*x = RemoveOutboundRequest{}
+	mi := &file_common_service_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveOutboundRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveOutboundRequest) ProtoMessage() {}
+
+func (x *RemoveOutboundRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveOutboundRequest.ProtoReflect.Descriptor instead.
+func (*RemoveOutboundRequest) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *RemoveOutboundRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+type OutboundsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Outbounds     []*OutboundConfig      `protobuf:"bytes,1,rep,name=outbounds,proto3" json:"outbounds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OutboundsResponse) Reset() {
+	*x = OutboundsResponse{}
+	mi := &file_common_service_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OutboundsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutboundsResponse) ProtoMessage() {}
+
+func (x *OutboundsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutboundsResponse.ProtoReflect.Descriptor instead.
+func (*OutboundsResponse) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *OutboundsResponse) GetOutbounds() []*OutboundConfig {
+	if x != nil {
+		return x.Outbounds
+	}
+	return nil
+}
+
+// RoutingRule is one xray routing rule.
+type RoutingRule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tag           string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`       // the rule's ruleTag, empty for untagged rules
+	Config        string                 `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"` // the rule as xray config JSON
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoutingRule) Reset() {
+	*x = RoutingRule{}
+	mi := &file_common_service_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoutingRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoutingRule) ProtoMessage() {}
+
+func (x *RoutingRule) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoutingRule.ProtoReflect.Descriptor instead.
+func (*RoutingRule) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *RoutingRule) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *RoutingRule) GetConfig() string {
+	if x != nil {
+		return x.Config
+	}
+	return ""
+}
+
+type AddRoutingRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Config        string                 `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`    // the rule as xray config JSON, ruleTag required
+	Prepend       bool                   `protobuf:"varint,2,opt,name=prepend,proto3" json:"prepend,omitempty"` // put the rule before the config's rules instead of after them
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddRoutingRuleRequest) Reset() {
+	*x = AddRoutingRuleRequest{}
+	mi := &file_common_service_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BackendStatsResponse) String() string {
+func (x *AddRoutingRuleRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BackendStatsResponse) ProtoMessage() {}
+func (*AddRoutingRuleRequest) ProtoMessage() {}
 
-func (x *BackendStatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_common_service_proto_msgTypes[12]
+func (x *AddRoutingRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -841,109 +3544,91 @@ func (x *BackendStatsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BackendStatsResponse.ProtoReflect.Descriptor instead.
-func (*BackendStatsResponse) Descriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{12}
-}
-
-func (x *BackendStatsResponse) GetNumGoroutine() uint32 {
-	if x != nil {
-		return x.NumGoroutine
-	}
-	return 0
+// Deprecated: Use AddRoutingRuleRequest.ProtoReflect.Descriptor instead.
+func (*AddRoutingRuleRequest) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{47}
 }
 
-func (x *BackendStatsResponse) GetNumGc() uint32 {
+func (x *AddRoutingRuleRequest) GetConfig() string {
 	if x != nil {
-		return x.NumGc
+		return x.Config
 	}
-	return 0
+	return ""
 }
 
-func (x *BackendStatsResponse) GetAlloc() uint64 {
+func (x *AddRoutingRuleRequest) GetPrepend() bool {
 	if x != nil {
-		return x.Alloc
+		return x.Prepend
 	}
-	return 0
+	return false
 }
 
-func (x *BackendStatsResponse) GetTotalAlloc() uint64 {
-	if x != nil {
-		return x.TotalAlloc
-	}
-	return 0
+type RemoveRoutingRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tag           string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BackendStatsResponse) GetSys() uint64 {
-	if x != nil {
-		return x.Sys
-	}
-	return 0
+func (x *RemoveRoutingRuleRequest) Reset() {
+	*x = RemoveRoutingRuleRequest{}
+	mi := &file_common_service_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *BackendStatsResponse) GetMallocs() uint64 {
-	if x != nil {
-		return x.Mallocs
-	}
-	return 0
+func (x *RemoveRoutingRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *BackendStatsResponse) GetFrees() uint64 {
-	if x != nil {
-		return x.Frees
-	}
-	return 0
-}
+func (*RemoveRoutingRuleRequest) ProtoMessage() {}
 
-func (x *BackendStatsResponse) GetLiveObjects() uint64 {
+func (x *RemoveRoutingRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[48]
 	if x != nil {
-		return x.LiveObjects
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *BackendStatsResponse) GetPauseTotalNs() uint64 {
-	if x != nil {
-		return x.PauseTotalNs
-	}
-	return 0
+// Deprecated: Use RemoveRoutingRuleRequest.ProtoReflect.Descriptor instead.
+func (*RemoveRoutingRuleRequest) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{48}
 }
 
-func (x *BackendStatsResponse) GetUptime() uint32 {
+func (x *RemoveRoutingRuleRequest) GetTag() string {
 	if x != nil {
-		return x.Uptime
+		return x.Tag
 	}
-	return 0
+	return ""
 }
 
-type SystemStatsResponse struct {
-	state                  protoimpl.MessageState `protogen:"open.v1"`
-	MemTotal               uint64                 `protobuf:"varint,1,opt,name=mem_total,json=memTotal,proto3" json:"mem_total,omitempty"`
-	MemUsed                uint64                 `protobuf:"varint,2,opt,name=mem_used,json=memUsed,proto3" json:"mem_used,omitempty"`
-	CpuCores               uint64                 `protobuf:"varint,3,opt,name=cpu_cores,json=cpuCores,proto3" json:"cpu_cores,omitempty"`
-	CpuUsage               float64                `protobuf:"fixed64,4,opt,name=cpu_usage,json=cpuUsage,proto3" json:"cpu_usage,omitempty"`
-	IncomingBandwidthSpeed uint64                 `protobuf:"varint,5,opt,name=incoming_bandwidth_speed,json=incomingBandwidthSpeed,proto3" json:"incoming_bandwidth_speed,omitempty"`
-	OutgoingBandwidthSpeed uint64                 `protobuf:"varint,6,opt,name=outgoing_bandwidth_speed,json=outgoingBandwidthSpeed,proto3" json:"outgoing_bandwidth_speed,omitempty"`
-	Uptime                 uint64                 `protobuf:"varint,7,opt,name=uptime,proto3" json:"uptime,omitempty"`
-	unknownFields          protoimpl.UnknownFields
-	sizeCache              protoimpl.SizeCache
+type RoutingRulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rules         []*RoutingRule         `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SystemStatsResponse) Reset() {
-	*x = SystemStatsResponse{}
-	mi := &file_common_service_proto_msgTypes[13]
+func (x *RoutingRulesResponse) Reset() {
+	*x = RoutingRulesResponse{}
+	mi := &file_common_service_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SystemStatsResponse) String() string {
+func (x *RoutingRulesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SystemStatsResponse) ProtoMessage() {}
+func (*RoutingRulesResponse) ProtoMessage() {}
 
-func (x *SystemStatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_common_service_proto_msgTypes[13]
+func (x *RoutingRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -954,61 +3639,70 @@ func (x *SystemStatsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SystemStatsResponse.ProtoReflect.Descriptor instead.
-func (*SystemStatsResponse) Descriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use RoutingRulesResponse.ProtoReflect.Descriptor instead.
+func (*RoutingRulesResponse) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{49}
 }
 
-func (x *SystemStatsResponse) GetMemTotal() uint64 {
+func (x *RoutingRulesResponse) GetRules() []*RoutingRule {
 	if x != nil {
-		return x.MemTotal
+		return x.Rules
 	}
-	return 0
+	return nil
 }
 
-func (x *SystemStatsResponse) GetMemUsed() uint64 {
-	if x != nil {
-		return x.MemUsed
-	}
-	return 0
+type BalancerTargetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BalancerTag   string                 `protobuf:"bytes,1,opt,name=balancer_tag,json=balancerTag,proto3" json:"balancer_tag,omitempty"`
+	Target        string                 `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"` // outbound tag, empty clears the override
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SystemStatsResponse) GetCpuCores() uint64 {
-	if x != nil {
-		return x.CpuCores
-	}
-	return 0
+func (x *BalancerTargetRequest) Reset() {
+	*x = BalancerTargetRequest{}
+	mi := &file_common_service_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *SystemStatsResponse) GetCpuUsage() float64 {
-	if x != nil {
-		return x.CpuUsage
-	}
-	return 0
+func (x *BalancerTargetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *SystemStatsResponse) GetIncomingBandwidthSpeed() uint64 {
+func (*BalancerTargetRequest) ProtoMessage() {}
+
+func (x *BalancerTargetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[50]
 	if x != nil {
-		return x.IncomingBandwidthSpeed
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *SystemStatsResponse) GetOutgoingBandwidthSpeed() uint64 {
+// Deprecated: Use BalancerTargetRequest.ProtoReflect.Descriptor instead.
+func (*BalancerTargetRequest) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *BalancerTargetRequest) GetBalancerTag() string {
 	if x != nil {
-		return x.OutgoingBandwidthSpeed
+		return x.BalancerTag
 	}
-	return 0
+	return ""
 }
 
-func (x *SystemStatsResponse) GetUptime() uint64 {
+func (x *BalancerTargetRequest) GetTarget() string {
 	if x != nil {
-		return x.Uptime
+		return x.Target
 	}
-	return 0
+	return ""
 }
 
-// User
 type Vmess struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -1018,7 +3712,7 @@ type Vmess struct {
 
 func (x *Vmess) Reset() {
 	*x = Vmess{}
-	mi := &file_common_service_proto_msgTypes[14]
+	mi := &file_common_service_proto_msgTypes[51]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1030,7 +3724,7 @@ func (x *Vmess) String() string {
 func (*Vmess) ProtoMessage() {}
 
 func (x *Vmess) ProtoReflect() protoreflect.Message {
-	mi := &file_common_service_proto_msgTypes[14]
+	mi := &file_common_service_proto_msgTypes[51]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1043,7 +3737,7 @@ func (x *Vmess) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Vmess.ProtoReflect.Descriptor instead.
 func (*Vmess) Descriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{14}
+	return file_common_service_proto_rawDescGZIP(), []int{51}
 }
 
 func (x *Vmess) GetId() string {
@@ -1063,7 +3757,7 @@ type Vless struct {
 
 func (x *Vless) Reset() {
 	*x = Vless{}
-	mi := &file_common_service_proto_msgTypes[15]
+	mi := &file_common_service_proto_msgTypes[52]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1075,7 +3769,7 @@ func (x *Vless) String() string {
 func (*Vless) ProtoMessage() {}
 
 func (x *Vless) ProtoReflect() protoreflect.Message {
-	mi := &file_common_service_proto_msgTypes[15]
+	mi := &file_common_service_proto_msgTypes[52]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1088,7 +3782,7 @@ func (x *Vless) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Vless.ProtoReflect.Descriptor instead.
 func (*Vless) Descriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{15}
+	return file_common_service_proto_rawDescGZIP(), []int{52}
 }
 
 func (x *Vless) GetId() string {
@@ -1114,7 +3808,7 @@ type Trojan struct {
 
 func (x *Trojan) Reset() {
 	*x = Trojan{}
-	mi := &file_common_service_proto_msgTypes[16]
+	mi := &file_common_service_proto_msgTypes[53]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1126,7 +3820,7 @@ func (x *Trojan) String() string {
 func (*Trojan) ProtoMessage() {}
 
 func (x *Trojan) ProtoReflect() protoreflect.Message {
-	mi := &file_common_service_proto_msgTypes[16]
+	mi := &file_common_service_proto_msgTypes[53]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1139,7 +3833,7 @@ func (x *Trojan) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Trojan.ProtoReflect.Descriptor instead.
 func (*Trojan) Descriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{16}
+	return file_common_service_proto_rawDescGZIP(), []int{53}
 }
 
 func (x *Trojan) GetPassword() string {
@@ -1159,7 +3853,7 @@ type Shadowsocks struct {
 
 func (x *Shadowsocks) Reset() {
 	*x = Shadowsocks{}
-	mi := &file_common_service_proto_msgTypes[17]
+	mi := &file_common_service_proto_msgTypes[54]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1171,7 +3865,7 @@ func (x *Shadowsocks) String() string {
 func (*Shadowsocks) ProtoMessage() {}
 
 func (x *Shadowsocks) ProtoReflect() protoreflect.Message {
-	mi := &file_common_service_proto_msgTypes[17]
+	mi := &file_common_service_proto_msgTypes[54]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1184,7 +3878,7 @@ func (x *Shadowsocks) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Shadowsocks.ProtoReflect.Descriptor instead.
 func (*Shadowsocks) Descriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{17}
+	return file_common_service_proto_rawDescGZIP(), []int{54}
 }
 
 func (x *Shadowsocks) GetPassword() string {
@@ -1202,16 +3896,20 @@ func (x *Shadowsocks) GetMethod() string {
 }
 
 type Wireguard struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	PublicKey     string                 `protobuf:"bytes,1,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
-	PeerIps       []string               `protobuf:"bytes,2,rep,name=peer_ips,json=peerIps,proto3" json:"peer_ips,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	PublicKey           string                 `protobuf:"bytes,1,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	PeerIps             []string               `protobuf:"bytes,2,rep,name=peer_ips,json=peerIps,proto3" json:"peer_ips,omitempty"`
+	UploadRate          uint64                 `protobuf:"varint,3,opt,name=upload_rate,json=uploadRate,proto3" json:"upload_rate,omitempty"`                            // bytes per second from the peer, 0 means unlimited
+	DownloadRate        uint64                 `protobuf:"varint,4,opt,name=download_rate,json=downloadRate,proto3" json:"download_rate,omitempty"`                      // bytes per second to the peer, 0 means unlimited
+	PresharedKey        string                 `protobuf:"bytes,5,opt,name=preshared_key,json=presharedKey,proto3" json:"preshared_key,omitempty"`                       // base64, overrides the interface pre_shared_key
+	PersistentKeepalive uint32                 `protobuf:"varint,6,opt,name=persistent_keepalive,json=persistentKeepalive,proto3" json:"persistent_keepalive,omitempty"` // seconds, 0 keeps the interface default
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
 }
 
 func (x *Wireguard) Reset() {
 	*x = Wireguard{}
-	mi := &file_common_service_proto_msgTypes[18]
+	mi := &file_common_service_proto_msgTypes[55]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1223,7 +3921,7 @@ func (x *Wireguard) String() string {
 func (*Wireguard) ProtoMessage() {}
 
 func (x *Wireguard) ProtoReflect() protoreflect.Message {
-	mi := &file_common_service_proto_msgTypes[18]
+	mi := &file_common_service_proto_msgTypes[55]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1236,7 +3934,7 @@ func (x *Wireguard) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Wireguard.ProtoReflect.Descriptor instead.
 func (*Wireguard) Descriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{18}
+	return file_common_service_proto_rawDescGZIP(), []int{55}
 }
 
 func (x *Wireguard) GetPublicKey() string {
@@ -1253,6 +3951,34 @@ func (x *Wireguard) GetPeerIps() []string {
 	return nil
 }
 
+func (x *Wireguard) GetUploadRate() uint64 {
+	if x != nil {
+		return x.UploadRate
+	}
+	return 0
+}
+
+func (x *Wireguard) GetDownloadRate() uint64 {
+	if x != nil {
+		return x.DownloadRate
+	}
+	return 0
+}
+
+func (x *Wireguard) GetPresharedKey() string {
+	if x != nil {
+		return x.PresharedKey
+	}
+	return ""
+}
+
+func (x *Wireguard) GetPersistentKeepalive() uint32 {
+	if x != nil {
+		return x.PersistentKeepalive
+	}
+	return 0
+}
+
 type Hysteria struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Auth          string                 `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
@@ -1262,7 +3988,7 @@ type Hysteria struct {
 
 func (x *Hysteria) Reset() {
 	*x = Hysteria{}
-	mi := &file_common_service_proto_msgTypes[19]
+	mi := &file_common_service_proto_msgTypes[56]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1274,7 +4000,7 @@ func (x *Hysteria) String() string {
 func (*Hysteria) ProtoMessage() {}
 
 func (x *Hysteria) ProtoReflect() protoreflect.Message {
-	mi := &file_common_service_proto_msgTypes[19]
+	mi := &file_common_service_proto_msgTypes[56]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1287,7 +4013,7 @@ func (x *Hysteria) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Hysteria.ProtoReflect.Descriptor instead.
 func (*Hysteria) Descriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{19}
+	return file_common_service_proto_rawDescGZIP(), []int{56}
 }
 
 func (x *Hysteria) GetAuth() string {
@@ -1311,7 +4037,7 @@ type Proxy struct {
 
 func (x *Proxy) Reset() {
 	*x = Proxy{}
-	mi := &file_common_service_proto_msgTypes[20]
+	mi := &file_common_service_proto_msgTypes[57]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1323,7 +4049,7 @@ func (x *Proxy) String() string {
 func (*Proxy) ProtoMessage() {}
 
 func (x *Proxy) ProtoReflect() protoreflect.Message {
-	mi := &file_common_service_proto_msgTypes[20]
+	mi := &file_common_service_proto_msgTypes[57]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1336,7 +4062,7 @@ func (x *Proxy) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Proxy.ProtoReflect.Descriptor instead.
 func (*Proxy) Descriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{20}
+	return file_common_service_proto_rawDescGZIP(), []int{57}
 }
 
 func (x *Proxy) GetVmess() *Vmess {
@@ -1382,17 +4108,24 @@ func (x *Proxy) GetHysteria() *Hysteria {
 }
 
 type User struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
-	Proxies       *Proxy                 `protobuf:"bytes,2,opt,name=proxies,proto3" json:"proxies,omitempty"`
-	Inbounds      []string               `protobuf:"bytes,3,rep,name=inbounds,proto3" json:"inbounds,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Email                  string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Proxies                *Proxy                 `protobuf:"bytes,2,opt,name=proxies,proto3" json:"proxies,omitempty"`
+	Inbounds               []string               `protobuf:"bytes,3,rep,name=inbounds,proto3" json:"inbounds,omitempty"`
+	DataLimit              uint64                 `protobuf:"varint,4,opt,name=data_limit,json=dataLimit,proto3" json:"data_limit,omitempty"`       // bytes, 0 means unlimited
+	UsedTraffic            uint64                 `protobuf:"varint,5,opt,name=used_traffic,json=usedTraffic,proto3" json:"used_traffic,omitempty"` // bytes already accounted by the panel
+	DataLimitResetStrategy DataLimitResetStrategy `protobuf:"varint,6,opt,name=data_limit_reset_strategy,json=dataLimitResetStrategy,proto3,enum=service.DataLimitResetStrategy" json:"data_limit_reset_strategy,omitempty"`
+	LastResetAt            int64                  `protobuf:"varint,7,opt,name=last_reset_at,json=lastResetAt,proto3" json:"last_reset_at,omitempty"` // unix seconds when the current usage period started
+	ExpireAt               int64                  `protobuf:"varint,8,opt,name=expire_at,json=expireAt,proto3" json:"expire_at,omitempty"`            // unix seconds, 0 means never
+	MaxIps                 uint32                 `protobuf:"varint,9,opt,name=max_ips,json=maxIps,proto3" json:"max_ips,omitempty"`                  // concurrent source IPs, 0 means unlimited
+	OutboundTag            string                 `protobuf:"bytes,10,opt,name=outbound_tag,json=outboundTag,proto3" json:"outbound_tag,omitempty"`   // egress through this outbound instead of the default route
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
 }
 
 func (x *User) Reset() {
 	*x = User{}
-	mi := &file_common_service_proto_msgTypes[21]
+	mi := &file_common_service_proto_msgTypes[58]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1404,7 +4137,7 @@ func (x *User) String() string {
 func (*User) ProtoMessage() {}
 
 func (x *User) ProtoReflect() protoreflect.Message {
-	mi := &file_common_service_proto_msgTypes[21]
+	mi := &file_common_service_proto_msgTypes[58]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1417,7 +4150,7 @@ func (x *User) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use User.ProtoReflect.Descriptor instead.
 func (*User) Descriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{21}
+	return file_common_service_proto_rawDescGZIP(), []int{58}
 }
 
 func (x *User) GetEmail() string {
@@ -1441,6 +4174,55 @@ func (x *User) GetInbounds() []string {
 	return nil
 }
 
+func (x *User) GetDataLimit() uint64 {
+	if x != nil {
+		return x.DataLimit
+	}
+	return 0
+}
+
+func (x *User) GetUsedTraffic() uint64 {
+	if x != nil {
+		return x.UsedTraffic
+	}
+	return 0
+}
+
+func (x *User) GetDataLimitResetStrategy() DataLimitResetStrategy {
+	if x != nil {
+		return x.DataLimitResetStrategy
+	}
+	return DataLimitResetStrategy_NO_RESET
+}
+
+func (x *User) GetLastResetAt() int64 {
+	if x != nil {
+		return x.LastResetAt
+	}
+	return 0
+}
+
+func (x *User) GetExpireAt() int64 {
+	if x != nil {
+		return x.ExpireAt
+	}
+	return 0
+}
+
+func (x *User) GetMaxIps() uint32 {
+	if x != nil {
+		return x.MaxIps
+	}
+	return 0
+}
+
+func (x *User) GetOutboundTag() string {
+	if x != nil {
+		return x.OutboundTag
+	}
+	return ""
+}
+
 type Users struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
@@ -1450,7 +4232,7 @@ type Users struct {
 
 func (x *Users) Reset() {
 	*x = Users{}
-	mi := &file_common_service_proto_msgTypes[22]
+	mi := &file_common_service_proto_msgTypes[59]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1462,7 +4244,7 @@ func (x *Users) String() string {
 func (*Users) ProtoMessage() {}
 
 func (x *Users) ProtoReflect() protoreflect.Message {
-	mi := &file_common_service_proto_msgTypes[22]
+	mi := &file_common_service_proto_msgTypes[59]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1475,7 +4257,7 @@ func (x *Users) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Users.ProtoReflect.Descriptor instead.
 func (*Users) Descriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{22}
+	return file_common_service_proto_rawDescGZIP(), []int{59}
 }
 
 func (x *Users) GetUsers() []*User {
@@ -1496,7 +4278,7 @@ type UsersChunk struct {
 
 func (x *UsersChunk) Reset() {
 	*x = UsersChunk{}
-	mi := &file_common_service_proto_msgTypes[23]
+	mi := &file_common_service_proto_msgTypes[60]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1508,7 +4290,7 @@ func (x *UsersChunk) String() string {
 func (*UsersChunk) ProtoMessage() {}
 
 func (x *UsersChunk) ProtoReflect() protoreflect.Message {
-	mi := &file_common_service_proto_msgTypes[23]
+	mi := &file_common_service_proto_msgTypes[60]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1521,7 +4303,7 @@ func (x *UsersChunk) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UsersChunk.ProtoReflect.Descriptor instead.
 func (*UsersChunk) Descriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{23}
+	return file_common_service_proto_rawDescGZIP(), []int{60}
 }
 
 func (x *UsersChunk) GetUsers() []*User {
@@ -1550,11 +4332,12 @@ var File_common_service_proto protoreflect.FileDescriptor
 const file_common_service_proto_rawDesc = "" +
 	"\n" +
 	"\x14common/service.proto\x12\aservice\"\a\n" +
-	"\x05Empty\"r\n" +
+	"\x05Empty\"\x94\x01\n" +
 	"\x10BaseInfoResponse\x12\x18\n" +
 	"\astarted\x18\x01 \x01(\bR\astarted\x12!\n" +
 	"\fcore_version\x18\x02 \x01(\tR\vcoreVersion\x12!\n" +
-	"\fnode_version\x18\x03 \x01(\tR\vnodeVersion\"\xba\x01\n" +
+	"\fnode_version\x18\x03 \x01(\tR\vnodeVersion\x12 \n" +
+	"\vobfuscation\x18\x04 \x01(\tR\vobfuscation\"\xba\x01\n" +
 	"\aBackend\x12(\n" +
 	"\x04type\x18\x01 \x01(\x0e2\x14.service.BackendTypeR\x04type\x12\x16\n" +
 	"\x06config\x18\x02 \x01(\tR\x06config\x12#\n" +
@@ -1568,9 +4351,16 @@ const file_common_service_proto_rawDesc = "" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
 	"\x04type\x18\x02 \x01(\tR\x04type\x12\x12\n" +
 	"\x04link\x18\x03 \x01(\tR\x04link\x12\x14\n" +
-	"\x05value\x18\x04 \x01(\x03R\x05value\"3\n" +
+	"\x05value\x18\x04 \x01(\x03R\x05value\"f\n" +
 	"\fStatResponse\x12#\n" +
-	"\x05stats\x18\x01 \x03(\v2\r.service.StatR\x05stats\"^\n" +
+	"\x05stats\x18\x01 \x03(\v2\r.service.StatR\x05stats\x121\n" +
+	"\x06events\x18\x02 \x03(\v2\x19.service.EnforcementEventR\x06events\"\xc6\x01\n" +
+	"\x10EnforcementEvent\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x122\n" +
+	"\x06action\x18\x02 \x01(\x0e2\x1a.service.EnforcementActionR\x06action\x122\n" +
+	"\x06reason\x18\x03 \x01(\x0e2\x1a.service.EnforcementReasonR\x06reason\x12\x1c\n" +
+	"\ttimestamp\x18\x04 \x01(\x03R\ttimestamp\x12\x16\n" +
+	"\x06detail\x18\x05 \x01(\tR\x06detail\"^\n" +
 	"\vStatRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
 	"\x05reset\x18\x02 \x01(\bR\x05reset\x12%\n" +
@@ -1583,7 +4373,7 @@ const file_common_service_proto_rawDesc = "" +
 	"\x03ips\x18\x02 \x03(\v2+.service.StatsOnlineIpListResponse.IpsEntryR\x03ips\x1a6\n" +
 	"\bIpsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"\xbf\x01\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"\xf4\x02\n" +
 	"\aLatency\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
 	"\x05alive\x18\x02 \x01(\bR\x05alive\x12\x14\n" +
@@ -1591,9 +4381,31 @@ const file_common_service_proto_rawDesc = "" +
 	"\x04link\x18\x04 \x01(\tR\x04link\x12$\n" +
 	"\x0elast_seen_time\x18\x05 \x01(\x03R\flastSeenTime\x12\"\n" +
 	"\rlast_try_time\x18\x06 \x01(\x03R\vlastTryTime\x12\x16\n" +
-	"\x06source\x18\a \x01(\tR\x06source\"$\n" +
+	"\x06source\x18\a \x01(\tR\x06source\x12\x16\n" +
+	"\x06window\x18\b \x01(\rR\x06window\x12\x18\n" +
+	"\asamples\x18\t \x01(\rR\asamples\x12\x1b\n" +
+	"\tmin_delay\x18\n" +
+	" \x01(\x03R\bminDelay\x12\x1b\n" +
+	"\tavg_delay\x18\v \x01(\x03R\bavgDelay\x12\x1b\n" +
+	"\tp95_delay\x18\f \x01(\x03R\bp95Delay\x12\x16\n" +
+	"\x06jitter\x18\r \x01(\x03R\x06jitter\x12\x12\n" +
+	"\x04loss\x18\x0e \x01(\x01R\x04loss\"<\n" +
 	"\x0eLatencyRequest\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\"A\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06window\x18\x02 \x01(\rR\x06window\"S\n" +
+	"\x15LatencyHistoryRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05start\x18\x02 \x01(\x03R\x05start\x12\x10\n" +
+	"\x03end\x18\x03 \x01(\x03R\x03end\"Y\n" +
+	"\rLatencySample\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x14\n" +
+	"\x05alive\x18\x02 \x01(\bR\x05alive\x12\x14\n" +
+	"\x05delay\x18\x03 \x01(\x03R\x05delay\"U\n" +
+	"\rLatencySeries\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x120\n" +
+	"\asamples\x18\x02 \x03(\v2\x16.service.LatencySampleR\asamples\"H\n" +
+	"\x16LatencyHistoryResponse\x12.\n" +
+	"\x06series\x18\x01 \x03(\v2\x16.service.LatencySeriesR\x06series\"A\n" +
 	"\x0fLatencyResponse\x12.\n" +
 	"\tlatencies\x18\x01 \x03(\v2\x10.service.LatencyR\tlatencies\"\xac\x02\n" +
 	"\x14BackendStatsResponse\x12#\n" +
@@ -1616,7 +4428,176 @@ const file_common_service_proto_rawDesc = "" +
 	"\tcpu_usage\x18\x04 \x01(\x01R\bcpuUsage\x128\n" +
 	"\x18incoming_bandwidth_speed\x18\x05 \x01(\x04R\x16incomingBandwidthSpeed\x128\n" +
 	"\x18outgoing_bandwidth_speed\x18\x06 \x01(\x04R\x16outgoingBandwidthSpeed\x12\x16\n" +
-	"\x06uptime\x18\a \x01(\x04R\x06uptime\"\x17\n" +
+	"\x06uptime\x18\a \x01(\x04R\x06uptime\"C\n" +
+	"\x19SystemStatsHistoryRequest\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\x03R\x05start\x12\x10\n" +
+	"\x03end\x18\x02 \x01(\x03R\x03end\"\xfa\x01\n" +
+	"\x11SystemStatsSample\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x1b\n" +
+	"\tcpu_usage\x18\x02 \x01(\x01R\bcpuUsage\x12\x19\n" +
+	"\bmem_used\x18\x03 \x01(\x04R\amemUsed\x12\x1b\n" +
+	"\tmem_total\x18\x04 \x01(\x04R\bmemTotal\x128\n" +
+	"\x18incoming_bandwidth_speed\x18\x05 \x01(\x04R\x16incomingBandwidthSpeed\x128\n" +
+	"\x18outgoing_bandwidth_speed\x18\x06 \x01(\x04R\x16outgoingBandwidthSpeed\"r\n" +
+	"\x1aSystemStatsHistoryResponse\x12\x1e\n" +
+	"\n" +
+	"resolution\x18\x01 \x01(\rR\n" +
+	"resolution\x124\n" +
+	"\asamples\x18\x02 \x03(\v2\x1a.service.SystemStatsSampleR\asamples\"\xc6\x02\n" +
+	"\x0eInterfaceStats\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x19\n" +
+	"\brx_bytes\x18\x02 \x01(\x04R\arxBytes\x12\x19\n" +
+	"\btx_bytes\x18\x03 \x01(\x04R\atxBytes\x12\x1d\n" +
+	"\n" +
+	"rx_packets\x18\x04 \x01(\x04R\trxPackets\x12\x1d\n" +
+	"\n" +
+	"tx_packets\x18\x05 \x01(\x04R\ttxPackets\x12\x1b\n" +
+	"\trx_errors\x18\x06 \x01(\x04R\brxErrors\x12\x1b\n" +
+	"\ttx_errors\x18\a \x01(\x04R\btxErrors\x12\x1d\n" +
+	"\n" +
+	"rx_dropped\x18\b \x01(\x04R\trxDropped\x12\x1d\n" +
+	"\n" +
+	"tx_dropped\x18\t \x01(\x04R\ttxDropped\x12\x19\n" +
+	"\brx_speed\x18\n" +
+	" \x01(\x04R\arxSpeed\x12\x19\n" +
+	"\btx_speed\x18\v \x01(\x04R\atxSpeed\"\xb8\x01\n" +
+	"\tDiskUsage\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x1e\n" +
+	"\n" +
+	"mountpoint\x18\x02 \x01(\tR\n" +
+	"mountpoint\x12\x16\n" +
+	"\x06fstype\x18\x03 \x01(\tR\x06fstype\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\x04R\x05total\x12\x12\n" +
+	"\x04used\x18\x05 \x01(\x04R\x04used\x12\x12\n" +
+	"\x04free\x18\x06 \x01(\x04R\x04free\x12!\n" +
+	"\fused_percent\x18\a \x01(\x01R\vusedPercent\"Q\n" +
+	"\vLoadAverage\x12\x14\n" +
+	"\x05load1\x18\x01 \x01(\x01R\x05load1\x12\x14\n" +
+	"\x05load5\x18\x02 \x01(\x01R\x05load5\x12\x16\n" +
+	"\x06load15\x18\x03 \x01(\x01R\x06load15\"\xb4\x01\n" +
+	"\vSocketStats\x12\x1a\n" +
+	"\bprotocol\x18\x01 \x01(\tR\bprotocol\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x04R\x05total\x128\n" +
+	"\x06states\x18\x03 \x03(\v2 .service.SocketStats.StatesEntryR\x06states\x1a9\n" +
+	"\vStatesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x04R\x05value:\x028\x01\"\xf0\x03\n" +
+	"\x1bDetailedSystemStatsResponse\x12\x1b\n" +
+	"\tmem_total\x18\x01 \x01(\x04R\bmemTotal\x12\x19\n" +
+	"\bmem_used\x18\x02 \x01(\x04R\amemUsed\x12\x1b\n" +
+	"\tcpu_cores\x18\x03 \x01(\x04R\bcpuCores\x12\x1b\n" +
+	"\tcpu_usage\x18\x04 \x01(\x01R\bcpuUsage\x12$\n" +
+	"\x0ecpu_core_usage\x18\x05 \x03(\x01R\fcpuCoreUsage\x12(\n" +
+	"\x04load\x18\x06 \x01(\v2\x14.service.LoadAverageR\x04load\x127\n" +
+	"\n" +
+	"interfaces\x18\a \x03(\v2\x17.service.InterfaceStatsR\n" +
+	"interfaces\x12(\n" +
+	"\x05disks\x18\b \x03(\v2\x12.service.DiskUsageR\x05disks\x122\n" +
+	"\x15open_file_descriptors\x18\t \x01(\x04R\x13openFileDescriptors\x120\n" +
+	"\x14max_file_descriptors\x18\n" +
+	" \x01(\x04R\x12maxFileDescriptors\x12.\n" +
+	"\asockets\x18\v \x03(\v2\x14.service.SocketStatsR\asockets\x12\x16\n" +
+	"\x06uptime\x18\f \x01(\x04R\x06uptime\"\xc2\x01\n" +
+	"\x10IpLimitViolation\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x17\n" +
+	"\amax_ips\x18\x02 \x01(\rR\x06maxIps\x12\x10\n" +
+	"\x03ips\x18\x03 \x03(\tR\x03ips\x12\x1f\n" +
+	"\vblocked_ips\x18\x04 \x03(\tR\n" +
+	"blockedIps\x12.\n" +
+	"\x06action\x18\x05 \x01(\x0e2\x16.service.IpLimitActionR\x06action\x12\x1c\n" +
+	"\ttimestamp\x18\x06 \x01(\x03R\ttimestamp\"F\n" +
+	"\x18IpLimitViolationsRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x14\n" +
+	"\x05since\x18\x02 \x01(\x03R\x05since\"V\n" +
+	"\x19IpLimitViolationsResponse\x129\n" +
+	"\n" +
+	"violations\x18\x01 \x03(\v2\x19.service.IpLimitViolationR\n" +
+	"violations\"y\n" +
+	"\x0ePeerAllocation\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x19\n" +
+	"\bpeer_ips\x18\x02 \x03(\tR\apeerIps\x12\x1c\n" +
+	"\tallocated\x18\x03 \x01(\bR\tallocated\x12\x18\n" +
+	"\ainbound\x18\x04 \x01(\tR\ainbound\"o\n" +
+	"\fPeerConflict\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x17\n" +
+	"\apeer_ip\x18\x02 \x01(\tR\x06peerIp\x12\x16\n" +
+	"\x06detail\x18\x03 \x01(\tR\x06detail\x12\x18\n" +
+	"\ainbound\x18\x04 \x01(\tR\ainbound\"\x89\x01\n" +
+	"\x17PeerAllocationsResponse\x129\n" +
+	"\vallocations\x18\x01 \x03(\v2\x17.service.PeerAllocationR\vallocations\x123\n" +
+	"\tconflicts\x18\x02 \x03(\v2\x15.service.PeerConflictR\tconflicts\"\xe7\x01\n" +
+	"\x13ClientConfigRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
+	"\bendpoint\x18\x02 \x01(\tR\bendpoint\x12\x10\n" +
+	"\x03dns\x18\x03 \x03(\tR\x03dns\x12\x1f\n" +
+	"\vallowed_ips\x18\x04 \x03(\tR\n" +
+	"allowedIps\x12\x1f\n" +
+	"\vprivate_key\x18\x05 \x01(\tR\n" +
+	"privateKey\x12\x15\n" +
+	"\x06qr_png\x18\x06 \x01(\bR\x05qrPng\x12\x19\n" +
+	"\bqr_ascii\x18\a \x01(\bR\aqrAscii\x12\x18\n" +
+	"\ainbound\x18\b \x01(\tR\ainbound\"`\n" +
+	"\x14ClientConfigResponse\x12\x16\n" +
+	"\x06config\x18\x01 \x01(\tR\x06config\x12\x15\n" +
+	"\x06qr_png\x18\x02 \x01(\fR\x05qrPng\x12\x19\n" +
+	"\bqr_ascii\x18\x03 \x01(\tR\aqrAscii\"\xea\x01\n" +
+	"\tPeerEvent\x12*\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x16.service.PeerEventTypeR\x04type\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1a\n" +
+	"\bendpoint\x18\x03 \x01(\tR\bendpoint\x12+\n" +
+	"\x11previous_endpoint\x18\x04 \x01(\tR\x10previousEndpoint\x12\x1c\n" +
+	"\ttimestamp\x18\x05 \x01(\x03R\ttimestamp\x12\x1a\n" +
+	"\bduration\x18\x06 \x01(\x03R\bduration\x12\x18\n" +
+	"\ainbound\x18\a \x01(\tR\ainbound\"\x9b\x01\n" +
+	"\vPeerSession\x12\x18\n" +
+	"\ainbound\x18\x01 \x01(\tR\ainbound\x12\x1c\n" +
+	"\tendpoints\x18\x02 \x03(\tR\tendpoints\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\x03 \x01(\x03R\tstartedAt\x12\x19\n" +
+	"\bended_at\x18\x04 \x01(\x03R\aendedAt\x12\x1a\n" +
+	"\bduration\x18\x05 \x01(\x03R\bduration\"+\n" +
+	"\x13PeerSessionsRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\"^\n" +
+	"\x14PeerSessionsResponse\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x120\n" +
+	"\bsessions\x18\x02 \x03(\v2\x14.service.PeerSessionR\bsessions\"R\n" +
+	"\n" +
+	"PolicyRule\x12\x12\n" +
+	"\x04cidr\x18\x01 \x01(\tR\x04cidr\x12\x1a\n" +
+	"\bprotocol\x18\x02 \x01(\tR\bprotocol\x12\x14\n" +
+	"\x05ports\x18\x03 \x03(\tR\x05ports\"\xbf\x01\n" +
+	"\x06Policy\x12+\n" +
+	"\x12block_peer_to_peer\x18\x01 \x01(\bR\x0fblockPeerToPeer\x124\n" +
+	"\x16block_private_networks\x18\x02 \x01(\bR\x14blockPrivateNetworks\x12)\n" +
+	"\x05allow\x18\x03 \x03(\v2\x13.service.PolicyRuleR\x05allow\x12'\n" +
+	"\x04deny\x18\x04 \x03(\v2\x13.service.PolicyRuleR\x04deny\"R\n" +
+	"\rPolicyRequest\x12\x18\n" +
+	"\ainbound\x18\x01 \x01(\tR\ainbound\x12'\n" +
+	"\x06policy\x18\x02 \x01(\v2\x0f.service.PolicyR\x06policy\"V\n" +
+	"\x0eOutboundConfig\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\x12\x1a\n" +
+	"\bprotocol\x18\x02 \x01(\tR\bprotocol\x12\x16\n" +
+	"\x06config\x18\x03 \x01(\tR\x06config\"F\n" +
+	"\x12AddOutboundRequest\x12\x16\n" +
+	"\x06config\x18\x01 \x01(\tR\x06config\x12\x18\n" +
+	"\areplace\x18\x02 \x01(\bR\areplace\")\n" +
+	"\x15RemoveOutboundRequest\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\"J\n" +
+	"\x11OutboundsResponse\x125\n" +
+	"\toutbounds\x18\x01 \x03(\v2\x17.service.OutboundConfigR\toutbounds\"7\n" +
+	"\vRoutingRule\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\x12\x16\n" +
+	"\x06config\x18\x02 \x01(\tR\x06config\"I\n" +
+	"\x15AddRoutingRuleRequest\x12\x16\n" +
+	"\x06config\x18\x01 \x01(\tR\x06config\x12\x18\n" +
+	"\aprepend\x18\x02 \x01(\bR\aprepend\",\n" +
+	"\x18RemoveRoutingRuleRequest\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\"B\n" +
+	"\x14RoutingRulesResponse\x12*\n" +
+	"\x05rules\x18\x01 \x03(\v2\x14.service.RoutingRuleR\x05rules\"R\n" +
+	"\x15BalancerTargetRequest\x12!\n" +
+	"\fbalancer_tag\x18\x01 \x01(\tR\vbalancerTag\x12\x16\n" +
+	"\x06target\x18\x02 \x01(\tR\x06target\"\x17\n" +
 	"\x05Vmess\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"+\n" +
 	"\x05Vless\x12\x0e\n" +
@@ -1626,11 +4607,16 @@ const file_common_service_proto_rawDesc = "" +
 	"\bpassword\x18\x01 \x01(\tR\bpassword\"A\n" +
 	"\vShadowsocks\x12\x1a\n" +
 	"\bpassword\x18\x01 \x01(\tR\bpassword\x12\x16\n" +
-	"\x06method\x18\x02 \x01(\tR\x06method\"E\n" +
+	"\x06method\x18\x02 \x01(\tR\x06method\"\xe3\x01\n" +
 	"\tWireguard\x12\x1d\n" +
 	"\n" +
 	"public_key\x18\x01 \x01(\tR\tpublicKey\x12\x19\n" +
-	"\bpeer_ips\x18\x02 \x03(\tR\apeerIps\"\x1e\n" +
+	"\bpeer_ips\x18\x02 \x03(\tR\apeerIps\x12\x1f\n" +
+	"\vupload_rate\x18\x03 \x01(\x04R\n" +
+	"uploadRate\x12#\n" +
+	"\rdownload_rate\x18\x04 \x01(\x04R\fdownloadRate\x12#\n" +
+	"\rpreshared_key\x18\x05 \x01(\tR\fpresharedKey\x121\n" +
+	"\x14persistent_keepalive\x18\x06 \x01(\rR\x13persistentKeepalive\"\x1e\n" +
 	"\bHysteria\x12\x12\n" +
 	"\x04auth\x18\x01 \x01(\tR\x04auth\"\x95\x02\n" +
 	"\x05Proxy\x12$\n" +
@@ -1639,11 +4625,20 @@ const file_common_service_proto_rawDesc = "" +
 	"\x06trojan\x18\x03 \x01(\v2\x0f.service.TrojanR\x06trojan\x126\n" +
 	"\vshadowsocks\x18\x04 \x01(\v2\x14.service.ShadowsocksR\vshadowsocks\x120\n" +
 	"\twireguard\x18\x05 \x01(\v2\x12.service.WireguardR\twireguard\x12-\n" +
-	"\bhysteria\x18\x06 \x01(\v2\x11.service.HysteriaR\bhysteria\"b\n" +
+	"\bhysteria\x18\x06 \x01(\v2\x11.service.HysteriaR\bhysteria\"\xfd\x02\n" +
 	"\x04User\x12\x14\n" +
 	"\x05email\x18\x01 \x01(\tR\x05email\x12(\n" +
 	"\aproxies\x18\x02 \x01(\v2\x0e.service.ProxyR\aproxies\x12\x1a\n" +
-	"\binbounds\x18\x03 \x03(\tR\binbounds\",\n" +
+	"\binbounds\x18\x03 \x03(\tR\binbounds\x12\x1d\n" +
+	"\n" +
+	"data_limit\x18\x04 \x01(\x04R\tdataLimit\x12!\n" +
+	"\fused_traffic\x18\x05 \x01(\x04R\vusedTraffic\x12Z\n" +
+	"\x19data_limit_reset_strategy\x18\x06 \x01(\x0e2\x1f.service.DataLimitResetStrategyR\x16dataLimitResetStrategy\x12\"\n" +
+	"\rlast_reset_at\x18\a \x01(\x03R\vlastResetAt\x12\x1b\n" +
+	"\texpire_at\x18\b \x01(\x03R\bexpireAt\x12\x17\n" +
+	"\amax_ips\x18\t \x01(\rR\x06maxIps\x12!\n" +
+	"\foutbound_tag\x18\n" +
+	" \x01(\tR\voutboundTag\",\n" +
 	"\x05Users\x12#\n" +
 	"\x05users\x18\x01 \x03(\v2\r.service.UserR\x05users\"[\n" +
 	"\n" +
@@ -1653,25 +4648,63 @@ const file_common_service_proto_rawDesc = "" +
 	"\x04last\x18\x03 \x01(\bR\x04last*&\n" +
 	"\vBackendType\x12\b\n" +
 	"\x04XRAY\x10\x00\x12\r\n" +
-	"\tWIREGUARD\x10\x01*_\n" +
+	"\tWIREGUARD\x10\x01*.\n" +
+	"\x11EnforcementAction\x12\f\n" +
+	"\bDISABLED\x10\x00\x12\v\n" +
+	"\aENABLED\x10\x01*>\n" +
+	"\x11EnforcementReason\x12\x0e\n" +
+	"\n" +
+	"DATA_LIMIT\x10\x00\x12\v\n" +
+	"\aEXPIRED\x10\x01\x12\f\n" +
+	"\bIP_LIMIT\x10\x02*_\n" +
 	"\bStatType\x12\r\n" +
 	"\tOutbounds\x10\x00\x12\f\n" +
 	"\bOutbound\x10\x01\x12\f\n" +
 	"\bInbounds\x10\x02\x12\v\n" +
 	"\aInbound\x10\x03\x12\r\n" +
 	"\tUsersStat\x10\x04\x12\f\n" +
-	"\bUserStat\x10\x052\xa3\x06\n" +
+	"\bUserStat\x10\x05*0\n" +
+	"\rIpLimitAction\x12\r\n" +
+	"\tBLOCK_IPS\x10\x00\x12\x10\n" +
+	"\fDISABLE_USER\x10\x01*<\n" +
+	"\rPeerEventType\x12\r\n" +
+	"\tCONNECTED\x10\x00\x12\n" +
+	"\n" +
+	"\x06ROAMED\x10\x01\x12\x10\n" +
+	"\fDISCONNECTED\x10\x02*N\n" +
+	"\x16DataLimitResetStrategy\x12\f\n" +
+	"\bNO_RESET\x10\x00\x12\a\n" +
+	"\x03DAY\x10\x01\x12\b\n" +
+	"\x04WEEK\x10\x02\x12\t\n" +
+	"\x05MONTH\x10\x03\x12\b\n" +
+	"\x04YEAR\x10\x042\xdc\x0f\n" +
 	"\vNodeService\x126\n" +
 	"\x05Start\x12\x10.service.Backend\x1a\x19.service.BaseInfoResponse\"\x00\x12(\n" +
 	"\x04Stop\x12\x0e.service.Empty\x1a\x0e.service.Empty\"\x00\x12:\n" +
 	"\vGetBaseInfo\x12\x0e.service.Empty\x1a\x19.service.BaseInfoResponse\"\x00\x12+\n" +
 	"\aGetLogs\x12\x0e.service.Empty\x1a\f.service.Log\"\x000\x01\x12@\n" +
-	"\x0eGetSystemStats\x12\x0e.service.Empty\x1a\x1c.service.SystemStatsResponse\"\x00\x12B\n" +
+	"\x0eGetSystemStats\x12\x0e.service.Empty\x1a\x1c.service.SystemStatsResponse\"\x00\x12b\n" +
+	"\x15GetSystemStatsHistory\x12\".service.SystemStatsHistoryRequest\x1a#.service.SystemStatsHistoryResponse\"\x00\x12P\n" +
+	"\x16GetDetailedSystemStats\x12\x0e.service.Empty\x1a$.service.DetailedSystemStatsResponse\"\x00\x12B\n" +
 	"\x0fGetBackendStats\x12\x0e.service.Empty\x1a\x1d.service.BackendStatsResponse\"\x00\x129\n" +
 	"\bGetStats\x12\x14.service.StatRequest\x1a\x15.service.StatResponse\"\x00\x12J\n" +
-	"\x13GetOutboundsLatency\x12\x17.service.LatencyRequest\x1a\x18.service.LatencyResponse\"\x00\x12I\n" +
+	"\x13GetOutboundsLatency\x12\x17.service.LatencyRequest\x1a\x18.service.LatencyResponse\"\x00\x12_\n" +
+	"\x1aGetOutboundsLatencyHistory\x12\x1e.service.LatencyHistoryRequest\x1a\x1f.service.LatencyHistoryResponse\"\x00\x12I\n" +
 	"\x12GetUserOnlineStats\x12\x14.service.StatRequest\x1a\x1b.service.OnlineStatResponse\"\x00\x12V\n" +
-	"\x18GetUserOnlineIpListStats\x12\x14.service.StatRequest\x1a\".service.StatsOnlineIpListResponse\"\x00\x12-\n" +
+	"\x18GetUserOnlineIpListStats\x12\x14.service.StatRequest\x1a\".service.StatsOnlineIpListResponse\"\x00\x12_\n" +
+	"\x14GetIpLimitViolations\x12!.service.IpLimitViolationsRequest\x1a\".service.IpLimitViolationsResponse\"\x00\x12H\n" +
+	"\x12GetPeerAllocations\x12\x0e.service.Empty\x1a .service.PeerAllocationsResponse\"\x00\x12P\n" +
+	"\x0fGetClientConfig\x12\x1c.service.ClientConfigRequest\x1a\x1d.service.ClientConfigResponse\"\x00\x128\n" +
+	"\fUpdatePolicy\x12\x16.service.PolicyRequest\x1a\x0e.service.Empty\"\x00\x12<\n" +
+	"\vAddOutbound\x12\x1b.service.AddOutboundRequest\x1a\x0e.service.Empty\"\x00\x12B\n" +
+	"\x0eRemoveOutbound\x12\x1e.service.RemoveOutboundRequest\x1a\x0e.service.Empty\"\x00\x12=\n" +
+	"\rListOutbounds\x12\x0e.service.Empty\x1a\x1a.service.OutboundsResponse\"\x00\x12B\n" +
+	"\x0eAddRoutingRule\x12\x1e.service.AddRoutingRuleRequest\x1a\x0e.service.Empty\"\x00\x12H\n" +
+	"\x11RemoveRoutingRule\x12!.service.RemoveRoutingRuleRequest\x1a\x0e.service.Empty\"\x00\x12C\n" +
+	"\x10ListRoutingRules\x12\x0e.service.Empty\x1a\x1d.service.RoutingRulesResponse\"\x00\x12J\n" +
+	"\x16OverrideBalancerTarget\x12\x1e.service.BalancerTargetRequest\x1a\x0e.service.Empty\"\x00\x127\n" +
+	"\rGetPeerEvents\x12\x0e.service.Empty\x1a\x12.service.PeerEvent\"\x000\x01\x12P\n" +
+	"\x0fGetPeerSessions\x12\x1c.service.PeerSessionsRequest\x1a\x1d.service.PeerSessionsResponse\"\x00\x12-\n" +
 	"\bSyncUser\x12\r.service.User\x1a\x0e.service.Empty\"\x00(\x01\x12-\n" +
 	"\tSyncUsers\x12\x0e.service.Users\x1a\x0e.service.Empty\"\x00\x12;\n" +
 	"\x10SyncUsersChunked\x12\x13.service.UsersChunk\x1a\x0e.service.Empty\"\x00(\x01B#Z!github.com/pasarguard/node/commonb\x06proto3"
@@ -1688,84 +4721,182 @@ func file_common_service_proto_rawDescGZIP() []byte {
 	return file_common_service_proto_rawDescData
 }
 
-var file_common_service_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_common_service_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
+var file_common_service_proto_enumTypes = make([]protoimpl.EnumInfo, 7)
+var file_common_service_proto_msgTypes = make([]protoimpl.MessageInfo, 63)
 var file_common_service_proto_goTypes = []any{
-	(BackendType)(0),                  // 0: service.BackendType
-	(StatType)(0),                     // 1: service.StatType
-	(*Empty)(nil),                     // 2: service.Empty
-	(*BaseInfoResponse)(nil),          // 3: service.BaseInfoResponse
-	(*Backend)(nil),                   // 4: service.Backend
-	(*Log)(nil),                       // 5: service.Log
-	(*Stat)(nil),                      // 6: service.Stat
-	(*StatResponse)(nil),              // 7: service.StatResponse
-	(*StatRequest)(nil),               // 8: service.StatRequest
-	(*OnlineStatResponse)(nil),        // 9: service.OnlineStatResponse
-	(*StatsOnlineIpListResponse)(nil), // 10: service.StatsOnlineIpListResponse
-	(*Latency)(nil),                   // 11: service.Latency
-	(*LatencyRequest)(nil),            // 12: service.LatencyRequest
-	(*LatencyResponse)(nil),           // 13: service.LatencyResponse
-	(*BackendStatsResponse)(nil),      // 14: service.BackendStatsResponse
-	(*SystemStatsResponse)(nil),       // 15: service.SystemStatsResponse
-	(*Vmess)(nil),                     // 16: service.Vmess
-	(*Vless)(nil),                     // 17: service.Vless
-	(*Trojan)(nil),                    // 18: service.Trojan
-	(*Shadowsocks)(nil),               // 19: service.Shadowsocks
-	(*Wireguard)(nil),                 // 20: service.Wireguard
-	(*Hysteria)(nil),                  // 21: service.Hysteria
-	(*Proxy)(nil),                     // 22: service.Proxy
-	(*User)(nil),                      // 23: service.User
-	(*Users)(nil),                     // 24: service.Users
-	(*UsersChunk)(nil),                // 25: service.UsersChunk
-	nil,                               // 26: service.StatsOnlineIpListResponse.IpsEntry
+	(BackendType)(0),                    // 0: service.BackendType
+	(EnforcementAction)(0),              // 1: service.EnforcementAction
+	(EnforcementReason)(0),              // 2: service.EnforcementReason
+	(StatType)(0),                       // 3: service.StatType
+	(IpLimitAction)(0),                  // 4: service.IpLimitAction
+	(PeerEventType)(0),                  // 5: service.PeerEventType
+	(DataLimitResetStrategy)(0),         // 6: service.DataLimitResetStrategy
+	(*Empty)(nil),                       // 7: service.Empty
+	(*BaseInfoResponse)(nil),            // 8: service.BaseInfoResponse
+	(*Backend)(nil),                     // 9: service.Backend
+	(*Log)(nil),                         // 10: service.Log
+	(*Stat)(nil),                        // 11: service.Stat
+	(*StatResponse)(nil),                // 12: service.StatResponse
+	(*EnforcementEvent)(nil),            // 13: service.EnforcementEvent
+	(*StatRequest)(nil),                 // 14: service.StatRequest
+	(*OnlineStatResponse)(nil),          // 15: service.OnlineStatResponse
+	(*StatsOnlineIpListResponse)(nil),   // 16: service.StatsOnlineIpListResponse
+	(*Latency)(nil),                     // 17: service.Latency
+	(*LatencyRequest)(nil),              // 18: service.LatencyRequest
+	(*LatencyHistoryRequest)(nil),       // 19: service.LatencyHistoryRequest
+	(*LatencySample)(nil),               // 20: service.LatencySample
+	(*LatencySeries)(nil),               // 21: service.LatencySeries
+	(*LatencyHistoryResponse)(nil),      // 22: service.LatencyHistoryResponse
+	(*LatencyResponse)(nil),             // 23: service.LatencyResponse
+	(*BackendStatsResponse)(nil),        // 24: service.BackendStatsResponse
+	(*SystemStatsResponse)(nil),         // 25: service.SystemStatsResponse
+	(*SystemStatsHistoryRequest)(nil),   // 26: service.SystemStatsHistoryRequest
+	(*SystemStatsSample)(nil),           // 27: service.SystemStatsSample
+	(*SystemStatsHistoryResponse)(nil),  // 28: service.SystemStatsHistoryResponse
+	(*InterfaceStats)(nil),              // 29: service.InterfaceStats
+	(*DiskUsage)(nil),                   // 30: service.DiskUsage
+	(*LoadAverage)(nil),                 // 31: service.LoadAverage
+	(*SocketStats)(nil),                 // 32: service.SocketStats
+	(*DetailedSystemStatsResponse)(nil), // 33: service.DetailedSystemStatsResponse
+	(*IpLimitViolation)(nil),            // 34: service.IpLimitViolation
+	(*IpLimitViolationsRequest)(nil),    // 35: service.IpLimitViolationsRequest
+	(*IpLimitViolationsResponse)(nil),   // 36: service.IpLimitViolationsResponse
+	(*PeerAllocation)(nil),              // 37: service.PeerAllocation
+	(*PeerConflict)(nil),                // 38: service.PeerConflict
+	(*PeerAllocationsResponse)(nil),     // 39: service.PeerAllocationsResponse
+	(*ClientConfigRequest)(nil),         // 40: service.ClientConfigRequest
+	(*ClientConfigResponse)(nil),        // 41: service.ClientConfigResponse
+	(*PeerEvent)(nil),                   // 42: service.PeerEvent
+	(*PeerSession)(nil),                 // 43: service.PeerSession
+	(*PeerSessionsRequest)(nil),         // 44: service.PeerSessionsRequest
+	(*PeerSessionsResponse)(nil),        // 45: service.PeerSessionsResponse
+	(*PolicyRule)(nil),                  // 46: service.PolicyRule
+	(*Policy)(nil),                      // 47: service.Policy
+	(*PolicyRequest)(nil),               // 48: service.PolicyRequest
+	(*OutboundConfig)(nil),              // 49: service.OutboundConfig
+	(*AddOutboundRequest)(nil),          // 50: service.AddOutboundRequest
+	(*RemoveOutboundRequest)(nil),       // 51: service.RemoveOutboundRequest
+	(*OutboundsResponse)(nil),           // 52: service.OutboundsResponse
+	(*RoutingRule)(nil),                 // 53: service.RoutingRule
+	(*AddRoutingRuleRequest)(nil),       // 54: service.AddRoutingRuleRequest
+	(*RemoveRoutingRuleRequest)(nil),    // 55: service.RemoveRoutingRuleRequest
+	(*RoutingRulesResponse)(nil),        // 56: service.RoutingRulesResponse
+	(*BalancerTargetRequest)(nil),       // 57: service.BalancerTargetRequest
+	(*Vmess)(nil),                       // 58: service.Vmess
+	(*Vless)(nil),                       // 59: service.Vless
+	(*Trojan)(nil),                      // 60: service.Trojan
+	(*Shadowsocks)(nil),                 // 61: service.Shadowsocks
+	(*Wireguard)(nil),                   // 62: service.Wireguard
+	(*Hysteria)(nil),                    // 63: service.Hysteria
+	(*Proxy)(nil),                       // 64: service.Proxy
+	(*User)(nil),                        // 65: service.User
+	(*Users)(nil),                       // 66: service.Users
+	(*UsersChunk)(nil),                  // 67: service.UsersChunk
+	nil,                                 // 68: service.StatsOnlineIpListResponse.IpsEntry
+	nil,                                 // 69: service.SocketStats.StatesEntry
 }
 var file_common_service_proto_depIdxs = []int32{
 	0,  // 0: service.Backend.type:type_name -> service.BackendType
-	23, // 1: service.Backend.users:type_name -> service.User
-	6,  // 2: service.StatResponse.stats:type_name -> service.Stat
-	1,  // 3: service.StatRequest.type:type_name -> service.StatType
-	26, // 4: service.StatsOnlineIpListResponse.ips:type_name -> service.StatsOnlineIpListResponse.IpsEntry
-	11, // 5: service.LatencyResponse.latencies:type_name -> service.Latency
-	16, // 6: service.Proxy.vmess:type_name -> service.Vmess
-	17, // 7: service.Proxy.vless:type_name -> service.Vless
-	18, // 8: service.Proxy.trojan:type_name -> service.Trojan
-	19, // 9: service.Proxy.shadowsocks:type_name -> service.Shadowsocks
-	20, // 10: service.Proxy.wireguard:type_name -> service.Wireguard
-	21, // 11: service.Proxy.hysteria:type_name -> service.Hysteria
-	22, // 12: service.User.proxies:type_name -> service.Proxy
-	23, // 13: service.Users.users:type_name -> service.User
-	23, // 14: service.UsersChunk.users:type_name -> service.User
-	4,  // 15: service.NodeService.Start:input_type -> service.Backend
-	2,  // 16: service.NodeService.Stop:input_type -> service.Empty
-	2,  // 17: service.NodeService.GetBaseInfo:input_type -> service.Empty
-	2,  // 18: service.NodeService.GetLogs:input_type -> service.Empty
-	2,  // 19: service.NodeService.GetSystemStats:input_type -> service.Empty
-	2,  // 20: service.NodeService.GetBackendStats:input_type -> service.Empty
-	8,  // 21: service.NodeService.GetStats:input_type -> service.StatRequest
-	12, // 22: service.NodeService.GetOutboundsLatency:input_type -> service.LatencyRequest
-	8,  // 23: service.NodeService.GetUserOnlineStats:input_type -> service.StatRequest
-	8,  // 24: service.NodeService.GetUserOnlineIpListStats:input_type -> service.StatRequest
-	23, // 25: service.NodeService.SyncUser:input_type -> service.User
-	24, // 26: service.NodeService.SyncUsers:input_type -> service.Users
-	25, // 27: service.NodeService.SyncUsersChunked:input_type -> service.UsersChunk
-	3,  // 28: service.NodeService.Start:output_type -> service.BaseInfoResponse
-	2,  // 29: service.NodeService.Stop:output_type -> service.Empty
-	3,  // 30: service.NodeService.GetBaseInfo:output_type -> service.BaseInfoResponse
-	5,  // 31: service.NodeService.GetLogs:output_type -> service.Log
-	15, // 32: service.NodeService.GetSystemStats:output_type -> service.SystemStatsResponse
-	14, // 33: service.NodeService.GetBackendStats:output_type -> service.BackendStatsResponse
-	7,  // 34: service.NodeService.GetStats:output_type -> service.StatResponse
-	13, // 35: service.NodeService.GetOutboundsLatency:output_type -> service.LatencyResponse
-	9,  // 36: service.NodeService.GetUserOnlineStats:output_type -> service.OnlineStatResponse
-	10, // 37: service.NodeService.GetUserOnlineIpListStats:output_type -> service.StatsOnlineIpListResponse
-	2,  // 38: service.NodeService.SyncUser:output_type -> service.Empty
-	2,  // 39: service.NodeService.SyncUsers:output_type -> service.Empty
-	2,  // 40: service.NodeService.SyncUsersChunked:output_type -> service.Empty
-	28, // [28:41] is the sub-list for method output_type
-	15, // [15:28] is the sub-list for method input_type
-	15, // [15:15] is the sub-list for extension type_name
-	15, // [15:15] is the sub-list for extension extendee
-	0,  // [0:15] is the sub-list for field type_name
+	65, // 1: service.Backend.users:type_name -> service.User
+	11, // 2: service.StatResponse.stats:type_name -> service.Stat
+	13, // 3: service.StatResponse.events:type_name -> service.EnforcementEvent
+	1,  // 4: service.EnforcementEvent.action:type_name -> service.EnforcementAction
+	2,  // 5: service.EnforcementEvent.reason:type_name -> service.EnforcementReason
+	3,  // 6: service.StatRequest.type:type_name -> service.StatType
+	68, // 7: service.StatsOnlineIpListResponse.ips:type_name -> service.StatsOnlineIpListResponse.IpsEntry
+	20, // 8: service.LatencySeries.samples:type_name -> service.LatencySample
+	21, // 9: service.LatencyHistoryResponse.series:type_name -> service.LatencySeries
+	17, // 10: service.LatencyResponse.latencies:type_name -> service.Latency
+	27, // 11: service.SystemStatsHistoryResponse.samples:type_name -> service.SystemStatsSample
+	69, // 12: service.SocketStats.states:type_name -> service.SocketStats.StatesEntry
+	31, // 13: service.DetailedSystemStatsResponse.load:type_name -> service.LoadAverage
+	29, // 14: service.DetailedSystemStatsResponse.interfaces:type_name -> service.InterfaceStats
+	30, // 15: service.DetailedSystemStatsResponse.disks:type_name -> service.DiskUsage
+	32, // 16: service.DetailedSystemStatsResponse.sockets:type_name -> service.SocketStats
+	4,  // 17: service.IpLimitViolation.action:type_name -> service.IpLimitAction
+	34, // 18: service.IpLimitViolationsResponse.violations:type_name -> service.IpLimitViolation
+	37, // 19: service.PeerAllocationsResponse.allocations:type_name -> service.PeerAllocation
+	38, // 20: service.PeerAllocationsResponse.conflicts:type_name -> service.PeerConflict
+	5,  // 21: service.PeerEvent.type:type_name -> service.PeerEventType
+	43, // 22: service.PeerSessionsResponse.sessions:type_name -> service.PeerSession
+	46, // 23: service.Policy.allow:type_name -> service.PolicyRule
+	46, // 24: service.Policy.deny:type_name -> service.PolicyRule
+	47, // 25: service.PolicyRequest.policy:type_name -> service.Policy
+	49, // 26: service.OutboundsResponse.outbounds:type_name -> service.OutboundConfig
+	53, // 27: service.RoutingRulesResponse.rules:type_name -> service.RoutingRule
+	58, // 28: service.Proxy.vmess:type_name -> service.Vmess
+	59, // 29: service.Proxy.vless:type_name -> service.Vless
+	60, // 30: service.Proxy.trojan:type_name -> service.Trojan
+	61, // 31: service.Proxy.shadowsocks:type_name -> service.Shadowsocks
+	62, // 32: service.Proxy.wireguard:type_name -> service.Wireguard
+	63, // 33: service.Proxy.hysteria:type_name -> service.Hysteria
+	64, // 34: service.User.proxies:type_name -> service.Proxy
+	6,  // 35: service.User.data_limit_reset_strategy:type_name -> service.DataLimitResetStrategy
+	65, // 36: service.Users.users:type_name -> service.User
+	65, // 37: service.UsersChunk.users:type_name -> service.User
+	9,  // 38: service.NodeService.Start:input_type -> service.Backend
+	7,  // 39: service.NodeService.Stop:input_type -> service.Empty
+	7,  // 40: service.NodeService.GetBaseInfo:input_type -> service.Empty
+	7,  // 41: service.NodeService.GetLogs:input_type -> service.Empty
+	7,  // 42: service.NodeService.GetSystemStats:input_type -> service.Empty
+	26, // 43: service.NodeService.GetSystemStatsHistory:input_type -> service.SystemStatsHistoryRequest
+	7,  // 44: service.NodeService.GetDetailedSystemStats:input_type -> service.Empty
+	7,  // 45: service.NodeService.GetBackendStats:input_type -> service.Empty
+	14, // 46: service.NodeService.GetStats:input_type -> service.StatRequest
+	18, // 47: service.NodeService.GetOutboundsLatency:input_type -> service.LatencyRequest
+	19, // 48: service.NodeService.GetOutboundsLatencyHistory:input_type -> service.LatencyHistoryRequest
+	14, // 49: service.NodeService.GetUserOnlineStats:input_type -> service.StatRequest
+	14, // 50: service.NodeService.GetUserOnlineIpListStats:input_type -> service.StatRequest
+	35, // 51: service.NodeService.GetIpLimitViolations:input_type -> service.IpLimitViolationsRequest
+	7,  // 52: service.NodeService.GetPeerAllocations:input_type -> service.Empty
+	40, // 53: service.NodeService.GetClientConfig:input_type -> service.ClientConfigRequest
+	48, // 54: service.NodeService.UpdatePolicy:input_type -> service.PolicyRequest
+	50, // 55: service.NodeService.AddOutbound:input_type -> service.AddOutboundRequest
+	51, // 56: service.NodeService.RemoveOutbound:input_type -> service.RemoveOutboundRequest
+	7,  // 57: service.NodeService.ListOutbounds:input_type -> service.Empty
+	54, // 58: service.NodeService.AddRoutingRule:input_type -> service.AddRoutingRuleRequest
+	55, // 59: service.NodeService.RemoveRoutingRule:input_type -> service.RemoveRoutingRuleRequest
+	7,  // 60: service.NodeService.ListRoutingRules:input_type -> service.Empty
+	57, // 61: service.NodeService.OverrideBalancerTarget:input_type -> service.BalancerTargetRequest
+	7,  // 62: service.NodeService.GetPeerEvents:input_type -> service.Empty
+	44, // 63: service.NodeService.GetPeerSessions:input_type -> service.PeerSessionsRequest
+	65, // 64: service.NodeService.SyncUser:input_type -> service.User
+	66, // 65: service.NodeService.SyncUsers:input_type -> service.Users
+	67, // 66: service.NodeService.SyncUsersChunked:input_type -> service.UsersChunk
+	8,  // 67: service.NodeService.Start:output_type -> service.BaseInfoResponse
+	7,  // 68: service.NodeService.Stop:output_type -> service.Empty
+	8,  // 69: service.NodeService.GetBaseInfo:output_type -> service.BaseInfoResponse
+	10, // 70: service.NodeService.GetLogs:output_type -> service.Log
+	25, // 71: service.NodeService.GetSystemStats:output_type -> service.SystemStatsResponse
+	28, // 72: service.NodeService.GetSystemStatsHistory:output_type -> service.SystemStatsHistoryResponse
+	33, // 73: service.NodeService.GetDetailedSystemStats:output_type -> service.DetailedSystemStatsResponse
+	24, // 74: service.NodeService.GetBackendStats:output_type -> service.BackendStatsResponse
+	12, // 75: service.NodeService.GetStats:output_type -> service.StatResponse
+	23, // 76: service.NodeService.GetOutboundsLatency:output_type -> service.LatencyResponse
+	22, // 77: service.NodeService.GetOutboundsLatencyHistory:output_type -> service.LatencyHistoryResponse
+	15, // 78: service.NodeService.GetUserOnlineStats:output_type -> service.OnlineStatResponse
+	16, // 79: service.NodeService.GetUserOnlineIpListStats:output_type -> service.StatsOnlineIpListResponse
+	36, // 80: service.NodeService.GetIpLimitViolations:output_type -> service.IpLimitViolationsResponse
+	39, // 81: service.NodeService.GetPeerAllocations:output_type -> service.PeerAllocationsResponse
+	41, // 82: service.NodeService.GetClientConfig:output_type -> service.ClientConfigResponse
+	7,  // 83: service.NodeService.UpdatePolicy:output_type -> service.Empty
+	7,  // 84: service.NodeService.AddOutbound:output_type -> service.Empty
+	7,  // 85: service.NodeService.RemoveOutbound:output_type -> service.Empty
+	52, // 86: service.NodeService.ListOutbounds:output_type -> service.OutboundsResponse
+	7,  // 87: service.NodeService.AddRoutingRule:output_type -> service.Empty
+	7,  // 88: service.NodeService.RemoveRoutingRule:output_type -> service.Empty
+	56, // 89: service.NodeService.ListRoutingRules:output_type -> service.RoutingRulesResponse
+	7,  // 90: service.NodeService.OverrideBalancerTarget:output_type -> service.Empty
+	42, // 91: service.NodeService.GetPeerEvents:output_type -> service.PeerEvent
+	45, // 92: service.NodeService.GetPeerSessions:output_type -> service.PeerSessionsResponse
+	7,  // 93: service.NodeService.SyncUser:output_type -> service.Empty
+	7,  // 94: service.NodeService.SyncUsers:output_type -> service.Empty
+	7,  // 95: service.NodeService.SyncUsersChunked:output_type -> service.Empty
+	67, // [67:96] is the sub-list for method output_type
+	38, // [38:67] is the sub-list for method input_type
+	38, // [38:38] is the sub-list for extension type_name
+	38, // [38:38] is the sub-list for extension extendee
+	0,  // [0:38] is the sub-list for field type_name
 }
 
 func init() { file_common_service_proto_init() }
@@ -1778,8 +4909,8 @@ func file_common_service_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_common_service_proto_rawDesc), len(file_common_service_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   25,
+			NumEnums:      7,
+			NumMessages:   63,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,19 +19,35 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	NodeService_Start_FullMethodName                    = "/service.NodeService/Start"
-	NodeService_Stop_FullMethodName                     = "/service.NodeService/Stop"
-	NodeService_GetBaseInfo_FullMethodName              = "/service.NodeService/GetBaseInfo"
-	NodeService_GetLogs_FullMethodName                  = "/service.NodeService/GetLogs"
-	NodeService_GetSystemStats_FullMethodName           = "/service.NodeService/GetSystemStats"
-	NodeService_GetBackendStats_FullMethodName          = "/service.NodeService/GetBackendStats"
-	NodeService_GetStats_FullMethodName                 = "/service.NodeService/GetStats"
-	NodeService_GetOutboundsLatency_FullMethodName      = "/service.NodeService/GetOutboundsLatency"
-	NodeService_GetUserOnlineStats_FullMethodName       = "/service.NodeService/GetUserOnlineStats"
-	NodeService_GetUserOnlineIpListStats_FullMethodName = "/service.NodeService/GetUserOnlineIpListStats"
-	NodeService_SyncUser_FullMethodName                 = "/service.NodeService/SyncUser"
-	NodeService_SyncUsers_FullMethodName                = "/service.NodeService/SyncUsers"
-	NodeService_SyncUsersChunked_FullMethodName         = "/service.NodeService/SyncUsersChunked"
+	NodeService_Start_FullMethodName                      = "/service.NodeService/Start"
+	NodeService_Stop_FullMethodName                       = "/service.NodeService/Stop"
+	NodeService_GetBaseInfo_FullMethodName                = "/service.NodeService/GetBaseInfo"
+	NodeService_GetLogs_FullMethodName                    = "/service.NodeService/GetLogs"
+	NodeService_GetSystemStats_FullMethodName             = "/service.NodeService/GetSystemStats"
+	NodeService_GetSystemStatsHistory_FullMethodName      = "/service.NodeService/GetSystemStatsHistory"
+	NodeService_GetDetailedSystemStats_FullMethodName     = "/service.NodeService/GetDetailedSystemStats"
+	NodeService_GetBackendStats_FullMethodName            = "/service.NodeService/GetBackendStats"
+	NodeService_GetStats_FullMethodName                   = "/service.NodeService/GetStats"
+	NodeService_GetOutboundsLatency_FullMethodName        = "/service.NodeService/GetOutboundsLatency"
+	NodeService_GetOutboundsLatencyHistory_FullMethodName = "/service.NodeService/GetOutboundsLatencyHistory"
+	NodeService_GetUserOnlineStats_FullMethodName         = "/service.NodeService/GetUserOnlineStats"
+	NodeService_GetUserOnlineIpListStats_FullMethodName   = "/service.NodeService/GetUserOnlineIpListStats"
+	NodeService_GetIpLimitViolations_FullMethodName       = "/service.NodeService/GetIpLimitViolations"
+	NodeService_GetPeerAllocations_FullMethodName         = "/service.NodeService/GetPeerAllocations"
+	NodeService_GetClientConfig_FullMethodName            = "/service.NodeService/GetClientConfig"
+	NodeService_UpdatePolicy_FullMethodName               = "/service.NodeService/UpdatePolicy"
+	NodeService_AddOutbound_FullMethodName                = "/service.NodeService/AddOutbound"
+	NodeService_RemoveOutbound_FullMethodName             = "/service.NodeService/RemoveOutbound"
+	NodeService_ListOutbounds_FullMethodName              = "/service.NodeService/ListOutbounds"
+	NodeService_AddRoutingRule_FullMethodName             = "/service.NodeService/AddRoutingRule"
+	NodeService_RemoveRoutingRule_FullMethodName          = "/service.NodeService/RemoveRoutingRule"
+	NodeService_ListRoutingRules_FullMethodName           = "/service.NodeService/ListRoutingRules"
+	NodeService_OverrideBalancerTarget_FullMethodName     = "/service.NodeService/OverrideBalancerTarget"
+	NodeService_GetPeerEvents_FullMethodName              = "/service.NodeService/GetPeerEvents"
+	NodeService_GetPeerSessions_FullMethodName            = "/service.NodeService/GetPeerSessions"
+	NodeService_SyncUser_FullMethodName                   = "/service.NodeService/SyncUser"
+	NodeService_SyncUsers_FullMethodName                  = "/service.NodeService/SyncUsers"
+	NodeService_SyncUsersChunked_FullMethodName           = "/service.NodeService/SyncUsersChunked"
 )
 
 // NodeServiceClient is the client API for NodeService service.
@@ -45,11 +61,27 @@ type NodeServiceClient interface {
 	GetBaseInfo(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BaseInfoResponse, error)
 	GetLogs(ctx context.Context, in *Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Log], error)
 	GetSystemStats(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SystemStatsResponse, error)
+	GetSystemStatsHistory(ctx context.Context, in *SystemStatsHistoryRequest, opts ...grpc.CallOption) (*SystemStatsHistoryResponse, error)
+	GetDetailedSystemStats(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*DetailedSystemStatsResponse, error)
 	GetBackendStats(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BackendStatsResponse, error)
 	GetStats(ctx context.Context, in *StatRequest, opts ...grpc.CallOption) (*StatResponse, error)
 	GetOutboundsLatency(ctx context.Context, in *LatencyRequest, opts ...grpc.CallOption) (*LatencyResponse, error)
+	GetOutboundsLatencyHistory(ctx context.Context, in *LatencyHistoryRequest, opts ...grpc.CallOption) (*LatencyHistoryResponse, error)
 	GetUserOnlineStats(ctx context.Context, in *StatRequest, opts ...grpc.CallOption) (*OnlineStatResponse, error)
 	GetUserOnlineIpListStats(ctx context.Context, in *StatRequest, opts ...grpc.CallOption) (*StatsOnlineIpListResponse, error)
+	GetIpLimitViolations(ctx context.Context, in *IpLimitViolationsRequest, opts ...grpc.CallOption) (*IpLimitViolationsResponse, error)
+	GetPeerAllocations(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PeerAllocationsResponse, error)
+	GetClientConfig(ctx context.Context, in *ClientConfigRequest, opts ...grpc.CallOption) (*ClientConfigResponse, error)
+	UpdatePolicy(ctx context.Context, in *PolicyRequest, opts ...grpc.CallOption) (*Empty, error)
+	AddOutbound(ctx context.Context, in *AddOutboundRequest, opts ...grpc.CallOption) (*Empty, error)
+	RemoveOutbound(ctx context.Context, in *RemoveOutboundRequest, opts ...grpc.CallOption) (*Empty, error)
+	ListOutbounds(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*OutboundsResponse, error)
+	AddRoutingRule(ctx context.Context, in *AddRoutingRuleRequest, opts ...grpc.CallOption) (*Empty, error)
+	RemoveRoutingRule(ctx context.Context, in *RemoveRoutingRuleRequest, opts ...grpc.CallOption) (*Empty, error)
+	ListRoutingRules(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RoutingRulesResponse, error)
+	OverrideBalancerTarget(ctx context.Context, in *BalancerTargetRequest, opts ...grpc.CallOption) (*Empty, error)
+	GetPeerEvents(ctx context.Context, in *Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PeerEvent], error)
+	GetPeerSessions(ctx context.Context, in *PeerSessionsRequest, opts ...grpc.CallOption) (*PeerSessionsResponse, error)
 	SyncUser(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[User, Empty], error)
 	SyncUsers(ctx context.Context, in *Users, opts ...grpc.CallOption) (*Empty, error)
 	SyncUsersChunked(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UsersChunk, Empty], error)
@@ -122,6 +154,26 @@ func (c *nodeServiceClient) GetSystemStats(ctx context.Context, in *Empty, opts
 	return out, nil
 }
 
+func (c *nodeServiceClient) GetSystemStatsHistory(ctx context.Context, in *SystemStatsHistoryRequest, opts ...grpc.CallOption) (*SystemStatsHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SystemStatsHistoryResponse)
+	err := c.cc.Invoke(ctx, NodeService_GetSystemStatsHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) GetDetailedSystemStats(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*DetailedSystemStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DetailedSystemStatsResponse)
+	err := c.cc.Invoke(ctx, NodeService_GetDetailedSystemStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *nodeServiceClient) GetBackendStats(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BackendStatsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(BackendStatsResponse)
@@ -152,6 +204,16 @@ func (c *nodeServiceClient) GetOutboundsLatency(ctx context.Context, in *Latency
 	return out, nil
 }
 
+func (c *nodeServiceClient) GetOutboundsLatencyHistory(ctx context.Context, in *LatencyHistoryRequest, opts ...grpc.CallOption) (*LatencyHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LatencyHistoryResponse)
+	err := c.cc.Invoke(ctx, NodeService_GetOutboundsLatencyHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *nodeServiceClient) GetUserOnlineStats(ctx context.Context, in *StatRequest, opts ...grpc.CallOption) (*OnlineStatResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(OnlineStatResponse)
@@ -172,9 +234,148 @@ func (c *nodeServiceClient) GetUserOnlineIpListStats(ctx context.Context, in *St
 	return out, nil
 }
 
+func (c *nodeServiceClient) GetIpLimitViolations(ctx context.Context, in *IpLimitViolationsRequest, opts ...grpc.CallOption) (*IpLimitViolationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IpLimitViolationsResponse)
+	err := c.cc.Invoke(ctx, NodeService_GetIpLimitViolations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) GetPeerAllocations(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PeerAllocationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PeerAllocationsResponse)
+	err := c.cc.Invoke(ctx, NodeService_GetPeerAllocations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) GetClientConfig(ctx context.Context, in *ClientConfigRequest, opts ...grpc.CallOption) (*ClientConfigResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClientConfigResponse)
+	err := c.cc.Invoke(ctx, NodeService_GetClientConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) UpdatePolicy(ctx context.Context, in *PolicyRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, NodeService_UpdatePolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) AddOutbound(ctx context.Context, in *AddOutboundRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, NodeService_AddOutbound_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) RemoveOutbound(ctx context.Context, in *RemoveOutboundRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, NodeService_RemoveOutbound_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) ListOutbounds(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*OutboundsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OutboundsResponse)
+	err := c.cc.Invoke(ctx, NodeService_ListOutbounds_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) AddRoutingRule(ctx context.Context, in *AddRoutingRuleRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, NodeService_AddRoutingRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) RemoveRoutingRule(ctx context.Context, in *RemoveRoutingRuleRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, NodeService_RemoveRoutingRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) ListRoutingRules(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RoutingRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoutingRulesResponse)
+	err := c.cc.Invoke(ctx, NodeService_ListRoutingRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) OverrideBalancerTarget(ctx context.Context, in *BalancerTargetRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, NodeService_OverrideBalancerTarget_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) GetPeerEvents(ctx context.Context, in *Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PeerEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NodeService_ServiceDesc.Streams[1], NodeService_GetPeerEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[Empty, PeerEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NodeService_GetPeerEventsClient = grpc.ServerStreamingClient[PeerEvent]
+
+func (c *nodeServiceClient) GetPeerSessions(ctx context.Context, in *PeerSessionsRequest, opts ...grpc.CallOption) (*PeerSessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PeerSessionsResponse)
+	err := c.cc.Invoke(ctx, NodeService_GetPeerSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *nodeServiceClient) SyncUser(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[User, Empty], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &NodeService_ServiceDesc.Streams[1], NodeService_SyncUser_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &NodeService_ServiceDesc.Streams[2], NodeService_SyncUser_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -197,7 +398,7 @@ func (c *nodeServiceClient) SyncUsers(ctx context.Context, in *Users, opts ...gr
 
 func (c *nodeServiceClient) SyncUsersChunked(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UsersChunk, Empty], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &NodeService_ServiceDesc.Streams[2], NodeService_SyncUsersChunked_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &NodeService_ServiceDesc.Streams[3], NodeService_SyncUsersChunked_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -219,11 +420,27 @@ type NodeServiceServer interface {
 	GetBaseInfo(context.Context, *Empty) (*BaseInfoResponse, error)
 	GetLogs(*Empty, grpc.ServerStreamingServer[Log]) error
 	GetSystemStats(context.Context, *Empty) (*SystemStatsResponse, error)
+	GetSystemStatsHistory(context.Context, *SystemStatsHistoryRequest) (*SystemStatsHistoryResponse, error)
+	GetDetailedSystemStats(context.Context, *Empty) (*DetailedSystemStatsResponse, error)
 	GetBackendStats(context.Context, *Empty) (*BackendStatsResponse, error)
 	GetStats(context.Context, *StatRequest) (*StatResponse, error)
 	GetOutboundsLatency(context.Context, *LatencyRequest) (*LatencyResponse, error)
+	GetOutboundsLatencyHistory(context.Context, *LatencyHistoryRequest) (*LatencyHistoryResponse, error)
 	GetUserOnlineStats(context.Context, *StatRequest) (*OnlineStatResponse, error)
 	GetUserOnlineIpListStats(context.Context, *StatRequest) (*StatsOnlineIpListResponse, error)
+	GetIpLimitViolations(context.Context, *IpLimitViolationsRequest) (*IpLimitViolationsResponse, error)
+	GetPeerAllocations(context.Context, *Empty) (*PeerAllocationsResponse, error)
+	GetClientConfig(context.Context, *ClientConfigRequest) (*ClientConfigResponse, error)
+	UpdatePolicy(context.Context, *PolicyRequest) (*Empty, error)
+	AddOutbound(context.Context, *AddOutboundRequest) (*Empty, error)
+	RemoveOutbound(context.Context, *RemoveOutboundRequest) (*Empty, error)
+	ListOutbounds(context.Context, *Empty) (*OutboundsResponse, error)
+	AddRoutingRule(context.Context, *AddRoutingRuleRequest) (*Empty, error)
+	RemoveRoutingRule(context.Context, *RemoveRoutingRuleRequest) (*Empty, error)
+	ListRoutingRules(context.Context, *Empty) (*RoutingRulesResponse, error)
+	OverrideBalancerTarget(context.Context, *BalancerTargetRequest) (*Empty, error)
+	GetPeerEvents(*Empty, grpc.ServerStreamingServer[PeerEvent]) error
+	GetPeerSessions(context.Context, *PeerSessionsRequest) (*PeerSessionsResponse, error)
 	SyncUser(grpc.ClientStreamingServer[User, Empty]) error
 	SyncUsers(context.Context, *Users) (*Empty, error)
 	SyncUsersChunked(grpc.ClientStreamingServer[UsersChunk, Empty]) error
@@ -252,6 +469,12 @@ func (UnimplementedNodeServiceServer) GetLogs(*Empty, grpc.ServerStreamingServer
 func (UnimplementedNodeServiceServer) GetSystemStats(context.Context, *Empty) (*SystemStatsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetSystemStats not implemented")
 }
+func (UnimplementedNodeServiceServer) GetSystemStatsHistory(context.Context, *SystemStatsHistoryRequest) (*SystemStatsHistoryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetSystemStatsHistory not implemented")
+}
+func (UnimplementedNodeServiceServer) GetDetailedSystemStats(context.Context, *Empty) (*DetailedSystemStatsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetDetailedSystemStats not implemented")
+}
 func (UnimplementedNodeServiceServer) GetBackendStats(context.Context, *Empty) (*BackendStatsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetBackendStats not implemented")
 }
@@ -261,12 +484,54 @@ func (UnimplementedNodeServiceServer) GetStats(context.Context, *StatRequest) (*
 func (UnimplementedNodeServiceServer) GetOutboundsLatency(context.Context, *LatencyRequest) (*LatencyResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetOutboundsLatency not implemented")
 }
+func (UnimplementedNodeServiceServer) GetOutboundsLatencyHistory(context.Context, *LatencyHistoryRequest) (*LatencyHistoryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetOutboundsLatencyHistory not implemented")
+}
 func (UnimplementedNodeServiceServer) GetUserOnlineStats(context.Context, *StatRequest) (*OnlineStatResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetUserOnlineStats not implemented")
 }
 func (UnimplementedNodeServiceServer) GetUserOnlineIpListStats(context.Context, *StatRequest) (*StatsOnlineIpListResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetUserOnlineIpListStats not implemented")
 }
+func (UnimplementedNodeServiceServer) GetIpLimitViolations(context.Context, *IpLimitViolationsRequest) (*IpLimitViolationsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetIpLimitViolations not implemented")
+}
+func (UnimplementedNodeServiceServer) GetPeerAllocations(context.Context, *Empty) (*PeerAllocationsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetPeerAllocations not implemented")
+}
+func (UnimplementedNodeServiceServer) GetClientConfig(context.Context, *ClientConfigRequest) (*ClientConfigResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetClientConfig not implemented")
+}
+func (UnimplementedNodeServiceServer) UpdatePolicy(context.Context, *PolicyRequest) (*Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdatePolicy not implemented")
+}
+func (UnimplementedNodeServiceServer) AddOutbound(context.Context, *AddOutboundRequest) (*Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method AddOutbound not implemented")
+}
+func (UnimplementedNodeServiceServer) RemoveOutbound(context.Context, *RemoveOutboundRequest) (*Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method RemoveOutbound not implemented")
+}
+func (UnimplementedNodeServiceServer) ListOutbounds(context.Context, *Empty) (*OutboundsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListOutbounds not implemented")
+}
+func (UnimplementedNodeServiceServer) AddRoutingRule(context.Context, *AddRoutingRuleRequest) (*Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method AddRoutingRule not implemented")
+}
+func (UnimplementedNodeServiceServer) RemoveRoutingRule(context.Context, *RemoveRoutingRuleRequest) (*Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method RemoveRoutingRule not implemented")
+}
+func (UnimplementedNodeServiceServer) ListRoutingRules(context.Context, *Empty) (*RoutingRulesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListRoutingRules not implemented")
+}
+func (UnimplementedNodeServiceServer) OverrideBalancerTarget(context.Context, *BalancerTargetRequest) (*Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method OverrideBalancerTarget not implemented")
+}
+func (UnimplementedNodeServiceServer) GetPeerEvents(*Empty, grpc.ServerStreamingServer[PeerEvent]) error {
+	return status.Error(codes.Unimplemented, "method GetPeerEvents not implemented")
+}
+func (UnimplementedNodeServiceServer) GetPeerSessions(context.Context, *PeerSessionsRequest) (*PeerSessionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetPeerSessions not implemented")
+}
 func (UnimplementedNodeServiceServer) SyncUser(grpc.ClientStreamingServer[User, Empty]) error {
 	return status.Error(codes.Unimplemented, "method SyncUser not implemented")
 }
@@ -380,6 +645,42 @@ func _NodeService_GetSystemStats_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NodeService_GetSystemStatsHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SystemStatsHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).GetSystemStatsHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_GetSystemStatsHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).GetSystemStatsHistory(ctx, req.(*SystemStatsHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_GetDetailedSystemStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).GetDetailedSystemStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_GetDetailedSystemStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).GetDetailedSystemStats(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _NodeService_GetBackendStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Empty)
 	if err := dec(in); err != nil {
@@ -434,6 +735,24 @@ func _NodeService_GetOutboundsLatency_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NodeService_GetOutboundsLatencyHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LatencyHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).GetOutboundsLatencyHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_GetOutboundsLatencyHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).GetOutboundsLatencyHistory(ctx, req.(*LatencyHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _NodeService_GetUserOnlineStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(StatRequest)
 	if err := dec(in); err != nil {
@@ -470,6 +789,233 @@ func _NodeService_GetUserOnlineIpListStats_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NodeService_GetIpLimitViolations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IpLimitViolationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).GetIpLimitViolations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_GetIpLimitViolations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).GetIpLimitViolations(ctx, req.(*IpLimitViolationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_GetPeerAllocations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).GetPeerAllocations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_GetPeerAllocations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).GetPeerAllocations(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_GetClientConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).GetClientConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_GetClientConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).GetClientConfig(ctx, req.(*ClientConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_UpdatePolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).UpdatePolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_UpdatePolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).UpdatePolicy(ctx, req.(*PolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_AddOutbound_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddOutboundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).AddOutbound(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_AddOutbound_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).AddOutbound(ctx, req.(*AddOutboundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_RemoveOutbound_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveOutboundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).RemoveOutbound(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_RemoveOutbound_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).RemoveOutbound(ctx, req.(*RemoveOutboundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_ListOutbounds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).ListOutbounds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_ListOutbounds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).ListOutbounds(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_AddRoutingRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddRoutingRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).AddRoutingRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_AddRoutingRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).AddRoutingRule(ctx, req.(*AddRoutingRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_RemoveRoutingRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveRoutingRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).RemoveRoutingRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_RemoveRoutingRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).RemoveRoutingRule(ctx, req.(*RemoveRoutingRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_ListRoutingRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).ListRoutingRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_ListRoutingRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).ListRoutingRules(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_OverrideBalancerTarget_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BalancerTargetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).OverrideBalancerTarget(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_OverrideBalancerTarget_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).OverrideBalancerTarget(ctx, req.(*BalancerTargetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_GetPeerEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NodeServiceServer).GetPeerEvents(m, &grpc.GenericServerStream[Empty, PeerEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NodeService_GetPeerEventsServer = grpc.ServerStreamingServer[PeerEvent]
+
+func _NodeService_GetPeerSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PeerSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).GetPeerSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_GetPeerSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).GetPeerSessions(ctx, req.(*PeerSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _NodeService_SyncUser_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(NodeServiceServer).SyncUser(&grpc.GenericServerStream[User, Empty]{ServerStream: stream})
 }
@@ -525,6 +1071,14 @@ var NodeService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetSystemStats",
 			Handler:    _NodeService_GetSystemStats_Handler,
 		},
+		{
+			MethodName: "GetSystemStatsHistory",
+			Handler:    _NodeService_GetSystemStatsHistory_Handler,
+		},
+		{
+			MethodName: "GetDetailedSystemStats",
+			Handler:    _NodeService_GetDetailedSystemStats_Handler,
+		},
 		{
 			MethodName: "GetBackendStats",
 			Handler:    _NodeService_GetBackendStats_Handler,
@@ -537,6 +1091,10 @@ var NodeService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetOutboundsLatency",
 			Handler:    _NodeService_GetOutboundsLatency_Handler,
 		},
+		{
+			MethodName: "GetOutboundsLatencyHistory",
+			Handler:    _NodeService_GetOutboundsLatencyHistory_Handler,
+		},
 		{
 			MethodName: "GetUserOnlineStats",
 			Handler:    _NodeService_GetUserOnlineStats_Handler,
@@ -545,6 +1103,54 @@ var NodeService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetUserOnlineIpListStats",
 			Handler:    _NodeService_GetUserOnlineIpListStats_Handler,
 		},
+		{
+			MethodName: "GetIpLimitViolations",
+			Handler:    _NodeService_GetIpLimitViolations_Handler,
+		},
+		{
+			MethodName: "GetPeerAllocations",
+			Handler:    _NodeService_GetPeerAllocations_Handler,
+		},
+		{
+			MethodName: "GetClientConfig",
+			Handler:    _NodeService_GetClientConfig_Handler,
+		},
+		{
+			MethodName: "UpdatePolicy",
+			Handler:    _NodeService_UpdatePolicy_Handler,
+		},
+		{
+			MethodName: "AddOutbound",
+			Handler:    _NodeService_AddOutbound_Handler,
+		},
+		{
+			MethodName: "RemoveOutbound",
+			Handler:    _NodeService_RemoveOutbound_Handler,
+		},
+		{
+			MethodName: "ListOutbounds",
+			Handler:    _NodeService_ListOutbounds_Handler,
+		},
+		{
+			MethodName: "AddRoutingRule",
+			Handler:    _NodeService_AddRoutingRule_Handler,
+		},
+		{
+			MethodName: "RemoveRoutingRule",
+			Handler:    _NodeService_RemoveRoutingRule_Handler,
+		},
+		{
+			MethodName: "ListRoutingRules",
+			Handler:    _NodeService_ListRoutingRules_Handler,
+		},
+		{
+			MethodName: "OverrideBalancerTarget",
+			Handler:    _NodeService_OverrideBalancerTarget_Handler,
+		},
+		{
+			MethodName: "GetPeerSessions",
+			Handler:    _NodeService_GetPeerSessions_Handler,
+		},
 		{
 			MethodName: "SyncUsers",
 			Handler:    _NodeService_SyncUsers_Handler,
@@ -556,6 +1162,11 @@ var NodeService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _NodeService_GetLogs_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "GetPeerEvents",
+			Handler:       _NodeService_GetPeerEvents_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "SyncUser",
 			Handler:       _NodeService_SyncUser_Handler,
@@ -11,20 +11,27 @@ import (
 )
 
 type Config struct {
-	ServicePort                 int
-	NodeHost                    string
-	XrayExecutablePath          string
-	XrayAssetsPath              string
-	SslCertFile                 string
-	SslKeyFile                  string
-	ApiKey                      uuid.UUID
-	ServiceProtocol             string
-	Debug                       bool
-	GeneratedConfigPath         string
-	LogBufferSize               int
-	StartupLogTailSize          int
-	StatsUpdateIntervalSeconds  int
-	StatsCleanupIntervalSeconds int
+	ServicePort                   int
+	NodeHost                      string
+	XrayExecutablePath            string
+	XrayAssetsPath                string
+	SslCertFile                   string
+	SslKeyFile                    string
+	ApiKey                        uuid.UUID
+	ServiceProtocol               string
+	Debug                         bool
+	GeneratedConfigPath           string
+	LogBufferSize                 int
+	StartupLogTailSize            int
+	StatsUpdateIntervalSeconds    int
+	StatsCleanupIntervalSeconds   int
+	SystemStatsIntervalSeconds    int
+	EnforcementIntervalSeconds    int
+	IpLimitAction                 string
+	IpLimitPenaltySeconds         int
+	XrayLatencyTestURL            string
+	XrayLatencyTimeoutSeconds     int
+	LatencyHistoryIntervalSeconds int
 }
 
 func Load() (*Config, error) {
@@ -34,18 +41,25 @@ func Load() (*Config, error) {
 	}
 
 	cfg := &Config{
-		ServicePort:                 GetEnvAsInt("SERVICE_PORT", 62050),
-		XrayExecutablePath:          GetEnv("XRAY_EXECUTABLE_PATH", "/usr/local/bin/xray"),
-		XrayAssetsPath:              GetEnv("XRAY_ASSETS_PATH", "/usr/local/share/xray"),
-		SslCertFile:                 GetEnv("SSL_CERT_FILE", "/var/lib/pg-node/certs/ssl_cert.pem"),
-		SslKeyFile:                  GetEnv("SSL_KEY_FILE", "/var/lib/pg-node/certs/ssl_key.pem"),
-		GeneratedConfigPath:         GetEnv("GENERATED_CONFIG_PATH", "/var/lib/pg-node/generated/"),
-		ServiceProtocol:             GetEnv("SERVICE_PROTOCOL", "grpc"),
-		Debug:                       GetEnvAsBool("DEBUG", false),
-		LogBufferSize:               GetEnvAsInt("LOG_BUFFER_SIZE", 10000),
-		StartupLogTailSize:          GetEnvAsInt("STARTUP_LOG_TAIL_SIZE", 200),
-		StatsUpdateIntervalSeconds:  GetEnvAsInt("STATS_UPDATE_INTERVAL_SECONDS", 10),
-		StatsCleanupIntervalSeconds: GetEnvAsInt("STATS_CLEANUP_INTERVAL_SECONDS", 300),
+		ServicePort:                   GetEnvAsInt("SERVICE_PORT", 62050),
+		XrayExecutablePath:            GetEnv("XRAY_EXECUTABLE_PATH", "/usr/local/bin/xray"),
+		XrayAssetsPath:                GetEnv("XRAY_ASSETS_PATH", "/usr/local/share/xray"),
+		SslCertFile:                   GetEnv("SSL_CERT_FILE", "/var/lib/pg-node/certs/ssl_cert.pem"),
+		SslKeyFile:                    GetEnv("SSL_KEY_FILE", "/var/lib/pg-node/certs/ssl_key.pem"),
+		GeneratedConfigPath:           GetEnv("GENERATED_CONFIG_PATH", "/var/lib/pg-node/generated/"),
+		ServiceProtocol:               GetEnv("SERVICE_PROTOCOL", "grpc"),
+		Debug:                         GetEnvAsBool("DEBUG", false),
+		LogBufferSize:                 GetEnvAsInt("LOG_BUFFER_SIZE", 10000),
+		StartupLogTailSize:            GetEnvAsInt("STARTUP_LOG_TAIL_SIZE", 200),
+		StatsUpdateIntervalSeconds:    GetEnvAsInt("STATS_UPDATE_INTERVAL_SECONDS", 10),
+		StatsCleanupIntervalSeconds:   GetEnvAsInt("STATS_CLEANUP_INTERVAL_SECONDS", 300),
+		SystemStatsIntervalSeconds:    GetEnvAsInt("SYSTEM_STATS_INTERVAL_SECONDS", 1),
+		EnforcementIntervalSeconds:    GetEnvAsInt("ENFORCEMENT_INTERVAL_SECONDS", 10),
+		IpLimitAction:                 GetEnv("IP_LIMIT_ACTION", "block"),
+		IpLimitPenaltySeconds:         GetEnvAsInt("IP_LIMIT_PENALTY_SECONDS", 300),
+		XrayLatencyTestURL:            GetEnv("XRAY_LATENCY_TEST_URL", "https://www.gstatic.com/generate_204"),
+		XrayLatencyTimeoutSeconds:     GetEnvAsInt("XRAY_LATENCY_TIMEOUT_SECONDS", 5),
+		LatencyHistoryIntervalSeconds: GetEnvAsInt("LATENCY_HISTORY_INTERVAL_SECONDS", 60),
 	}
 
 	if cfg.LogBufferSize <= 0 {
@@ -53,6 +67,36 @@ func Load() (*Config, error) {
 		cfg.LogBufferSize = 1
 	}
 
+	if cfg.SystemStatsIntervalSeconds <= 0 {
+		log.Printf("[Warning] SYSTEM_STATS_INTERVAL_SECONDS must be greater than 0, got %d. Falling back to 1.", cfg.SystemStatsIntervalSeconds)
+		cfg.SystemStatsIntervalSeconds = 1
+	}
+
+	if cfg.EnforcementIntervalSeconds <= 0 {
+		log.Printf("[Warning] ENFORCEMENT_INTERVAL_SECONDS must be greater than 0, got %d. Falling back to 10.", cfg.EnforcementIntervalSeconds)
+		cfg.EnforcementIntervalSeconds = 10
+	}
+
+	if cfg.IpLimitAction != "block" && cfg.IpLimitAction != "disable" {
+		log.Printf("[Warning] IP_LIMIT_ACTION must be 'block' or 'disable', got %q. Falling back to block.", cfg.IpLimitAction)
+		cfg.IpLimitAction = "block"
+	}
+
+	if cfg.IpLimitPenaltySeconds <= 0 {
+		log.Printf("[Warning] IP_LIMIT_PENALTY_SECONDS must be greater than 0, got %d. Falling back to 300.", cfg.IpLimitPenaltySeconds)
+		cfg.IpLimitPenaltySeconds = 300
+	}
+
+	if cfg.XrayLatencyTimeoutSeconds <= 0 {
+		log.Printf("[Warning] XRAY_LATENCY_TIMEOUT_SECONDS must be greater than 0, got %d. Falling back to 5.", cfg.XrayLatencyTimeoutSeconds)
+		cfg.XrayLatencyTimeoutSeconds = 5
+	}
+
+	if cfg.LatencyHistoryIntervalSeconds <= 0 {
+		log.Printf("[Warning] LATENCY_HISTORY_INTERVAL_SECONDS must be greater than 0, got %d. Falling back to 60.", cfg.LatencyHistoryIntervalSeconds)
+		cfg.LatencyHistoryIntervalSeconds = 60
+	}
+
 	cfg.ApiKey, err = GetEnvAsUUID("API_KEY")
 	if err != nil {
 		log.Printf("[Error] Failed to load API Key, error: %v", err)
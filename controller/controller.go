@@ -8,12 +8,17 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/pasarguard/node/backend"
 	"github.com/pasarguard/node/backend/wireguard"
 	"github.com/pasarguard/node/backend/xray"
 	"github.com/pasarguard/node/common"
 	"github.com/pasarguard/node/config"
+	"github.com/pasarguard/node/pkg/enforcer"
+	"github.com/pasarguard/node/pkg/latency"
 	"github.com/pasarguard/node/pkg/netutil"
 	"github.com/pasarguard/node/pkg/sysstats"
 )
@@ -32,8 +37,14 @@ type Controller struct {
 	clientIP    string
 	lastRequest time.Time
 	stats       *common.SystemStatsResponse
+	snapshot    *sysstats.Snapshot
+	history     *sysstats.History
+	latencies   *latency.History
+	enforcer    *enforcer.Enforcer
 	cancelFunc  context.CancelFunc
-	mu          sync.RWMutex
+	// stopRecording cancels the stats and latency recorders; nil while they are not running.
+	stopRecording context.CancelFunc
+	mu            sync.RWMutex
 }
 
 func New(cfg *config.Config) *Controller {
@@ -42,10 +53,25 @@ func New(cfg *config.Config) *Controller {
 		cfg:        cfg,
 		apiPort:    netutil.FindFreePort(),
 		metricPort: netutil.FindFreePort(),
+		history:    sysstats.NewHistory(sysstats.DefaultHistoryTiers),
+		latencies:  latency.NewHistory(latency.DefaultRetention),
+		enforcer:   enforcer.New(enforcerOptions(cfg)),
 		cancelFunc: cancel,
 	}
 }
 
+func enforcerOptions(cfg *config.Config) enforcer.Options {
+	opts := enforcer.Options{
+		Interval:       time.Duration(cfg.EnforcementIntervalSeconds) * time.Second,
+		IPLimitAction:  common.IpLimitAction_BLOCK_IPS,
+		IPLimitPenalty: time.Duration(cfg.IpLimitPenaltySeconds) * time.Second,
+	}
+	if cfg.IpLimitAction == "disable" {
+		opts.IPLimitAction = common.IpLimitAction_DISABLE_USER
+	}
+	return opts
+}
+
 func (c *Controller) ApiKey() uuid.UUID {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -60,7 +86,12 @@ func (c *Controller) Connect(ip string, keepAlive uint64) {
 
 	ctx, cancel := context.WithCancel(context.Background())
 	c.cancelFunc = cancel
-	go c.recordSystemStats(ctx)
+	if c.stopRecording == nil {
+		recordCtx, stopRecording := context.WithCancel(context.Background())
+		c.stopRecording = stopRecording
+		go c.recordSystemStats(recordCtx)
+		go c.recordLatency(recordCtx)
+	}
 	if keepAlive > 0 {
 		go c.keepAliveTracker(ctx, time.Duration(keepAlive)*time.Second)
 	}
@@ -68,6 +99,7 @@ func (c *Controller) Connect(ip string, keepAlive uint64) {
 
 func (c *Controller) Disconnect() {
 	c.cancelFunc()
+	c.enforcer.Stop()
 
 	c.mu.Lock()
 	backend := c.backend
@@ -82,6 +114,10 @@ func (c *Controller) Disconnect() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if c.stopRecording != nil {
+		c.stopRecording()
+		c.stopRecording = nil
+	}
 	c.backend = nil
 	c.apiPort = netutil.FindFreePort()
 	c.metricPort = netutil.FindFreePort()
@@ -104,6 +140,15 @@ func (c *Controller) StartBackend(ctx context.Context, backend *common.Backend)
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	var users []*common.User
+	// Users over a local limit start out disabled; the enforcer takes over once the backend runs.
+	if err := c.enforcer.Apply(backend.GetUsers(), true, func(effective []*common.User) error {
+		users = effective
+		return nil
+	}); err != nil {
+		return err
+	}
+
 	switch backend.GetType() {
 	case common.BackendType_XRAY:
 		config, err := xray.NewConfig(backend.GetConfig(), backend.GetExcludeInbounds())
@@ -114,7 +159,7 @@ func (c *Controller) StartBackend(ctx context.Context, backend *common.Backend)
 		newBackend, err := xray.New(
 			ctx,
 			config,
-			backend.GetUsers(),
+			users,
 			c.apiPort,
 			c.metricPort,
 			c.cfg,
@@ -125,11 +170,19 @@ func (c *Controller) StartBackend(ctx context.Context, backend *common.Backend)
 		c.backend = newBackend
 
 	case common.BackendType_WIREGUARD:
-		config, err := wireguard.NewConfig(backend.GetConfig())
+		configs, err := wireguard.NewConfigs(backend.GetConfig())
 		if err != nil {
 			return err
 		}
-		newBackend, err := wireguard.New(c.cfg, config, backend.GetUsers())
+		if len(configs) == 1 {
+			newBackend, err := wireguard.New(c.cfg, configs[0], users)
+			if err != nil {
+				return err
+			}
+			c.backend = newBackend
+			break
+		}
+		newBackend, err := wireguard.NewGroup(c.cfg, configs, users)
 		if err != nil {
 			return err
 		}
@@ -138,6 +191,8 @@ func (c *Controller) StartBackend(ctx context.Context, backend *common.Backend)
 		return errors.New("invalid backend type")
 	}
 
+	c.enforcer.Start(c.backend)
+
 	return nil
 }
 
@@ -147,6 +202,62 @@ func (c *Controller) Backend() backend.Backend {
 	return c.backend
 }
 
+// PeerManager returns the running backend when it manages peers, and an
+// Unimplemented error otherwise.
+func (c *Controller) PeerManager() (backend.PeerManager, error) {
+	if manager, ok := c.Backend().(backend.PeerManager); ok {
+		return manager, nil
+	}
+	return nil, status.Errorf(codes.Unimplemented, "peers are only available on the wireguard backend")
+}
+
+// RoutingManager returns the running backend when its outbounds and routing
+// can change at runtime, and an Unimplemented error otherwise.
+func (c *Controller) RoutingManager() (backend.RoutingManager, error) {
+	if manager, ok := c.Backend().(backend.RoutingManager); ok {
+		return manager, nil
+	}
+	return nil, status.Errorf(codes.Unimplemented, "outbounds and routing are only available on the xray backend")
+}
+
+// SyncBackendUser pushes one user to the backend, applying local limits first.
+func (c *Controller) SyncBackendUser(ctx context.Context, user *common.User) error {
+	return c.enforcer.Apply([]*common.User{user}, false, func(users []*common.User) error {
+		return c.Backend().SyncUser(ctx, users[0])
+	})
+}
+
+// SyncBackendUsers replaces every backend user, applying local limits first.
+func (c *Controller) SyncBackendUsers(ctx context.Context, users []*common.User) error {
+	return c.enforcer.Apply(users, true, func(users []*common.User) error {
+		return c.Backend().SyncUsers(ctx, users)
+	})
+}
+
+// UpdateBackendUsers updates a subset of backend users, applying local limits first.
+func (c *Controller) UpdateBackendUsers(ctx context.Context, users []*common.User) error {
+	return c.enforcer.Apply(users, false, func(users []*common.User) error {
+		return c.Backend().UpdateUsers(ctx, users)
+	})
+}
+
+// UpdateBackendUsersAndRestart is UpdateBackendUsers for batches large enough to warrant a restart.
+func (c *Controller) UpdateBackendUsersAndRestart(ctx context.Context, users []*common.User) error {
+	return c.enforcer.Apply(users, false, func(users []*common.User) error {
+		return c.Backend().UpdateUsersAndRestart(ctx, users)
+	})
+}
+
+// Stats returns backend stats and attaches pending enforcement events to user stats pulls.
+func (c *Controller) Stats(ctx context.Context, request *common.StatRequest) (*common.StatResponse, error) {
+	return c.enforcer.Stats(ctx, c.Backend(), request)
+}
+
+// IpLimitViolations returns the recorded max_ips violations matching request.
+func (c *Controller) IpLimitViolations(request *common.IpLimitViolationsRequest) *common.IpLimitViolationsResponse {
+	return &common.IpLimitViolationsResponse{Violations: c.enforcer.Violations(request.GetEmail(), request.GetSince())}
+}
+
 func (c *Controller) keepAliveTracker(ctx context.Context, keepAlive time.Duration) {
 	ticker := time.NewTicker(5 * time.Second)
 	defer ticker.Stop()
@@ -168,24 +279,34 @@ func (c *Controller) keepAliveTracker(ctx context.Context, keepAlive time.Durati
 }
 
 func (c *Controller) recordSystemStats(ctx context.Context) {
-	interval := 1500 * time.Millisecond
+	interval := time.Second
+	if c.cfg.SystemStatsIntervalSeconds > 0 {
+		interval = time.Duration(c.cfg.SystemStatsIntervalSeconds) * time.Second
+	}
 
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
+	collector := sysstats.NewCollector(sysstats.DefaultSources())
+	if err := collector.Prime(); err != nil {
+		log.Printf("Failed to get system stats: %v", err)
+	}
+
 	collect := func() {
-		stats, err := sysstats.GetSystemStats()
+		snapshot, err := collector.Collect()
 		if err != nil {
 			log.Printf("Failed to get system stats: %v", err)
 			return
 		}
+		stats := snapshot.Summary
 
 		c.mu.Lock()
 		c.stats = stats
+		c.snapshot = snapshot
 		c.mu.Unlock()
-	}
 
-	collect()
+		c.history.Add(time.Now(), stats)
+	}
 
 	for {
 		select {
@@ -197,6 +318,40 @@ func (c *Controller) recordSystemStats(ctx context.Context) {
 	}
 }
 
+// recordLatency reads outbound latency on an interval so the history keeps
+// filling between panel requests. Reads fail while no backend is running.
+func (c *Controller) recordLatency(ctx context.Context) {
+	interval := time.Duration(c.cfg.LatencyHistoryIntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.mu.RLock()
+			backendSnapshot := c.backend
+			c.mu.RUnlock()
+			if backendSnapshot == nil || !backendSnapshot.Started() {
+				continue
+			}
+
+			probeCtx, cancel := context.WithTimeout(ctx, interval)
+			response, err := backendSnapshot.GetOutboundsLatency(probeCtx, &common.LatencyRequest{})
+			cancel()
+			if err != nil {
+				continue
+			}
+			c.latencies.Record(time.Now(), response.GetLatencies())
+		}
+	}
+}
+
 func (c *Controller) SystemStats(ctx context.Context) *common.SystemStatsResponse {
 	c.mu.RLock()
 	statsSnapshot := c.stats
@@ -231,6 +386,65 @@ func (c *Controller) SystemStats(ctx context.Context) *common.SystemStatsRespons
 	return response
 }
 
+func (c *Controller) DetailedSystemStats(ctx context.Context) *common.DetailedSystemStatsResponse {
+	c.mu.RLock()
+	snapshot := c.snapshot
+	backendSnapshot := c.backend
+	c.mu.RUnlock()
+
+	paths := []string{c.cfg.GeneratedConfigPath}
+	if backendSnapshot != nil {
+		paths = append(paths, backendSnapshot.LogFiles()...)
+	}
+	host := sysstats.GetHostStats(paths)
+
+	response := &common.DetailedSystemStatsResponse{
+		CpuCoreUsage:        []float64{},
+		Interfaces:          []*common.InterfaceStats{},
+		Load:                host.Load,
+		Disks:               host.Disks,
+		OpenFileDescriptors: host.OpenFileDescriptors,
+		MaxFileDescriptors:  host.MaxFileDescriptors,
+		Sockets:             host.Sockets,
+	}
+
+	if snapshot != nil {
+		summary := snapshot.Summary
+		response.MemTotal = summary.GetMemTotal()
+		response.MemUsed = summary.GetMemUsed()
+		response.CpuCores = summary.GetCpuCores()
+		response.CpuUsage = summary.GetCpuUsage()
+		response.CpuCoreUsage = append(response.CpuCoreUsage, snapshot.CpuCoreUsage...)
+		for _, iface := range snapshot.Interfaces {
+			response.Interfaces = append(response.Interfaces, proto.Clone(iface).(*common.InterfaceStats))
+		}
+	}
+
+	if backendSnapshot == nil {
+		return response
+	}
+
+	backendStats, err := backendSnapshot.GetSysStats(ctx)
+	if err != nil {
+		log.Printf("Failed to get backend uptime for detailed system stats: %v", err)
+		return response
+	}
+
+	response.Uptime = uint64(backendStats.GetUptime())
+	return response
+}
+
+func (c *Controller) SystemStatsHistory(request *common.SystemStatsHistoryRequest) *common.SystemStatsHistoryResponse {
+	var start, end time.Time
+	if request.GetStart() > 0 {
+		start = time.Unix(request.GetStart(), 0)
+	}
+	if request.GetEnd() > 0 {
+		end = time.Unix(request.GetEnd(), 0)
+	}
+	return c.history.Query(start, end)
+}
+
 func (c *Controller) BaseInfoResponse() *common.BaseInfoResponse {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -244,6 +458,7 @@ func (c *Controller) BaseInfoResponse() *common.BaseInfoResponse {
 	if c.backend != nil {
 		response.Started = c.backend.Started()
 		response.CoreVersion = c.backend.Version()
+		response.Obfuscation = c.backend.Obfuscation()
 	}
 
 	return response
@@ -258,5 +473,23 @@ func (c *Controller) OutboundsLatency(ctx context.Context, request *common.Laten
 		return &common.LatencyResponse{Latencies: []*common.Latency{}}, nil
 	}
 
-	return backendSnapshot.GetOutboundsLatency(ctx, request)
+	response, err := backendSnapshot.GetOutboundsLatency(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only recordLatency records samples, so the history does not depend on how often the panel polls.
+	c.latencies.Summarize(time.Now(), time.Duration(request.GetWindow())*time.Second, response.GetLatencies())
+	return response, nil
+}
+
+func (c *Controller) OutboundsLatencyHistory(request *common.LatencyHistoryRequest) *common.LatencyHistoryResponse {
+	var start, end time.Time
+	if request.GetStart() > 0 {
+		start = time.Unix(request.GetStart(), 0)
+	}
+	if request.GetEnd() > 0 {
+		end = time.Unix(request.GetEnd(), 0)
+	}
+	return c.latencies.Query(request.GetName(), start, end)
 }
@@ -386,6 +386,172 @@ func TestREST_GetSystemStats(t *testing.T) {
 		systemStats.MemTotal, systemStats.MemUsed, systemStats.CpuCores, systemStats.CpuUsage, systemStats.IncomingBandwidthSpeed, systemStats.OutgoingBandwidthSpeed)
 }
 
+func TestREST_GetSystemStatsHistory(t *testing.T) {
+	var history common.SystemStatsHistoryResponse
+	if err := sharedTestCtx.createAuthenticatedRequest("GET", "/stats/system/history", &common.SystemStatsHistoryRequest{}, &history); err != nil {
+		t.Fatalf("System stats history request failed: %v", err)
+	}
+	if len(history.GetSamples()) == 0 {
+		t.Fatal("expected at least one history sample")
+	}
+}
+
+func TestREST_GetOutboundsLatencyHistory(t *testing.T) {
+	var history common.LatencyHistoryResponse
+	if err := sharedTestCtx.createAuthenticatedRequest("GET", "/stats/latency/history", &common.LatencyHistoryRequest{}, &history); err != nil {
+		t.Fatalf("Latency history request failed: %v", err)
+	}
+}
+
+func TestREST_GetDetailedSystemStats(t *testing.T) {
+	var stats common.DetailedSystemStatsResponse
+	if err := sharedTestCtx.createAuthenticatedRequest("GET", "/stats/system/detailed", &common.Empty{}, &stats); err != nil {
+		t.Fatalf("Detailed system stats request failed: %v", err)
+	}
+	if len(stats.GetDisks()) == 0 {
+		t.Fatal("expected disk usage for generated config path")
+	}
+}
+
+func TestREST_GetIpLimitViolations(t *testing.T) {
+	var violations common.IpLimitViolationsResponse
+	if err := sharedTestCtx.createAuthenticatedRequest("GET", "/stats/user/ip_limit_violations", &common.IpLimitViolationsRequest{}, &violations); err != nil {
+		t.Fatalf("IP limit violations request failed: %v", err)
+	}
+}
+
+func TestREST_GetPeerAllocations_Unimplemented(t *testing.T) {
+	req, err := http.NewRequest("GET", sharedTestCtx.url+"/users/allocations", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set("x-api-key", apiKey.String())
+
+	resp, err := sharedTestCtx.client.Do(req)
+	if err != nil {
+		t.Fatalf("failed to send allocations request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// The test node runs xray, which has no peer addresses to allocate.
+	if resp.StatusCode != http.StatusNotImplemented {
+		t.Fatalf("unexpected status %d", resp.StatusCode)
+	}
+}
+
+func TestREST_GetClientConfig_Unimplemented(t *testing.T) {
+	body, err := proto.Marshal(&common.ClientConfigRequest{Email: "test_user1@example.com", Endpoint: "127.0.0.1"})
+	if err != nil {
+		t.Fatalf("failed to marshal request: %v", err)
+	}
+	req, err := http.NewRequest("GET", sharedTestCtx.url+"/users/client_config", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set("x-api-key", apiKey.String())
+	req.Header.Set("Content-Type", "application/x-protobuf")
+
+	resp, err := sharedTestCtx.client.Do(req)
+	if err != nil {
+		t.Fatalf("failed to send client config request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotImplemented {
+		t.Fatalf("unexpected status %d", resp.StatusCode)
+	}
+}
+
+func TestREST_UpdatePolicy_Unimplemented(t *testing.T) {
+	body, err := proto.Marshal(&common.PolicyRequest{Policy: &common.Policy{BlockPeerToPeer: true}})
+	if err != nil {
+		t.Fatalf("failed to marshal request: %v", err)
+	}
+	req, err := http.NewRequest("PUT", sharedTestCtx.url+"/policy", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set("x-api-key", apiKey.String())
+	req.Header.Set("Content-Type", "application/x-protobuf")
+
+	resp, err := sharedTestCtx.client.Do(req)
+	if err != nil {
+		t.Fatalf("failed to send policy request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotImplemented {
+		t.Fatalf("unexpected status %d", resp.StatusCode)
+	}
+}
+
+func TestREST_Outbounds(t *testing.T) {
+	if err := sharedTestCtx.createAuthenticatedRequest("PUT", "/outbounds", &common.AddOutboundRequest{Config: `{"tag":"rest-upstream","protocol":"freedom"}`}, &common.Empty{}); err != nil {
+		t.Fatalf("Add outbound request failed: %v", err)
+	}
+
+	var outbounds common.OutboundsResponse
+	if err := sharedTestCtx.createAuthenticatedRequest("GET", "/outbounds", &common.Empty{}, &outbounds); err != nil {
+		t.Fatalf("List outbounds request failed: %v", err)
+	}
+	found := false
+	for _, outbound := range outbounds.GetOutbounds() {
+		found = found || outbound.GetTag() == "rest-upstream"
+	}
+	if !found {
+		t.Fatal("expected the added outbound in the list")
+	}
+
+	if err := sharedTestCtx.createAuthenticatedRequest("DELETE", "/outbounds", &common.RemoveOutboundRequest{Tag: "rest-upstream"}, &common.Empty{}); err != nil {
+		t.Fatalf("Remove outbound request failed: %v", err)
+	}
+}
+
+func TestREST_RoutingRules(t *testing.T) {
+	rule := `{"type":"field","domain":["example.org"],"outboundTag":"BLOCK","ruleTag":"rest-block"}`
+	if err := sharedTestCtx.createAuthenticatedRequest("PUT", "/routing/rules", &common.AddRoutingRuleRequest{Config: rule}, &common.Empty{}); err != nil {
+		t.Fatalf("Add routing rule request failed: %v", err)
+	}
+
+	var rules common.RoutingRulesResponse
+	if err := sharedTestCtx.createAuthenticatedRequest("GET", "/routing/rules", &common.Empty{}, &rules); err != nil {
+		t.Fatalf("List routing rules request failed: %v", err)
+	}
+	if last := rules.GetRules()[len(rules.GetRules())-1]; last.GetTag() != "rest-block" {
+		t.Fatalf("expected the added rule last, got %v", last)
+	}
+
+	if err := sharedTestCtx.createAuthenticatedRequest("DELETE", "/routing/rules", &common.RemoveRoutingRuleRequest{Tag: "rest-block"}, &common.Empty{}); err != nil {
+		t.Fatalf("Remove routing rule request failed: %v", err)
+	}
+}
+
+func TestREST_PeerEventsAndSessions_Unimplemented(t *testing.T) {
+	body, err := proto.Marshal(&common.PeerSessionsRequest{Email: "test_user1@example.com"})
+	if err != nil {
+		t.Fatalf("failed to marshal request: %v", err)
+	}
+
+	for _, path := range []string{"/users/events", "/users/sessions"} {
+		req, err := http.NewRequest("GET", sharedTestCtx.url+path, bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		req.Header.Set("x-api-key", apiKey.String())
+		req.Header.Set("Content-Type", "application/x-protobuf")
+
+		resp, err := sharedTestCtx.client.Do(req)
+		if err != nil {
+			t.Fatalf("failed to send %s request: %v", path, err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusNotImplemented {
+			t.Fatalf("%s: unexpected status %d", path, resp.StatusCode)
+		}
+	}
+}
+
 func TestREST_StopBackend(t *testing.T) {
 	user := &common.User{}
 	if err := sharedTestCtx.createAuthenticatedRequest("PUT", "/stop", user, &common.Empty{}); err != nil {
@@ -43,14 +43,34 @@ func (s *Service) setRouter() {
 		private.Route("/stats", func(statsGroup chi.Router) {
 			statsGroup.Get("/", s.GetStats)
 			statsGroup.Get("/latency", s.GetOutboundsLatency)
+			statsGroup.Get("/latency/history", s.GetOutboundsLatencyHistory)
 			statsGroup.Get("/user/online", s.GetUserOnlineStat)
 			statsGroup.Get("/user/online_ip", s.GetUserOnlineIpListStats)
+			statsGroup.Get("/user/ip_limit_violations", s.GetIpLimitViolations)
 			statsGroup.Get("/backend", s.GetBackendStats)
 			statsGroup.Get("/system", s.GetSystemStats)
+			statsGroup.Get("/system/history", s.GetSystemStatsHistory)
+			statsGroup.Get("/system/detailed", s.GetDetailedSystemStats)
 		})
 		private.Put("/user/sync", s.SyncUser)
 		private.Put("/users/sync", s.SyncUsers)
 		private.Put("/users/sync/chunked", s.SyncUsersChunked)
+		private.Get("/users/allocations", s.GetPeerAllocations)
+		private.Get("/users/client_config", s.GetClientConfig)
+		private.Get("/users/events", s.GetPeerEvents)
+		private.Get("/users/sessions", s.GetPeerSessions)
+		private.Put("/policy", s.UpdatePolicy)
+		private.Route("/outbounds", func(outboundsGroup chi.Router) {
+			outboundsGroup.Get("/", s.ListOutbounds)
+			outboundsGroup.Put("/", s.AddOutbound)
+			outboundsGroup.Delete("/", s.RemoveOutbound)
+		})
+		private.Route("/routing", func(routingGroup chi.Router) {
+			routingGroup.Get("/rules", s.ListRoutingRules)
+			routingGroup.Put("/rules", s.AddRoutingRule)
+			routingGroup.Delete("/rules", s.RemoveRoutingRule)
+			routingGroup.Put("/balancer", s.OverrideBalancerTarget)
+		})
 	})
 
 	s.Router = router
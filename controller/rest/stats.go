@@ -14,7 +14,7 @@ func (s *Service) GetStats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	stats, err := s.Backend().GetStats(r.Context(), &request)
+	stats, err := s.Stats(r.Context(), &request)
 	if err != nil {
 		err = common.InterceptNotFound(err)
 		st, _ := status.FromError(err)
@@ -92,6 +92,50 @@ func (s *Service) GetOutboundsLatency(w http.ResponseWriter, r *http.Request) {
 	common.SendProtoResponse(w, latency)
 }
 
+func (s *Service) GetOutboundsLatencyHistory(w http.ResponseWriter, r *http.Request) {
+	var request common.LatencyHistoryRequest
+	if err := common.ReadProtoBody(r.Body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if request.GetStart() > 0 && request.GetEnd() > 0 && request.GetStart() > request.GetEnd() {
+		http.Error(w, "start must not be after end", http.StatusBadRequest)
+		return
+	}
+
+	common.SendProtoResponse(w, s.OutboundsLatencyHistory(&request))
+}
+
 func (s *Service) GetSystemStats(w http.ResponseWriter, r *http.Request) {
 	common.SendProtoResponse(w, s.SystemStats(r.Context()))
 }
+
+func (s *Service) GetSystemStatsHistory(w http.ResponseWriter, r *http.Request) {
+	var request common.SystemStatsHistoryRequest
+	if err := common.ReadProtoBody(r.Body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if request.GetStart() > 0 && request.GetEnd() > 0 && request.GetStart() > request.GetEnd() {
+		http.Error(w, "start must not be after end", http.StatusBadRequest)
+		return
+	}
+
+	common.SendProtoResponse(w, s.SystemStatsHistory(&request))
+}
+
+func (s *Service) GetDetailedSystemStats(w http.ResponseWriter, r *http.Request) {
+	common.SendProtoResponse(w, s.DetailedSystemStats(r.Context()))
+}
+
+func (s *Service) GetIpLimitViolations(w http.ResponseWriter, r *http.Request) {
+	var request common.IpLimitViolationsRequest
+	if err := common.ReadProtoBody(r.Body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	common.SendProtoResponse(w, s.IpLimitViolations(&request))
+}
@@ -9,6 +9,8 @@ import (
 	"log"
 	"net/http"
 
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/pasarguard/node/common"
@@ -36,7 +38,7 @@ func (s *Service) SyncUser(w http.ResponseWriter, r *http.Request) {
 
 	log.Printf("Got user: %v", user.GetEmail())
 
-	if err = s.Backend().SyncUser(r.Context(), user); err != nil {
+	if err = s.SyncBackendUser(r.Context(), user); err != nil {
 		log.Printf("Error syncing user: %v", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -65,7 +67,7 @@ func (s *Service) SyncUsers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err = s.Backend().SyncUsers(r.Context(), users.GetUsers()); err != nil {
+	if err = s.SyncBackendUsers(r.Context(), users.GetUsers()); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -131,13 +133,13 @@ func (s *Service) SyncUsersChunked(w http.ResponseWriter, r *http.Request) {
 
 	// Large chunk: update in-memory then restart (no API calls).
 	if len(users) > 100 {
-		if err := s.Backend().UpdateUsersAndRestart(r.Context(), users); err != nil {
+		if err := s.UpdateBackendUsersAndRestart(r.Context(), users); err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 	} else {
 		// Small chunk: update via API without restart.
-		if err := s.Backend().UpdateUsers(r.Context(), users); err != nil {
+		if err := s.UpdateBackendUsers(r.Context(), users); err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
@@ -145,3 +147,295 @@ func (s *Service) SyncUsersChunked(w http.ResponseWriter, r *http.Request) {
 
 	common.SendProtoResponse(w, &common.Empty{})
 }
+
+func (s *Service) GetPeerAllocations(w http.ResponseWriter, r *http.Request) {
+	manager, err := s.PeerManager()
+	if err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, err.Error(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	allocations, err := manager.GetPeerAllocations(r.Context())
+	if err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, err.Error(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	common.SendProtoResponse(w, allocations)
+}
+
+func (s *Service) GetClientConfig(w http.ResponseWriter, r *http.Request) {
+	var request common.ClientConfigRequest
+	if err := common.ReadProtoBody(r.Body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	manager, err := s.PeerManager()
+	if err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, err.Error(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	response, err := manager.GetClientConfig(r.Context(), &request)
+	if err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, err.Error(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	common.SendProtoResponse(w, response)
+}
+
+func (s *Service) UpdatePolicy(w http.ResponseWriter, r *http.Request) {
+	var request common.PolicyRequest
+	if err := common.ReadProtoBody(r.Body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	manager, err := s.PeerManager()
+	if err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, err.Error(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	if err = manager.UpdatePolicy(r.Context(), &request); err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, err.Error(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	common.SendProtoResponse(w, &common.Empty{})
+}
+
+func (s *Service) AddOutbound(w http.ResponseWriter, r *http.Request) {
+	var request common.AddOutboundRequest
+	if err := common.ReadProtoBody(r.Body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	manager, err := s.RoutingManager()
+	if err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, err.Error(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	if err = manager.AddOutbound(r.Context(), &request); err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, err.Error(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	common.SendProtoResponse(w, &common.Empty{})
+}
+
+func (s *Service) RemoveOutbound(w http.ResponseWriter, r *http.Request) {
+	var request common.RemoveOutboundRequest
+	if err := common.ReadProtoBody(r.Body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	manager, err := s.RoutingManager()
+	if err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, err.Error(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	if err = manager.RemoveOutbound(r.Context(), request.GetTag()); err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, err.Error(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	common.SendProtoResponse(w, &common.Empty{})
+}
+
+func (s *Service) ListOutbounds(w http.ResponseWriter, r *http.Request) {
+	manager, err := s.RoutingManager()
+	if err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, err.Error(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	response, err := manager.ListOutbounds(r.Context())
+	if err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, err.Error(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	common.SendProtoResponse(w, response)
+}
+
+func (s *Service) AddRoutingRule(w http.ResponseWriter, r *http.Request) {
+	var request common.AddRoutingRuleRequest
+	if err := common.ReadProtoBody(r.Body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	manager, err := s.RoutingManager()
+	if err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, err.Error(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	if err = manager.AddRoutingRule(r.Context(), &request); err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, err.Error(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	common.SendProtoResponse(w, &common.Empty{})
+}
+
+func (s *Service) RemoveRoutingRule(w http.ResponseWriter, r *http.Request) {
+	var request common.RemoveRoutingRuleRequest
+	if err := common.ReadProtoBody(r.Body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	manager, err := s.RoutingManager()
+	if err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, err.Error(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	if err = manager.RemoveRoutingRule(r.Context(), request.GetTag()); err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, err.Error(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	common.SendProtoResponse(w, &common.Empty{})
+}
+
+func (s *Service) ListRoutingRules(w http.ResponseWriter, r *http.Request) {
+	manager, err := s.RoutingManager()
+	if err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, err.Error(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	response, err := manager.ListRoutingRules(r.Context())
+	if err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, err.Error(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	common.SendProtoResponse(w, response)
+}
+
+func (s *Service) OverrideBalancerTarget(w http.ResponseWriter, r *http.Request) {
+	var request common.BalancerTargetRequest
+	if err := common.ReadProtoBody(r.Body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	manager, err := s.RoutingManager()
+	if err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, err.Error(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	if err = manager.OverrideBalancerTarget(r.Context(), request.GetBalancerTag(), request.GetTarget()); err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, err.Error(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	common.SendProtoResponse(w, &common.Empty{})
+}
+
+// GetPeerEvents streams peer events as server-sent events, each carrying one
+// JSON object in its data field.
+func (s *Service) GetPeerEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	manager, err := s.PeerManager()
+	if err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, err.Error(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	events, release, err := manager.SubscribePeerEvents(r.Context())
+	if err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, err.Error(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+	defer release()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	flusher.Flush()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			line, err := protojson.Marshal(event)
+			if err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", line); err != nil {
+				return
+			}
+
+			flusher.Flush()
+
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (s *Service) GetPeerSessions(w http.ResponseWriter, r *http.Request) {
+	var request common.PeerSessionsRequest
+	if err := common.ReadProtoBody(r.Body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	manager, err := s.PeerManager()
+	if err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, err.Error(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	response, err := manager.GetPeerSessions(r.Context(), request.GetEmail())
+	if err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, err.Error(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	common.SendProtoResponse(w, response)
+}
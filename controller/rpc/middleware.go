@@ -190,6 +190,21 @@ var backendMethods = map[string]bool{
 	"/service.NodeService/GetUserOnlineIpListStats": true,
 	"/service.NodeService/GetBackendStats":          true,
 	"/service.NodeService/GetSystemStats":           true,
+	"/service.NodeService/GetSystemStatsHistory":    true,
+	"/service.NodeService/GetDetailedSystemStats":   true,
+	"/service.NodeService/GetIpLimitViolations":     true,
+	"/service.NodeService/GetPeerAllocations":       true,
+	"/service.NodeService/GetClientConfig":          true,
+	"/service.NodeService/UpdatePolicy":             true,
+	"/service.NodeService/GetPeerEvents":            true,
+	"/service.NodeService/GetPeerSessions":          true,
+	"/service.NodeService/AddOutbound":              true,
+	"/service.NodeService/RemoveOutbound":           true,
+	"/service.NodeService/ListOutbounds":            true,
+	"/service.NodeService/AddRoutingRule":           true,
+	"/service.NodeService/RemoveRoutingRule":        true,
+	"/service.NodeService/ListRoutingRules":         true,
+	"/service.NodeService/OverrideBalancerTarget":   true,
 	"/service.NodeService/Stop":                     true,
 	"/service.NodeService/SyncUser":                 true,
 	"/service.NodeService/SyncUsers":                true,
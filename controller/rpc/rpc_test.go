@@ -418,6 +418,178 @@ func TestGRPC_GetSystemStats(t *testing.T) {
 	log.Println("uptime:", nodeStats.GetUptime())
 }
 
+func TestGRPC_GetSystemStatsHistory(t *testing.T) {
+	ctx, cancel := context.WithTimeout(sharedTestCtx.ctxWithSession, 5*time.Second)
+	defer cancel()
+
+	history, err := sharedTestCtx.client.GetSystemStatsHistory(ctx, &common.SystemStatsHistoryRequest{})
+	if err != nil {
+		t.Fatalf("Failed to get system stats history: %v", err)
+	}
+	if len(history.GetSamples()) == 0 {
+		t.Fatal("expected at least one history sample")
+	}
+	log.Println("resolution:", history.GetResolution(), "samples:", len(history.GetSamples()))
+
+	_, err = sharedTestCtx.client.GetSystemStatsHistory(ctx, &common.SystemStatsHistoryRequest{Start: 20, End: 10})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for inverted range, got %v", err)
+	}
+}
+
+func TestGRPC_GetOutboundsLatencyHistory(t *testing.T) {
+	ctx, cancel := context.WithTimeout(sharedTestCtx.ctxWithSession, 5*time.Second)
+	defer cancel()
+
+	if _, err := sharedTestCtx.client.GetOutboundsLatencyHistory(ctx, &common.LatencyHistoryRequest{}); err != nil {
+		t.Fatalf("Failed to get latency history: %v", err)
+	}
+
+	_, err := sharedTestCtx.client.GetOutboundsLatencyHistory(ctx, &common.LatencyHistoryRequest{Start: 20, End: 10})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for inverted range, got %v", err)
+	}
+}
+
+func TestGRPC_GetDetailedSystemStats(t *testing.T) {
+	ctx, cancel := context.WithTimeout(sharedTestCtx.ctxWithSession, 5*time.Second)
+	defer cancel()
+
+	stats, err := sharedTestCtx.client.GetDetailedSystemStats(ctx, &common.Empty{})
+	if err != nil {
+		t.Fatalf("Failed to get detailed system stats: %v", err)
+	}
+	if len(stats.GetDisks()) == 0 {
+		t.Fatal("expected disk usage for generated config path")
+	}
+	log.Println("cpu_core_usage:", stats.GetCpuCoreUsage())
+	log.Println("load:", stats.GetLoad())
+	log.Println("interfaces:", len(stats.GetInterfaces()))
+	log.Println("open_fds:", stats.GetOpenFileDescriptors())
+	log.Println("sockets:", stats.GetSockets())
+}
+
+func TestGRPC_GetIpLimitViolations(t *testing.T) {
+	ctx, cancel := context.WithTimeout(sharedTestCtx.ctxWithSession, 5*time.Second)
+	defer cancel()
+
+	violations, err := sharedTestCtx.client.GetIpLimitViolations(ctx, &common.IpLimitViolationsRequest{})
+	if err != nil {
+		t.Fatalf("Failed to get ip limit violations: %v", err)
+	}
+	log.Println("ip limit violations:", len(violations.GetViolations()))
+}
+
+func TestGRPC_GetPeerAllocations_Unimplemented(t *testing.T) {
+	ctx, cancel := context.WithTimeout(sharedTestCtx.ctxWithSession, 5*time.Second)
+	defer cancel()
+
+	// The test node runs xray, which has no peer addresses to allocate.
+	_, err := sharedTestCtx.client.GetPeerAllocations(ctx, &common.Empty{})
+	if status.Code(err) != codes.Unimplemented {
+		t.Fatalf("expected Unimplemented, got %v", err)
+	}
+}
+
+func TestGRPC_GetClientConfig_Unimplemented(t *testing.T) {
+	ctx, cancel := context.WithTimeout(sharedTestCtx.ctxWithSession, 5*time.Second)
+	defer cancel()
+
+	_, err := sharedTestCtx.client.GetClientConfig(ctx, &common.ClientConfigRequest{Email: "test_user1@example.com", Endpoint: "127.0.0.1"})
+	if status.Code(err) != codes.Unimplemented {
+		t.Fatalf("expected Unimplemented, got %v", err)
+	}
+}
+
+func TestGRPC_UpdatePolicy_Unimplemented(t *testing.T) {
+	ctx, cancel := context.WithTimeout(sharedTestCtx.ctxWithSession, 5*time.Second)
+	defer cancel()
+
+	_, err := sharedTestCtx.client.UpdatePolicy(ctx, &common.PolicyRequest{Policy: &common.Policy{BlockPeerToPeer: true}})
+	if status.Code(err) != codes.Unimplemented {
+		t.Fatalf("expected Unimplemented, got %v", err)
+	}
+}
+
+func TestGRPC_Outbounds(t *testing.T) {
+	ctx, cancel := context.WithTimeout(sharedTestCtx.ctxWithSession, 10*time.Second)
+	defer cancel()
+
+	if _, err := sharedTestCtx.client.AddOutbound(ctx, &common.AddOutboundRequest{Config: `{"tag":"rpc-upstream","protocol":"freedom"}`}); err != nil {
+		t.Fatalf("Failed to add outbound: %v", err)
+	}
+
+	outbounds, err := sharedTestCtx.client.ListOutbounds(ctx, &common.Empty{})
+	if err != nil {
+		t.Fatalf("Failed to list outbounds: %v", err)
+	}
+	found := false
+	for _, outbound := range outbounds.GetOutbounds() {
+		found = found || outbound.GetTag() == "rpc-upstream"
+	}
+	if !found {
+		t.Fatal("expected the added outbound in the list")
+	}
+
+	if _, err = sharedTestCtx.client.RemoveOutbound(ctx, &common.RemoveOutboundRequest{Tag: "rpc-upstream"}); err != nil {
+		t.Fatalf("Failed to remove outbound: %v", err)
+	}
+	_, err = sharedTestCtx.client.RemoveOutbound(ctx, &common.RemoveOutboundRequest{Tag: "BLOCK"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition for an outbound used by routing, got %v", err)
+	}
+}
+
+func TestGRPC_RoutingRules(t *testing.T) {
+	ctx, cancel := context.WithTimeout(sharedTestCtx.ctxWithSession, 10*time.Second)
+	defer cancel()
+
+	rule := `{"type":"field","domain":["example.com"],"outboundTag":"BLOCK","ruleTag":"rpc-block"}`
+	if _, err := sharedTestCtx.client.AddRoutingRule(ctx, &common.AddRoutingRuleRequest{Config: rule}); err != nil {
+		t.Fatalf("Failed to add routing rule: %v", err)
+	}
+
+	rules, err := sharedTestCtx.client.ListRoutingRules(ctx, &common.Empty{})
+	if err != nil {
+		t.Fatalf("Failed to list routing rules: %v", err)
+	}
+	if last := rules.GetRules()[len(rules.GetRules())-1]; last.GetTag() != "rpc-block" {
+		t.Fatalf("expected the added rule last, got %v", last)
+	}
+
+	_, err = sharedTestCtx.client.AddRoutingRule(ctx, &common.AddRoutingRuleRequest{Config: `{"type":"field","inboundTag":["x"],"outboundTag":"API","ruleTag":"api"}`})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for a rule routing to the API, got %v", err)
+	}
+
+	if _, err = sharedTestCtx.client.RemoveRoutingRule(ctx, &common.RemoveRoutingRuleRequest{Tag: "rpc-block"}); err != nil {
+		t.Fatalf("Failed to remove routing rule: %v", err)
+	}
+}
+
+func TestGRPC_GetPeerEvents_Unimplemented(t *testing.T) {
+	ctx, cancel := context.WithTimeout(sharedTestCtx.ctxWithSession, 5*time.Second)
+	defer cancel()
+
+	stream, err := sharedTestCtx.client.GetPeerEvents(ctx, &common.Empty{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Unimplemented {
+		t.Fatalf("expected Unimplemented, got %v", err)
+	}
+}
+
+func TestGRPC_GetPeerSessions_Unimplemented(t *testing.T) {
+	ctx, cancel := context.WithTimeout(sharedTestCtx.ctxWithSession, 5*time.Second)
+	defer cancel()
+
+	_, err := sharedTestCtx.client.GetPeerSessions(ctx, &common.PeerSessionsRequest{Email: "test_user1@example.com"})
+	if status.Code(err) != codes.Unimplemented {
+		t.Fatalf("expected Unimplemented, got %v", err)
+	}
+}
+
 func TestGRPC_KeepAliveTimeout(t *testing.T) {
 	// Wait for keep alive to timeout (10 seconds + buffer)
 	time.Sleep(16 * time.Second)
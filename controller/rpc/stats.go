@@ -3,11 +3,14 @@ package rpc
 import (
 	"context"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"github.com/pasarguard/node/common"
 )
 
 func (s *Service) GetStats(ctx context.Context, request *common.StatRequest) (*common.StatResponse, error) {
-	stats, err := s.Backend().GetStats(ctx, request)
+	stats, err := s.Stats(ctx, request)
 	if err != nil {
 		err = common.InterceptNotFound(err)
 		return nil, err
@@ -41,6 +44,28 @@ func (s *Service) GetOutboundsLatency(ctx context.Context, request *common.Laten
 	return s.OutboundsLatency(ctx, request)
 }
 
+func (s *Service) GetOutboundsLatencyHistory(_ context.Context, request *common.LatencyHistoryRequest) (*common.LatencyHistoryResponse, error) {
+	if request.GetStart() > 0 && request.GetEnd() > 0 && request.GetStart() > request.GetEnd() {
+		return nil, status.Errorf(codes.InvalidArgument, "start must not be after end")
+	}
+	return s.OutboundsLatencyHistory(request), nil
+}
+
 func (s *Service) GetSystemStats(ctx context.Context, _ *common.Empty) (*common.SystemStatsResponse, error) {
 	return s.SystemStats(ctx), nil
 }
+
+func (s *Service) GetSystemStatsHistory(_ context.Context, request *common.SystemStatsHistoryRequest) (*common.SystemStatsHistoryResponse, error) {
+	if request.GetStart() > 0 && request.GetEnd() > 0 && request.GetStart() > request.GetEnd() {
+		return nil, status.Errorf(codes.InvalidArgument, "start must not be after end")
+	}
+	return s.SystemStatsHistory(request), nil
+}
+
+func (s *Service) GetIpLimitViolations(_ context.Context, request *common.IpLimitViolationsRequest) (*common.IpLimitViolationsResponse, error) {
+	return s.IpLimitViolations(request), nil
+}
+
+func (s *Service) GetDetailedSystemStats(ctx context.Context, _ *common.Empty) (*common.DetailedSystemStatsResponse, error) {
+	return s.DetailedSystemStats(ctx), nil
+}
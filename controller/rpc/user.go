@@ -3,6 +3,7 @@ package rpc
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"log"
 
@@ -27,7 +28,7 @@ func (s *Service) SyncUser(stream grpc.ClientStreamingServer[common.User, common
 
 		log.Printf("Got user: %v", user.GetEmail())
 
-		if err = s.Backend().SyncUser(stream.Context(), user); err != nil {
+		if err = s.SyncBackendUser(stream.Context(), user); err != nil {
 			log.Printf("Error syncing user: %v", err)
 			return status.Errorf(codes.Internal, "failed to update user: %v", err)
 		}
@@ -35,7 +36,7 @@ func (s *Service) SyncUser(stream grpc.ClientStreamingServer[common.User, common
 }
 
 func (s *Service) SyncUsers(ctx context.Context, users *common.Users) (*common.Empty, error) {
-	if err := s.Backend().SyncUsers(ctx, users.GetUsers()); err != nil {
+	if err := s.SyncBackendUsers(ctx, users.GetUsers()); err != nil {
 		return nil, err
 	}
 
@@ -74,15 +75,148 @@ func (s *Service) SyncUsersChunked(stream grpc.ClientStreamingServer[common.User
 
 	// Large chunk: update in-memory then restart (no API calls).
 	if len(users) > 100 {
-		if err := s.Backend().UpdateUsersAndRestart(stream.Context(), users); err != nil {
+		if err := s.UpdateBackendUsersAndRestart(stream.Context(), users); err != nil {
 			return status.Errorf(codes.Internal, "failed to update users: %v", err)
 		}
 	} else {
 		// Small chunk: update via API without restart.
-		if err := s.Backend().UpdateUsers(stream.Context(), users); err != nil {
+		if err := s.UpdateBackendUsers(stream.Context(), users); err != nil {
 			return status.Errorf(codes.Internal, "failed to update users: %v", err)
 		}
 	}
 
 	return stream.SendAndClose(&common.Empty{})
 }
+
+func (s *Service) GetPeerAllocations(ctx context.Context, _ *common.Empty) (*common.PeerAllocationsResponse, error) {
+	manager, err := s.PeerManager()
+	if err != nil {
+		return nil, err
+	}
+	return manager.GetPeerAllocations(ctx)
+}
+
+func (s *Service) GetClientConfig(ctx context.Context, request *common.ClientConfigRequest) (*common.ClientConfigResponse, error) {
+	manager, err := s.PeerManager()
+	if err != nil {
+		return nil, err
+	}
+	return manager.GetClientConfig(ctx, request)
+}
+
+func (s *Service) GetPeerEvents(_ *common.Empty, stream common.NodeService_GetPeerEventsServer) error {
+	manager, err := s.PeerManager()
+	if err != nil {
+		return err
+	}
+	events, release, err := manager.SubscribePeerEvents(stream.Context())
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return errors.New("peer event channel closed")
+			}
+			if err := stream.Send(event); err != nil {
+				return fmt.Errorf("failed to send peer event: %w", err)
+			}
+
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+func (s *Service) GetPeerSessions(ctx context.Context, request *common.PeerSessionsRequest) (*common.PeerSessionsResponse, error) {
+	manager, err := s.PeerManager()
+	if err != nil {
+		return nil, err
+	}
+	return manager.GetPeerSessions(ctx, request.GetEmail())
+}
+
+func (s *Service) AddOutbound(ctx context.Context, request *common.AddOutboundRequest) (*common.Empty, error) {
+	manager, err := s.RoutingManager()
+	if err != nil {
+		return nil, err
+	}
+	if err = manager.AddOutbound(ctx, request); err != nil {
+		return nil, err
+	}
+	return &common.Empty{}, nil
+}
+
+func (s *Service) RemoveOutbound(ctx context.Context, request *common.RemoveOutboundRequest) (*common.Empty, error) {
+	manager, err := s.RoutingManager()
+	if err != nil {
+		return nil, err
+	}
+	if err = manager.RemoveOutbound(ctx, request.GetTag()); err != nil {
+		return nil, err
+	}
+	return &common.Empty{}, nil
+}
+
+func (s *Service) ListOutbounds(ctx context.Context, _ *common.Empty) (*common.OutboundsResponse, error) {
+	manager, err := s.RoutingManager()
+	if err != nil {
+		return nil, err
+	}
+	return manager.ListOutbounds(ctx)
+}
+
+func (s *Service) AddRoutingRule(ctx context.Context, request *common.AddRoutingRuleRequest) (*common.Empty, error) {
+	manager, err := s.RoutingManager()
+	if err != nil {
+		return nil, err
+	}
+	if err = manager.AddRoutingRule(ctx, request); err != nil {
+		return nil, err
+	}
+	return &common.Empty{}, nil
+}
+
+func (s *Service) RemoveRoutingRule(ctx context.Context, request *common.RemoveRoutingRuleRequest) (*common.Empty, error) {
+	manager, err := s.RoutingManager()
+	if err != nil {
+		return nil, err
+	}
+	if err = manager.RemoveRoutingRule(ctx, request.GetTag()); err != nil {
+		return nil, err
+	}
+	return &common.Empty{}, nil
+}
+
+func (s *Service) ListRoutingRules(ctx context.Context, _ *common.Empty) (*common.RoutingRulesResponse, error) {
+	manager, err := s.RoutingManager()
+	if err != nil {
+		return nil, err
+	}
+	return manager.ListRoutingRules(ctx)
+}
+
+func (s *Service) OverrideBalancerTarget(ctx context.Context, request *common.BalancerTargetRequest) (*common.Empty, error) {
+	manager, err := s.RoutingManager()
+	if err != nil {
+		return nil, err
+	}
+	if err = manager.OverrideBalancerTarget(ctx, request.GetBalancerTag(), request.GetTarget()); err != nil {
+		return nil, err
+	}
+	return &common.Empty{}, nil
+}
+
+func (s *Service) UpdatePolicy(ctx context.Context, request *common.PolicyRequest) (*common.Empty, error) {
+	manager, err := s.PeerManager()
+	if err != nil {
+		return nil, err
+	}
+	if err = manager.UpdatePolicy(ctx, request); err != nil {
+		return nil, err
+	}
+	return &common.Empty{}, nil
+}
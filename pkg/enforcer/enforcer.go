@@ -0,0 +1,454 @@
+package enforcer
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/pasarguard/node/common"
+)
+
+// maxPendingEvents bounds the queue of events waiting for the next stats pull.
+const maxPendingEvents = 1000
+
+// Backend is the part of backend.Backend the enforcer needs to read usage
+// and to add or remove users.
+type Backend interface {
+	SyncUser(context.Context, *common.User) error
+	GetStats(context.Context, *common.StatRequest) (*common.StatResponse, error)
+	GetUserOnlineIpListStats(context.Context, string) (*common.StatsOnlineIpListResponse, error)
+}
+
+// IPBlocker is implemented by backends that can block a user's extra IPs.
+type IPBlocker interface {
+	BlockUserIPs(context.Context, string, []string) error
+}
+
+// Options configures an Enforcer.
+type Options struct {
+	// Interval between usage checks.
+	Interval time.Duration
+	// IPLimitAction is what happens to a user online from more than max_ips IPs.
+	// Blocking falls back to disabling on backends that cannot block IPs.
+	IPLimitAction common.IpLimitAction
+	// IPLimitPenalty is how long extra IPs stay blocked, or the user disabled.
+	IPLimitPenalty time.Duration
+}
+
+// Enforcer applies per-user limits on the node itself, so users are cut off
+// even while the panel is unreachable.
+//
+// Every user change coming from the panel goes through Apply, which swaps
+// users that are over a limit for a copy without inbounds. Locking order is
+// applyMu -> statsMu -> mu.
+type Enforcer struct {
+	interval       time.Duration
+	ipLimitAction  common.IpLimitAction
+	ipLimitPenalty time.Duration
+	now            func() time.Time
+
+	applyMu sync.Mutex
+	statsMu sync.Mutex
+	mu      sync.Mutex
+
+	backend Backend
+	users   map[string]*userState
+	events  []*common.EnforcementEvent
+	cancel  context.CancelFunc
+
+	violations []*common.IpLimitViolation
+	// released holds users whose blocked IPs must be lifted after the next push.
+	released []string
+
+	// expiry wakes run at the next expire_at so expired users are cut off on time
+	// rather than at the next tick.
+	expiry *time.Timer
+	wake   chan struct{}
+}
+
+// New creates an enforcer that checks usage every opts.Interval once started.
+func New(opts Options) *Enforcer {
+	if opts.Interval <= 0 {
+		opts.Interval = 10 * time.Second
+	}
+	if opts.IPLimitPenalty <= 0 {
+		opts.IPLimitPenalty = 5 * time.Minute
+	}
+	return &Enforcer{
+		interval:       opts.Interval,
+		ipLimitAction:  opts.IPLimitAction,
+		ipLimitPenalty: opts.IPLimitPenalty,
+		now:            time.Now,
+		users:          make(map[string]*userState),
+		cancel:         func() {},
+		wake:           make(chan struct{}, 1),
+	}
+}
+
+// Start attaches the running backend and begins periodic checks.
+func (e *Enforcer) Start(backend Backend) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	e.mu.Lock()
+	e.cancel()
+	e.backend = backend
+	e.cancel = cancel
+	// A fresh backend starts without IP blocks.
+	for _, state := range e.users {
+		state.ipLimit.blocked = nil
+	}
+	e.released = nil
+	e.scheduleLocked(e.now())
+	e.mu.Unlock()
+
+	go e.run(ctx)
+}
+
+// Stop ends periodic checks and detaches the backend. Tracked users are kept
+// so a later Start can pick up where this session left off.
+func (e *Enforcer) Stop() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.cancel()
+	e.cancel = func() {}
+	e.backend = nil
+	if e.expiry != nil {
+		e.expiry.Stop()
+		e.expiry = nil
+	}
+}
+
+func (e *Enforcer) run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.Check(ctx)
+		case <-e.wake:
+			e.enforce(ctx)
+		}
+	}
+}
+
+// Apply records the users pushed by the panel and hands the effective users
+// to push, in the same order. With replace set, users missing from the list
+// stop being tracked, matching a full sync.
+func (e *Enforcer) Apply(users []*common.User, replace bool, push func([]*common.User) error) error {
+	e.applyMu.Lock()
+	defer e.applyMu.Unlock()
+
+	now := e.now()
+
+	e.mu.Lock()
+	if replace {
+		seen := make(map[string]struct{}, len(users))
+		for _, user := range users {
+			seen[user.GetEmail()] = struct{}{}
+		}
+		for email, state := range e.users {
+			if _, ok := seen[email]; !ok {
+				e.releaseLocked(email, state)
+				delete(e.users, email)
+			}
+		}
+	}
+
+	effective := make([]*common.User, len(users))
+	for i, user := range users {
+		effective[i] = e.trackLocked(user, now)
+	}
+	e.scheduleLocked(now)
+	backend, released := e.backend, e.released
+	e.released = nil
+	e.mu.Unlock()
+
+	if err := push(effective); err != nil {
+		return err
+	}
+	if backend != nil {
+		for _, email := range released {
+			e.blockIPs(context.Background(), backend, email, nil)
+		}
+	}
+	return nil
+}
+
+// trackLocked updates the state kept for user and returns what the backend should get.
+func (e *Enforcer) trackLocked(user *common.User, now time.Time) *common.User {
+	if user == nil || user.GetEmail() == "" {
+		return user
+	}
+
+	state, ok := e.users[user.GetEmail()]
+	if !tracksLimits(user) {
+		if ok {
+			if state.disabled {
+				e.emitLocked(user.GetEmail(), common.EnforcementAction_ENABLED, state.reason, now, "limit removed by panel")
+			}
+			e.releaseLocked(user.GetEmail(), state)
+		}
+		delete(e.users, user.GetEmail())
+		return user
+	}
+
+	if !ok {
+		state = &userState{}
+		e.users[user.GetEmail()] = state
+	}
+	if user.GetMaxIps() == 0 {
+		e.releaseLocked(user.GetEmail(), state)
+	}
+	state.reconcile(user, now)
+	state.rollPeriod(now)
+
+	if reason, detail, over := state.violation(now); over {
+		if !state.disabled {
+			state.disabled = true
+			state.reason = reason
+			e.emitLocked(user.GetEmail(), common.EnforcementAction_DISABLED, reason, now, detail)
+		}
+		return disabledCopy(user)
+	}
+
+	if state.disabled {
+		state.disabled = false
+		e.emitLocked(user.GetEmail(), common.EnforcementAction_ENABLED, state.reason, now, "limit no longer exceeded")
+	}
+	return user
+}
+
+// Check refreshes usage counters and online IPs, and disables or re-enables
+// users whose state changed since the last check.
+func (e *Enforcer) Check(ctx context.Context) {
+	e.applyMu.Lock()
+	defer e.applyMu.Unlock()
+
+	e.mu.Lock()
+	backend := e.backend
+	needsCounters := false
+	for _, state := range e.users {
+		if state.user.GetDataLimit() > 0 {
+			needsCounters = true
+			break
+		}
+	}
+	e.mu.Unlock()
+
+	if backend == nil {
+		return
+	}
+
+	if needsCounters {
+		e.statsMu.Lock()
+		response, err := backend.GetStats(ctx, &common.StatRequest{Type: common.StatType_UsersStat})
+		if err == nil {
+			e.mu.Lock()
+			e.observeCountersLocked(response)
+			e.mu.Unlock()
+		}
+		e.statsMu.Unlock()
+		if err != nil {
+			log.Printf("enforcer: failed to read user stats: %v", err)
+		}
+	}
+
+	e.checkIPLimits(ctx, backend)
+	e.enforceLocked(ctx, backend)
+}
+
+// enforce applies state changes that do not need fresh counters, such as expiry.
+func (e *Enforcer) enforce(ctx context.Context) {
+	e.applyMu.Lock()
+	defer e.applyMu.Unlock()
+
+	e.mu.Lock()
+	backend := e.backend
+	e.mu.Unlock()
+
+	if backend == nil {
+		return
+	}
+
+	e.enforceLocked(ctx, backend)
+}
+
+// enforceLocked disables or re-enables users whose state changed. Callers hold applyMu.
+func (e *Enforcer) enforceLocked(ctx context.Context, backend Backend) {
+	now := e.now()
+
+	type change struct {
+		email   string
+		user    *common.User
+		disable bool
+		reason  common.EnforcementReason
+		detail  string
+	}
+
+	e.mu.Lock()
+	changes := make([]change, 0)
+	for email, state := range e.users {
+		state.rollPeriod(now)
+		reason, detail, over := state.violation(now)
+		switch {
+		case over && !state.disabled:
+			changes = append(changes, change{email: email, user: disabledCopy(state.user), disable: true, reason: reason, detail: detail})
+		case !over && state.disabled:
+			changes = append(changes, change{email: email, user: state.user, reason: state.reason, detail: "limit no longer exceeded"})
+		}
+	}
+	e.mu.Unlock()
+
+	for _, c := range changes {
+		if err := backend.SyncUser(ctx, c.user); err != nil {
+			log.Printf("enforcer: failed to update user %s: %v", c.email, err)
+			continue
+		}
+
+		e.mu.Lock()
+		if state, ok := e.users[c.email]; ok {
+			state.disabled = c.disable
+			action := common.EnforcementAction_ENABLED
+			if c.disable {
+				action = common.EnforcementAction_DISABLED
+				state.reason = c.reason
+			}
+			e.emitLocked(c.email, action, c.reason, now, c.detail)
+		}
+		e.mu.Unlock()
+	}
+
+	e.mu.Lock()
+	e.scheduleLocked(now)
+	e.mu.Unlock()
+}
+
+// scheduleLocked arms the expiry timer for the earliest upcoming expire_at.
+func (e *Enforcer) scheduleLocked(now time.Time) {
+	if e.expiry != nil {
+		e.expiry.Stop()
+		e.expiry = nil
+	}
+	if e.backend == nil {
+		return
+	}
+
+	var next time.Time
+	for _, state := range e.users {
+		if state.disabled || state.user.GetExpireAt() <= 0 {
+			continue
+		}
+		expireAt := time.Unix(state.user.GetExpireAt(), 0)
+		if next.IsZero() || expireAt.Before(next) {
+			next = expireAt
+		}
+	}
+	if next.IsZero() {
+		return
+	}
+
+	e.expiry = time.AfterFunc(max(next.Sub(now), 0), func() {
+		select {
+		case e.wake <- struct{}{}:
+		default:
+		}
+	})
+}
+
+// Stats forwards a stats request to the backend. Panel pulls that reset user
+// counters are folded into local usage, and user stats pulls carry the
+// enforcement events recorded since the previous pull.
+func (e *Enforcer) Stats(ctx context.Context, backend Backend, request *common.StatRequest) (*common.StatResponse, error) {
+	e.statsMu.Lock()
+	defer e.statsMu.Unlock()
+
+	response, err := backend.GetStats(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	userStats := request.GetType() == common.StatType_UsersStat || request.GetType() == common.StatType_UserStat
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if userStats && request.GetReset_() {
+		e.observeResetLocked(request, response)
+	}
+	if request.GetType() == common.StatType_UsersStat && len(e.events) > 0 {
+		response.Events = append(response.Events, e.events...)
+		e.events = nil
+	}
+
+	return response, nil
+}
+
+// Events drains the pending enforcement events.
+func (e *Enforcer) Events() []*common.EnforcementEvent {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	events := e.events
+	e.events = nil
+	return events
+}
+
+func (e *Enforcer) observeCountersLocked(response *common.StatResponse) {
+	readings := sumByName(response)
+	for email, state := range e.users {
+		state.observeCounter(readings[email])
+	}
+}
+
+func (e *Enforcer) observeResetLocked(request *common.StatRequest, response *common.StatResponse) {
+	readings := sumByName(response)
+	if request.GetType() == common.StatType_UserStat {
+		if state, ok := e.users[request.GetName()]; ok {
+			state.observeReset(readings[request.GetName()])
+		}
+		return
+	}
+	for email, state := range e.users {
+		state.observeReset(readings[email])
+	}
+}
+
+func (e *Enforcer) emitLocked(email string, action common.EnforcementAction, reason common.EnforcementReason, at time.Time, detail string) {
+	log.Printf("enforcer: %s user %s (%s): %s", action, email, reason, detail)
+
+	e.events = append(e.events, &common.EnforcementEvent{
+		Email:     email,
+		Action:    action,
+		Reason:    reason,
+		Timestamp: at.Unix(),
+		Detail:    detail,
+	})
+	if overflow := len(e.events) - maxPendingEvents; overflow > 0 {
+		e.events = append([]*common.EnforcementEvent(nil), e.events[overflow:]...)
+	}
+}
+
+func sumByName(response *common.StatResponse) map[string]uint64 {
+	readings := make(map[string]uint64, len(response.GetStats()))
+	for _, stat := range response.GetStats() {
+		if stat.GetValue() > 0 {
+			readings[stat.GetName()] += uint64(stat.GetValue())
+		}
+	}
+	return readings
+}
+
+// disabledCopy returns user without inbounds, which removes it from every
+// Xray inbound and drops its WireGuard peer.
+func disabledCopy(user *common.User) *common.User {
+	clone := proto.Clone(user).(*common.User)
+	clone.Inbounds = nil
+	return clone
+}
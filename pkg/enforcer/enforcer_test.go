@@ -0,0 +1,482 @@
+package enforcer
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pasarguard/node/common"
+)
+
+type fakeBackend struct {
+	mu          sync.Mutex
+	counters    map[string]int64
+	synced      []*common.User
+	online      map[string]map[string]int64
+	blocked     map[string][]string
+	cannotBlock bool
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{
+		counters: make(map[string]int64),
+		online:   make(map[string]map[string]int64),
+		blocked:  make(map[string][]string),
+	}
+}
+
+func (b *fakeBackend) GetUserOnlineIpListStats(_ context.Context, email string) (*common.StatsOnlineIpListResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return &common.StatsOnlineIpListResponse{Name: email, Ips: b.online[email]}, nil
+}
+
+func (b *fakeBackend) BlockUserIPs(_ context.Context, email string, ips []string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cannotBlock {
+		return status.Error(codes.Unimplemented, "not supported")
+	}
+	if len(ips) == 0 {
+		delete(b.blocked, email)
+		return nil
+	}
+	b.blocked[email] = ips
+	return nil
+}
+
+func (b *fakeBackend) setOnline(email string, ips ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	online := make(map[string]int64, len(ips))
+	for _, ip := range ips {
+		online[ip] = 1
+	}
+	b.online[email] = online
+}
+
+func (b *fakeBackend) blockedIPs(email string) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.blocked[email]
+}
+
+func (b *fakeBackend) SyncUser(_ context.Context, user *common.User) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.synced = append(b.synced, user)
+	return nil
+}
+
+func (b *fakeBackend) GetStats(_ context.Context, request *common.StatRequest) (*common.StatResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	response := &common.StatResponse{}
+	for email, value := range b.counters {
+		if request.GetType() == common.StatType_UserStat && email != request.GetName() {
+			continue
+		}
+		response.Stats = append(response.Stats,
+			&common.Stat{Name: email, Type: "uplink", Value: value / 2},
+			&common.Stat{Name: email, Type: "downlink", Value: value - value/2},
+		)
+		if request.GetReset_() {
+			b.counters[email] = 0
+		}
+	}
+	return response, nil
+}
+
+func (b *fakeBackend) set(email string, value int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.counters[email] = value
+}
+
+func (b *fakeBackend) lastSynced() *common.User {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.synced) == 0 {
+		return nil
+	}
+	return b.synced[len(b.synced)-1]
+}
+
+func limitedUser(email string, limit, used uint64) *common.User {
+	return &common.User{
+		Email:       email,
+		Inbounds:    []string{"vless-in"},
+		DataLimit:   limit,
+		UsedTraffic: used,
+	}
+}
+
+func newTestEnforcer(backend *fakeBackend, now *time.Time) *Enforcer {
+	e := New(Options{Interval: time.Hour, IPLimitPenalty: time.Minute})
+	e.now = func() time.Time { return *now }
+	e.backend = backend
+	return e
+}
+
+func applyUsers(t *testing.T, e *Enforcer, replace bool, users ...*common.User) []*common.User {
+	t.Helper()
+	var effective []*common.User
+	if err := e.Apply(users, replace, func(u []*common.User) error {
+		effective = u
+		return nil
+	}); err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+	return effective
+}
+
+func TestEnforcer_DisablesUserOverDataLimit(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	backend := newFakeBackend()
+	e := newTestEnforcer(backend, &now)
+
+	applyUsers(t, e, true, limitedUser("alice", 1000, 600))
+
+	backend.set("alice", 300)
+	e.Check(context.Background())
+	if backend.lastSynced() != nil {
+		t.Fatal("expected no backend change below the limit")
+	}
+
+	backend.set("alice", 450)
+	e.Check(context.Background())
+
+	synced := backend.lastSynced()
+	if synced == nil || synced.GetEmail() != "alice" {
+		t.Fatalf("expected alice to be synced, got %v", synced)
+	}
+	if len(synced.GetInbounds()) != 0 {
+		t.Fatalf("expected disabled user without inbounds, got %v", synced.GetInbounds())
+	}
+
+	response, err := e.Stats(context.Background(), backend, &common.StatRequest{Type: common.StatType_UsersStat})
+	if err != nil {
+		t.Fatalf("stats failed: %v", err)
+	}
+	if len(response.GetEvents()) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(response.GetEvents()))
+	}
+	event := response.GetEvents()[0]
+	if event.GetEmail() != "alice" || event.GetAction() != common.EnforcementAction_DISABLED || event.GetReason() != common.EnforcementReason_DATA_LIMIT {
+		t.Errorf("unexpected event: %v", event)
+	}
+
+	response, _ = e.Stats(context.Background(), backend, &common.StatRequest{Type: common.StatType_UsersStat})
+	if len(response.GetEvents()) != 0 {
+		t.Errorf("expected events to be drained, got %d", len(response.GetEvents()))
+	}
+}
+
+func TestEnforcer_CountsTrafficPulledByPanel(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	backend := newFakeBackend()
+	e := newTestEnforcer(backend, &now)
+
+	applyUsers(t, e, true, limitedUser("bob", 1000, 0))
+
+	backend.set("bob", 700)
+	if _, err := e.Stats(context.Background(), backend, &common.StatRequest{Type: common.StatType_UsersStat, Reset_: true}); err != nil {
+		t.Fatalf("stats failed: %v", err)
+	}
+
+	backend.set("bob", 400)
+	e.Check(context.Background())
+
+	if synced := backend.lastSynced(); synced == nil || len(synced.GetInbounds()) != 0 {
+		t.Fatalf("expected bob to be disabled after 1100 bytes, got %v", synced)
+	}
+}
+
+func TestEnforcer_KeepsUsageAcrossCounterReset(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	backend := newFakeBackend()
+	e := newTestEnforcer(backend, &now)
+
+	applyUsers(t, e, true, limitedUser("carol", 1000, 0))
+
+	backend.set("carol", 800)
+	e.Check(context.Background())
+
+	// Core restarted and counters started from zero.
+	backend.set("carol", 200)
+	e.Check(context.Background())
+
+	if synced := backend.lastSynced(); synced == nil || len(synced.GetInbounds()) != 0 {
+		t.Fatalf("expected carol to be disabled after restart, got %v", synced)
+	}
+}
+
+func TestEnforcer_PeriodResetReenablesUser(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	backend := newFakeBackend()
+	e := newTestEnforcer(backend, &now)
+
+	user := limitedUser("dave", 1000, 1200)
+	user.DataLimitResetStrategy = common.DataLimitResetStrategy_DAY
+	user.LastResetAt = now.Add(-time.Hour).Unix()
+
+	effective := applyUsers(t, e, true, user)
+	if len(effective[0].GetInbounds()) != 0 {
+		t.Fatal("expected user over limit to start disabled")
+	}
+
+	now = now.Add(24 * time.Hour)
+	e.Check(context.Background())
+
+	synced := backend.lastSynced()
+	if synced == nil || len(synced.GetInbounds()) != 1 {
+		t.Fatalf("expected dave to be re-enabled after the daily reset, got %v", synced)
+	}
+
+	events := e.Events()
+	if len(events) != 2 || events[1].GetAction() != common.EnforcementAction_ENABLED {
+		t.Fatalf("expected disable then enable events, got %v", events)
+	}
+}
+
+func TestEnforcer_PanelReconcileReenablesUser(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	backend := newFakeBackend()
+	e := newTestEnforcer(backend, &now)
+
+	applyUsers(t, e, true, limitedUser("erin", 1000, 0))
+	backend.set("erin", 1500)
+	e.Check(context.Background())
+	if synced := backend.lastSynced(); synced == nil || len(synced.GetInbounds()) != 0 {
+		t.Fatal("expected erin to be disabled")
+	}
+
+	// Panel raised the limit after the user renewed.
+	effective := applyUsers(t, e, false, limitedUser("erin", 5000, 1500))
+	if len(effective[0].GetInbounds()) != 1 {
+		t.Fatalf("expected erin to be enabled after renewal, got %v", effective[0].GetInbounds())
+	}
+
+	// Limit removed entirely: user is no longer tracked.
+	effective = applyUsers(t, e, false, &common.User{Email: "erin", Inbounds: []string{"vless-in"}})
+	if len(effective[0].GetInbounds()) != 1 {
+		t.Fatal("expected unlimited user to pass through")
+	}
+	if _, ok := e.users["erin"]; ok {
+		t.Error("expected unlimited user to stop being tracked")
+	}
+}
+
+func TestResetAfter(t *testing.T) {
+	from := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		strategy common.DataLimitResetStrategy
+		want     time.Time
+	}{
+		{common.DataLimitResetStrategy_DAY, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{common.DataLimitResetStrategy_WEEK, time.Date(2026, 2, 7, 0, 0, 0, 0, time.UTC)},
+		{common.DataLimitResetStrategy_MONTH, time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{common.DataLimitResetStrategy_YEAR, time.Date(2027, 1, 31, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		if got := resetAfter(from, 1, tt.strategy); !got.Equal(tt.want) {
+			t.Errorf("%s: got %s want %s", tt.strategy, got, tt.want)
+		}
+	}
+}
+
+func TestRollPeriodKeepsMonthEndAnchor(t *testing.T) {
+	anchor := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	state := &userState{}
+	state.reconcile(&common.User{
+		Email:                  "frank",
+		DataLimit:              1000,
+		DataLimitResetStrategy: common.DataLimitResetStrategy_MONTH,
+	}, anchor)
+
+	state.rollPeriod(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	if want := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC); !state.periodStart.Equal(want) {
+		t.Fatalf("expected period to start %s, got %s", want, state.periodStart)
+	}
+
+	state.rollPeriod(time.Date(2026, 3, 30, 0, 0, 0, 0, time.UTC))
+	if want := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC); !state.periodStart.Equal(want) {
+		t.Fatalf("expected no reset before Mar 31, got period start %s", state.periodStart)
+	}
+
+	state.rollPeriod(time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC))
+	if want := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC); !state.periodStart.Equal(want) {
+		t.Fatalf("expected period to start %s, got %s", want, state.periodStart)
+	}
+}
+
+func TestEnforcer_DisablesExpiredUser(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	backend := newFakeBackend()
+	e := newTestEnforcer(backend, &now)
+
+	user := &common.User{Email: "frank", Inbounds: []string{"vless-in"}, ExpireAt: now.Add(time.Hour).Unix()}
+	effective := applyUsers(t, e, true, user)
+	if len(effective[0].GetInbounds()) != 1 {
+		t.Fatal("expected user to be active before expiry")
+	}
+
+	now = now.Add(time.Hour)
+	e.enforce(context.Background())
+
+	synced := backend.lastSynced()
+	if synced == nil || len(synced.GetInbounds()) != 0 {
+		t.Fatalf("expected frank to be disabled at expiry, got %v", synced)
+	}
+	events := e.Events()
+	if len(events) != 1 || events[0].GetReason() != common.EnforcementReason_EXPIRED {
+		t.Fatalf("expected one expiry event, got %v", events)
+	}
+
+	// Panel renews the user.
+	user.ExpireAt = now.Add(30 * 24 * time.Hour).Unix()
+	effective = applyUsers(t, e, false, user)
+	if len(effective[0].GetInbounds()) != 1 {
+		t.Fatal("expected renewed user to be enabled")
+	}
+}
+
+func TestEnforcer_ExpiryTimerFiresWithoutTick(t *testing.T) {
+	backend := newFakeBackend()
+	e := New(Options{Interval: time.Hour})
+	defer e.Stop()
+
+	applyUsers(t, e, true, &common.User{
+		Email:    "grace",
+		Inbounds: []string{"vless-in"},
+		ExpireAt: time.Now().Add(time.Second).Unix(),
+	})
+	e.Start(backend)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if synced := backend.lastSynced(); synced != nil {
+			if len(synced.GetInbounds()) != 0 {
+				t.Fatalf("expected grace to be disabled, got %v", synced)
+			}
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatal("expiry timer did not disable the user")
+}
+
+func TestEnforcer_BlocksNewestIPsOverLimit(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	backend := newFakeBackend()
+	e := newTestEnforcer(backend, &now)
+
+	applyUsers(t, e, true, &common.User{Email: "heidi", Inbounds: []string{"vless-in"}, MaxIps: 1})
+
+	backend.setOnline("heidi", "10.0.0.1")
+	e.Check(context.Background())
+
+	now = now.Add(time.Second)
+	backend.setOnline("heidi", "10.0.0.1", "10.0.0.2", "10.0.0.3")
+	e.Check(context.Background())
+
+	if got := backend.blockedIPs("heidi"); !slices.Equal(got, []string{"10.0.0.2", "10.0.0.3"}) {
+		t.Fatalf("expected the newest ips to be blocked, got %v", got)
+	}
+	if backend.lastSynced() != nil {
+		t.Fatal("expected the user to stay enabled while blocking ips")
+	}
+
+	violations := e.Violations("heidi", 0)
+	if len(violations) != 1 || violations[0].GetAction() != common.IpLimitAction_BLOCK_IPS {
+		t.Fatalf("expected one block violation, got %v", violations)
+	}
+	if len(e.Violations("someone-else", 0)) != 0 || len(e.Violations("", now.Unix()+1)) != 0 {
+		t.Error("expected violations to be filtered by email and time")
+	}
+
+	// Blocks are lifted once the penalty is over.
+	now = now.Add(time.Minute)
+	backend.setOnline("heidi", "10.0.0.1")
+	e.Check(context.Background())
+	if got := backend.blockedIPs("heidi"); len(got) != 0 {
+		t.Fatalf("expected blocks to expire, got %v", got)
+	}
+}
+
+func TestEnforcer_DisablesWhenBlockingIsUnsupported(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	backend := newFakeBackend()
+	backend.cannotBlock = true
+	e := newTestEnforcer(backend, &now)
+
+	applyUsers(t, e, true, &common.User{Email: "ivan", Inbounds: []string{"wg"}, MaxIps: 1})
+
+	backend.setOnline("ivan", "10.0.0.1", "10.0.0.2")
+	e.Check(context.Background())
+
+	synced := backend.lastSynced()
+	if synced == nil || len(synced.GetInbounds()) != 0 {
+		t.Fatalf("expected ivan to be disabled, got %v", synced)
+	}
+	violations := e.Violations("ivan", 0)
+	if len(violations) != 1 || violations[0].GetAction() != common.IpLimitAction_DISABLE_USER {
+		t.Fatalf("expected a disable violation, got %v", violations)
+	}
+	events := e.Events()
+	if len(events) != 1 || events[0].GetReason() != common.EnforcementReason_IP_LIMIT {
+		t.Fatalf("expected an ip limit event, got %v", events)
+	}
+
+	now = now.Add(time.Minute)
+	e.Check(context.Background())
+	if synced := backend.lastSynced(); synced == nil || len(synced.GetInbounds()) != 1 {
+		t.Fatalf("expected ivan to be re-enabled after the penalty, got %v", synced)
+	}
+}
+
+func TestEnforcer_DisablesWhenBackendCannotBlock(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	backend := newFakeBackend()
+	e := newTestEnforcer(backend, &now)
+	// Only the methods of Backend are promoted, so this backend is no IPBlocker.
+	e.backend = struct{ Backend }{backend}
+
+	applyUsers(t, e, true, &common.User{Email: "judy", Inbounds: []string{"wg"}, MaxIps: 1})
+
+	backend.setOnline("judy", "10.0.0.1", "10.0.0.2")
+	e.Check(context.Background())
+
+	if synced := backend.lastSynced(); synced == nil || len(synced.GetInbounds()) != 0 {
+		t.Fatalf("expected judy to be disabled, got %v", synced)
+	}
+	if violations := e.Violations("judy", 0); len(violations) != 1 || violations[0].GetAction() != common.IpLimitAction_DISABLE_USER {
+		t.Fatalf("expected a disable violation, got %v", violations)
+	}
+}
+
+func TestEnforcer_LiftsBlocksWhenLimitRemoved(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	backend := newFakeBackend()
+	e := newTestEnforcer(backend, &now)
+
+	applyUsers(t, e, true, &common.User{Email: "judy", Inbounds: []string{"vless-in"}, MaxIps: 1})
+	backend.setOnline("judy", "10.0.0.1", "10.0.0.2")
+	e.Check(context.Background())
+	if len(backend.blockedIPs("judy")) != 1 {
+		t.Fatal("expected one blocked ip")
+	}
+
+	applyUsers(t, e, false, &common.User{Email: "judy", Inbounds: []string{"vless-in"}})
+	if got := backend.blockedIPs("judy"); len(got) != 0 {
+		t.Fatalf("expected blocks to be lifted, got %v", got)
+	}
+}
@@ -0,0 +1,222 @@
+package enforcer
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/pasarguard/node/common"
+)
+
+// maxIPLimitViolations bounds the violation log kept for GetIpLimitViolations.
+const maxIPLimitViolations = 1000
+
+// ipLimitState tracks the source IPs of a user with max_ips.
+type ipLimitState struct {
+	// firstSeen orders online IPs so the oldest ones keep working.
+	firstSeen map[string]time.Time
+	// blocked maps a blocked IP to the end of its penalty.
+	blocked map[string]time.Time
+	// disabledUntil is set when the user is disabled for going over the limit.
+	disabledUntil time.Time
+}
+
+// observe records the online IPs and returns them with the ones over maxIPs,
+// newest first-seen being over the limit. Blocked IPs do not count.
+func (s *ipLimitState) observe(online map[string]int64, maxIPs uint32, now time.Time) ([]string, []string) {
+	if s.firstSeen == nil {
+		s.firstSeen = make(map[string]time.Time)
+	}
+	for ip := range s.firstSeen {
+		if _, ok := online[ip]; !ok {
+			delete(s.firstSeen, ip)
+		}
+	}
+
+	ips := make([]string, 0, len(online))
+	active := make([]string, 0, len(online))
+	for ip := range online {
+		ips = append(ips, ip)
+		if _, ok := s.firstSeen[ip]; !ok {
+			s.firstSeen[ip] = now
+		}
+		if _, ok := s.blocked[ip]; !ok {
+			active = append(active, ip)
+		}
+	}
+	slices.Sort(ips)
+
+	if len(active) <= int(maxIPs) {
+		return ips, nil
+	}
+	slices.SortFunc(active, func(a, b string) int {
+		if c := s.firstSeen[a].Compare(s.firstSeen[b]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+	return ips, active[maxIPs:]
+}
+
+// expireBlocks drops blocks whose penalty is over and reports whether any did.
+func (s *ipLimitState) expireBlocks(now time.Time) bool {
+	expired := false
+	for ip, until := range s.blocked {
+		if !now.Before(until) {
+			delete(s.blocked, ip)
+			expired = true
+		}
+	}
+	return expired
+}
+
+func (s *ipLimitState) blockedIPs() []string {
+	ips := make([]string, 0, len(s.blocked))
+	for ip := range s.blocked {
+		ips = append(ips, ip)
+	}
+	slices.Sort(ips)
+	return ips
+}
+
+// checkIPLimits compares the online IPs of every user with max_ips to the
+// limit and blocks the extra IPs or disables the user, depending on the
+// configured action. Callers hold applyMu.
+func (e *Enforcer) checkIPLimits(ctx context.Context, backend Backend) {
+	now := e.now()
+
+	e.mu.Lock()
+	emails := make([]string, 0)
+	for email, state := range e.users {
+		if state.disabled || (state.user.GetMaxIps() == 0 && len(state.ipLimit.blocked) == 0) {
+			continue
+		}
+		emails = append(emails, email)
+	}
+	e.mu.Unlock()
+
+	for _, email := range emails {
+		var online map[string]int64
+		response, err := backend.GetUserOnlineIpListStats(ctx, email)
+		if err == nil {
+			online = response.GetIps()
+		}
+
+		e.mu.Lock()
+		state, ok := e.users[email]
+		if !ok {
+			e.mu.Unlock()
+			continue
+		}
+		limit := &state.ipLimit
+		changed := limit.expireBlocks(now)
+
+		var ips, excess []string
+		if maxIPs := state.user.GetMaxIps(); maxIPs > 0 && err == nil {
+			ips, excess = limit.observe(online, maxIPs, now)
+		}
+		if len(excess) == 0 {
+			blocked := limit.blockedIPs()
+			e.mu.Unlock()
+			if changed {
+				e.blockIPs(ctx, backend, email, blocked)
+			}
+			continue
+		}
+
+		action := e.ipLimitAction
+		var blocked []string
+		if action == common.IpLimitAction_BLOCK_IPS {
+			if limit.blocked == nil {
+				limit.blocked = make(map[string]time.Time)
+			}
+			for _, ip := range excess {
+				limit.blocked[ip] = now.Add(e.ipLimitPenalty)
+			}
+			blocked = limit.blockedIPs()
+		}
+		e.mu.Unlock()
+
+		if action == common.IpLimitAction_BLOCK_IPS && !e.blockIPs(ctx, backend, email, blocked) {
+			action = common.IpLimitAction_DISABLE_USER
+		}
+
+		e.mu.Lock()
+		if action == common.IpLimitAction_DISABLE_USER {
+			for _, ip := range excess {
+				delete(limit.blocked, ip)
+			}
+			blocked = excess
+			limit.disabledUntil = now.Add(e.ipLimitPenalty)
+			limit.firstSeen = nil
+		}
+		e.recordViolationLocked(&common.IpLimitViolation{
+			Email:      email,
+			MaxIps:     state.user.GetMaxIps(),
+			Ips:        ips,
+			BlockedIps: blocked,
+			Action:     action,
+			Timestamp:  now.Unix(),
+		})
+		e.mu.Unlock()
+	}
+}
+
+// blockIPs replaces the blocked IPs of email on the backend. It reports false
+// when the backend cannot block IPs.
+func (e *Enforcer) blockIPs(ctx context.Context, backend Backend, email string, ips []string) bool {
+	blocker, ok := backend.(IPBlocker)
+	if !ok {
+		return false
+	}
+	if err := blocker.BlockUserIPs(ctx, email, ips); err != nil {
+		log.Printf("enforcer: failed to block ips of user %s: %v", email, err)
+		return false
+	}
+	return true
+}
+
+// releaseLocked forgets the IP-limit state of a user and queues its blocked
+// IPs to be lifted once the current Apply is pushed.
+func (e *Enforcer) releaseLocked(email string, state *userState) {
+	if len(state.ipLimit.blocked) > 0 {
+		e.released = append(e.released, email)
+	}
+	state.ipLimit = ipLimitState{}
+}
+
+func (e *Enforcer) recordViolationLocked(violation *common.IpLimitViolation) {
+	log.Printf("enforcer: user %s has %d online ips, limit %d: %s %v", violation.GetEmail(), len(violation.GetIps()), violation.GetMaxIps(), violation.GetAction(), violation.GetBlockedIps())
+
+	e.violations = append(e.violations, violation)
+	if overflow := len(e.violations) - maxIPLimitViolations; overflow > 0 {
+		e.violations = append([]*common.IpLimitViolation(nil), e.violations[overflow:]...)
+	}
+}
+
+// Violations returns the recorded IP limit violations at or after since,
+// optionally for a single user.
+func (e *Enforcer) Violations(email string, since int64) []*common.IpLimitViolation {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	violations := make([]*common.IpLimitViolation, 0)
+	for _, violation := range e.violations {
+		if violation.GetTimestamp() < since {
+			continue
+		}
+		if email != "" && violation.GetEmail() != email {
+			continue
+		}
+		violations = append(violations, violation)
+	}
+	return violations
+}
+
+// ipLimitDetail describes an active IP limit penalty for enforcement events.
+func ipLimitDetail(until time.Time) string {
+	return fmt.Sprintf("too many concurrent ips, disabled until %s", until.UTC().Format(time.RFC3339))
+}
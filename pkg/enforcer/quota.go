@@ -0,0 +1,152 @@
+package enforcer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pasarguard/node/common"
+)
+
+// userState is the usage the node has seen for one limited user.
+//
+// Usage is panelUsed + local + counter:
+//   - panelUsed is the traffic the panel reported when it last pushed the user.
+//   - local is traffic the node has accounted since then: counters the panel
+//     pulled with reset, and counters lost to a core restart. It goes negative
+//     when a period rolls over mid-counter.
+//   - counter is the latest unreset backend reading.
+type userState struct {
+	user        *common.User
+	panelUsed   uint64
+	local       int64
+	counter     uint64
+	periodStart time.Time
+	// periodAnchor and periods locate periodStart as whole periods after the
+	// anchor, so month-end reset days survive short months.
+	periodAnchor time.Time
+	periods      int
+	disabled     bool
+	reason       common.EnforcementReason
+	ipLimit      ipLimitState
+}
+
+func tracksLimits(user *common.User) bool {
+	return user.GetDataLimit() > 0 || user.GetExpireAt() > 0 || user.GetMaxIps() > 0
+}
+
+// reconcile takes the panel's view of the user. A changed used_traffic means
+// the panel has accounted everything pulled so far, so local usage restarts
+// from it; an unchanged one is treated as stale and local usage is kept.
+func (s *userState) reconcile(user *common.User, now time.Time) {
+	first := s.user == nil
+	s.user = user
+
+	if first || user.GetUsedTraffic() != s.panelUsed {
+		s.panelUsed = user.GetUsedTraffic()
+		s.local = 0
+	}
+
+	switch {
+	case user.GetLastResetAt() > 0:
+		s.startPeriods(time.Unix(user.GetLastResetAt(), 0))
+	case first || s.periodStart.IsZero():
+		s.startPeriods(now)
+	}
+}
+
+func (s *userState) startPeriods(anchor time.Time) {
+	s.periodStart = anchor
+	s.periodAnchor = anchor
+	s.periods = 0
+}
+
+func (s *userState) used() uint64 {
+	total := int64(s.panelUsed) + s.local + int64(s.counter)
+	if total < 0 {
+		return 0
+	}
+	return uint64(total)
+}
+
+// observeCounter records an unreset reading. A reading below the previous one
+// means the core restarted, so the lost counter is kept as local usage.
+func (s *userState) observeCounter(reading uint64) {
+	if reading < s.counter {
+		s.local += int64(s.counter)
+	}
+	s.counter = reading
+}
+
+// observeReset records a reading the panel pulled with reset.
+func (s *userState) observeReset(reading uint64) {
+	s.local += int64(reading)
+	s.counter = 0
+}
+
+// rollPeriod starts a new usage period once the reset strategy says so.
+func (s *userState) rollPeriod(now time.Time) {
+	strategy := s.user.GetDataLimitResetStrategy()
+	if strategy == common.DataLimitResetStrategy_NO_RESET || s.periodStart.IsZero() {
+		return
+	}
+
+	next := resetAfter(s.periodAnchor, s.periods+1, strategy)
+	if now.Before(next) {
+		return
+	}
+	for !now.Before(next) {
+		s.periods++
+		s.periodStart = next
+		next = resetAfter(s.periodAnchor, s.periods+1, strategy)
+	}
+
+	s.panelUsed = 0
+	s.local = -int64(s.counter)
+}
+
+// violation reports whether the user is over a limit right now.
+func (s *userState) violation(now time.Time) (common.EnforcementReason, string, bool) {
+	if expireAt := s.user.GetExpireAt(); expireAt > 0 && now.Unix() >= expireAt {
+		return common.EnforcementReason_EXPIRED, fmt.Sprintf("expired at %s", time.Unix(expireAt, 0).UTC().Format(time.RFC3339)), true
+	}
+	if limit := s.user.GetDataLimit(); limit > 0 {
+		if used := s.used(); used >= limit {
+			return common.EnforcementReason_DATA_LIMIT, fmt.Sprintf("used %d of %d bytes", used, limit), true
+		}
+	}
+	if until := s.ipLimit.disabledUntil; now.Before(until) {
+		return common.EnforcementReason_IP_LIMIT, ipLimitDetail(until), true
+	}
+	return 0, "", false
+}
+
+// resetAfter returns the reset that ends the given number of periods after
+// anchor. Each reset is computed from the anchor rather than the previous
+// reset, so a clamped month end does not move later resets.
+func resetAfter(anchor time.Time, periods int, strategy common.DataLimitResetStrategy) time.Time {
+	switch strategy {
+	case common.DataLimitResetStrategy_DAY:
+		return anchor.AddDate(0, 0, periods)
+	case common.DataLimitResetStrategy_WEEK:
+		return anchor.AddDate(0, 0, 7*periods)
+	case common.DataLimitResetStrategy_MONTH:
+		return addMonthsClamped(anchor, periods)
+	case common.DataLimitResetStrategy_YEAR:
+		return addMonthsClamped(anchor, 12*periods)
+	default:
+		return anchor
+	}
+}
+
+// addMonthsClamped adds months without overflowing short months,
+// so Jan 31 is followed by Feb 28 rather than Mar 3.
+func addMonthsClamped(from time.Time, months int) time.Time {
+	year, month, day := from.Date()
+	firstOfTarget := time.Date(year, month+time.Month(months), 1, 0, 0, 0, 0, from.Location())
+	lastDay := firstOfTarget.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	hour, minute, sec := from.Clock()
+	return time.Date(firstOfTarget.Year(), firstOfTarget.Month(), day, hour, minute, sec, from.Nanosecond(), from.Location())
+}
@@ -0,0 +1,209 @@
+package latency
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pasarguard/node/common"
+)
+
+const (
+	// DefaultRetention is how long probe results are kept per name.
+	DefaultRetention = 24 * time.Hour
+	// DefaultWindow is used for the window statistics when a request does not set one.
+	DefaultWindow = 5 * time.Minute
+
+	maxSamplesPerName = 4096
+)
+
+type sample struct {
+	at    time.Time
+	alive bool
+	delay int64
+}
+
+type series struct {
+	samples []sample
+	lastTry int64
+}
+
+// History keeps a rolling window of probe results per outbound or interface
+// so flaky links can be told apart from slow ones.
+type History struct {
+	mu        sync.RWMutex
+	retention time.Duration
+	series    map[string]*series
+}
+
+// NewHistory creates a history keeping samples for the given retention.
+func NewHistory(retention time.Duration) *History {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	return &History{
+		retention: retention,
+		series:    make(map[string]*series),
+	}
+}
+
+// Record adds the probe results reported at the given time. A result with the
+// same last try time as the previous one for its name is the same probe read
+// again, so it is skipped.
+func (h *History) Record(at time.Time, latencies []*common.Latency) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, latency := range latencies {
+		name := latency.GetName()
+		if name == "" {
+			continue
+		}
+		s, ok := h.series[name]
+		if !ok {
+			s = &series{}
+			h.series[name] = s
+		}
+
+		lastTry := latency.GetLastTryTime()
+		if lastTry > 0 && lastTry == s.lastTry {
+			continue
+		}
+		s.lastTry = lastTry
+
+		sampledAt := at
+		if lastTry > 0 {
+			sampledAt = time.Unix(lastTry, 0)
+		}
+		s.samples = append(s.samples, sample{at: sampledAt, alive: latency.GetAlive(), delay: latency.GetDelay()})
+		if len(s.samples) > maxSamplesPerName {
+			s.samples = s.samples[len(s.samples)-maxSamplesPerName:]
+		}
+	}
+
+	h.evictLocked(at)
+}
+
+// evictLocked drops samples older than the retention and names left without any.
+func (h *History) evictLocked(now time.Time) {
+	cutoff := now.Add(-h.retention)
+	for name, s := range h.series {
+		keep := sort.Search(len(s.samples), func(i int) bool {
+			return s.samples[i].at.After(cutoff)
+		})
+		if keep == len(s.samples) {
+			delete(h.series, name)
+			continue
+		}
+		s.samples = s.samples[keep:]
+	}
+}
+
+// Summarize fills the window statistics of each latency from the samples
+// recorded in the window ending at now.
+func (h *History) Summarize(now time.Time, window time.Duration, latencies []*common.Latency) {
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	start := now.Add(-window)
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, latency := range latencies {
+		latency.Window = uint32(window / time.Second)
+		s, ok := h.series[latency.GetName()]
+		if !ok {
+			continue
+		}
+		var inWindow []sample
+		for _, sample := range s.samples {
+			if sample.at.Before(start) || sample.at.After(now) {
+				continue
+			}
+			inWindow = append(inWindow, sample)
+		}
+		summarize(latency, inWindow)
+	}
+}
+
+// summarize computes delay statistics over the successful probes and the loss
+// over all of them. Jitter is the mean difference between consecutive delays.
+func summarize(latency *common.Latency, samples []sample) {
+	latency.Samples = uint32(len(samples))
+	if len(samples) == 0 {
+		return
+	}
+
+	delays := make([]int64, 0, len(samples))
+	var jitterSum int64
+	for _, sample := range samples {
+		if !sample.alive {
+			continue
+		}
+		if len(delays) > 0 {
+			diff := sample.delay - delays[len(delays)-1]
+			if diff < 0 {
+				diff = -diff
+			}
+			jitterSum += diff
+		}
+		delays = append(delays, sample.delay)
+	}
+	latency.Loss = float64(len(samples)-len(delays)) * 100 / float64(len(samples))
+	if len(delays) == 0 {
+		return
+	}
+	if len(delays) > 1 {
+		latency.Jitter = jitterSum / int64(len(delays)-1)
+	}
+
+	var sum int64
+	for _, delay := range delays {
+		sum += delay
+	}
+	latency.AvgDelay = sum / int64(len(delays))
+
+	sort.Slice(delays, func(i, j int) bool { return delays[i] < delays[j] })
+	latency.MinDelay = delays[0]
+	latency.P95Delay = delays[int(math.Ceil(0.95*float64(len(delays))))-1]
+}
+
+// Query returns the recorded samples between start and end (inclusive) for
+// name, or for every name when it is empty. A zero start means "oldest
+// available", a zero end means now.
+func (h *History) Query(name string, start, end time.Time) *common.LatencyHistoryResponse {
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	names := make([]string, 0, len(h.series))
+	for key := range h.series {
+		if name != "" && key != name {
+			continue
+		}
+		names = append(names, key)
+	}
+	sort.Strings(names)
+
+	response := &common.LatencyHistoryResponse{Series: make([]*common.LatencySeries, 0, len(names))}
+	for _, key := range names {
+		samples := make([]*common.LatencySample, 0)
+		for _, sample := range h.series[key].samples {
+			if sample.at.Before(start) || sample.at.After(end) {
+				continue
+			}
+			samples = append(samples, &common.LatencySample{
+				Timestamp: sample.at.Unix(),
+				Alive:     sample.alive,
+				Delay:     sample.delay,
+			})
+		}
+		response.Series = append(response.Series, &common.LatencySeries{Name: key, Samples: samples})
+	}
+	return response
+}
@@ -0,0 +1,106 @@
+package latency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pasarguard/node/common"
+)
+
+func TestHistory_SummarizesWindow(t *testing.T) {
+	history := NewHistory(time.Hour)
+	base := time.Unix(1_700_000_000, 0)
+
+	probes := []struct {
+		alive bool
+		delay int64
+	}{
+		{true, 100}, {true, 140}, {false, 0}, {true, 120}, {true, 300},
+	}
+	for i, probe := range probes {
+		at := base.Add(time.Duration(i) * 10 * time.Second)
+		history.Record(at, []*common.Latency{{Name: "proxy", Alive: probe.alive, Delay: probe.delay}})
+	}
+
+	latency := &common.Latency{Name: "proxy"}
+	history.Summarize(base.Add(time.Minute), 2*time.Minute, []*common.Latency{latency})
+
+	if latency.Window != 120 {
+		t.Errorf("Expected window 120, got %d", latency.Window)
+	}
+	if latency.Samples != 5 {
+		t.Errorf("Expected 5 samples, got %d", latency.Samples)
+	}
+	if latency.Loss != 20 {
+		t.Errorf("Expected loss 20, got %f", latency.Loss)
+	}
+	if latency.MinDelay != 100 || latency.AvgDelay != 165 || latency.P95Delay != 300 {
+		t.Errorf("Expected min/avg/p95 100/165/300, got %d/%d/%d", latency.MinDelay, latency.AvgDelay, latency.P95Delay)
+	}
+	// |140-100| + |120-140| + |300-120| = 240 over 3 intervals.
+	if latency.Jitter != 80 {
+		t.Errorf("Expected jitter 80, got %d", latency.Jitter)
+	}
+}
+
+func TestHistory_SummarizeLimitsToWindow(t *testing.T) {
+	history := NewHistory(time.Hour)
+	base := time.Unix(1_700_000_000, 0)
+
+	history.Record(base, []*common.Latency{{Name: "proxy", Alive: false}})
+	history.Record(base.Add(5*time.Minute), []*common.Latency{{Name: "proxy", Alive: true, Delay: 50}})
+
+	latency := &common.Latency{Name: "proxy"}
+	history.Summarize(base.Add(5*time.Minute), time.Minute, []*common.Latency{latency})
+	if latency.Samples != 1 || latency.Loss != 0 || latency.AvgDelay != 50 {
+		t.Errorf("Expected only the last probe in the window, got samples=%d loss=%f avg=%d", latency.Samples, latency.Loss, latency.AvgDelay)
+	}
+}
+
+func TestHistory_SkipsRepeatedProbe(t *testing.T) {
+	history := NewHistory(time.Hour)
+	base := time.Unix(1_700_000_000, 0)
+
+	entry := &common.Latency{Name: "proxy", Alive: true, Delay: 80, LastTryTime: base.Unix()}
+	history.Record(base, []*common.Latency{entry})
+	history.Record(base.Add(30*time.Second), []*common.Latency{entry})
+
+	resp := history.Query("", time.Time{}, base.Add(time.Minute))
+	if len(resp.Series) != 1 {
+		t.Fatalf("Expected 1 series, got %d", len(resp.Series))
+	}
+	if len(resp.Series[0].Samples) != 1 {
+		t.Fatalf("Expected the repeated probe to be recorded once, got %d", len(resp.Series[0].Samples))
+	}
+	if resp.Series[0].Samples[0].Timestamp != base.Unix() {
+		t.Errorf("Expected timestamp %d, got %d", base.Unix(), resp.Series[0].Samples[0].Timestamp)
+	}
+}
+
+func TestHistory_EvictsOldSamples(t *testing.T) {
+	history := NewHistory(time.Minute)
+	base := time.Unix(1_700_000_000, 0)
+
+	history.Record(base, []*common.Latency{{Name: "gone", Alive: true, Delay: 10}, {Name: "proxy", Alive: true, Delay: 10}})
+	history.Record(base.Add(2*time.Minute), []*common.Latency{{Name: "proxy", Alive: true, Delay: 20}})
+
+	resp := history.Query("", time.Time{}, base.Add(3*time.Minute))
+	if len(resp.Series) != 1 || resp.Series[0].Name != "proxy" {
+		t.Fatalf("Expected only the proxy series, got %v", resp.Series)
+	}
+	if len(resp.Series[0].Samples) != 1 || resp.Series[0].Samples[0].Delay != 20 {
+		t.Errorf("Expected only the newest sample, got %v", resp.Series[0].Samples)
+	}
+}
+
+func TestHistory_QueryFiltersByName(t *testing.T) {
+	history := NewHistory(time.Hour)
+	base := time.Unix(1_700_000_000, 0)
+
+	history.Record(base, []*common.Latency{{Name: "a", Alive: true}, {Name: "b", Alive: true}})
+
+	resp := history.Query("b", time.Time{}, base)
+	if len(resp.Series) != 1 || resp.Series[0].Name != "b" {
+		t.Fatalf("Expected only series b, got %v", resp.Series)
+	}
+}
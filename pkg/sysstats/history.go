@@ -0,0 +1,195 @@
+package sysstats
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pasarguard/node/common"
+)
+
+// HistoryTier describes one downsampling level of the history ring.
+// Samples are averaged into buckets of Resolution and kept for Retention.
+type HistoryTier struct {
+	Resolution time.Duration
+	Retention  time.Duration
+}
+
+// DefaultHistoryTiers keeps 1-second samples for 10 minutes and 1-minute samples for 24 hours.
+var DefaultHistoryTiers = []HistoryTier{
+	{Resolution: time.Second, Retention: 10 * time.Minute},
+	{Resolution: time.Minute, Retention: 24 * time.Hour},
+}
+
+type historyBucket struct {
+	start    time.Time
+	count    uint64
+	cpuUsage float64
+	memUsed  uint64
+	memTotal uint64
+	incoming uint64
+	outgoing uint64
+}
+
+func (b *historyBucket) add(stats *common.SystemStatsResponse) {
+	b.count++
+	b.cpuUsage += stats.GetCpuUsage()
+	b.memUsed += stats.GetMemUsed()
+	b.memTotal += stats.GetMemTotal()
+	b.incoming += stats.GetIncomingBandwidthSpeed()
+	b.outgoing += stats.GetOutgoingBandwidthSpeed()
+}
+
+func (b *historyBucket) sample() *common.SystemStatsSample {
+	if b.count == 0 {
+		return &common.SystemStatsSample{Timestamp: b.start.Unix()}
+	}
+	return &common.SystemStatsSample{
+		Timestamp:              b.start.Unix(),
+		CpuUsage:               b.cpuUsage / float64(b.count),
+		MemUsed:                b.memUsed / b.count,
+		MemTotal:               b.memTotal / b.count,
+		IncomingBandwidthSpeed: b.incoming / b.count,
+		OutgoingBandwidthSpeed: b.outgoing / b.count,
+	}
+}
+
+// historyRing is a fixed-capacity ring of buckets ordered from oldest to newest.
+type historyRing struct {
+	resolution time.Duration
+	retention  time.Duration
+	buckets    []historyBucket
+	head       int // index of the oldest bucket
+	size       int
+	evicted    bool
+}
+
+func newHistoryRing(tier HistoryTier) *historyRing {
+	capacity := int(tier.Retention / tier.Resolution)
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &historyRing{
+		resolution: tier.Resolution,
+		retention:  tier.Retention,
+		buckets:    make([]historyBucket, capacity),
+	}
+}
+
+func (r *historyRing) at(i int) *historyBucket {
+	return &r.buckets[(r.head+i)%len(r.buckets)]
+}
+
+func (r *historyRing) add(at time.Time, stats *common.SystemStatsResponse) {
+	start := at.Truncate(r.resolution)
+
+	if r.size > 0 {
+		last := r.at(r.size - 1)
+		if last.start.Equal(start) {
+			last.add(stats)
+			return
+		}
+		if start.Before(last.start) {
+			// Clock went backwards; fold the sample into the newest bucket instead of reordering.
+			last.add(stats)
+			return
+		}
+	}
+
+	// Buckets are evicted by age as well as by capacity, because the sampling
+	// interval does not have to match the tier resolution.
+	cutoff := start.Add(-r.retention)
+	for r.size > 0 && (r.size == len(r.buckets) || !r.at(0).start.After(cutoff)) {
+		r.head = (r.head + 1) % len(r.buckets)
+		r.size--
+		r.evicted = true
+	}
+
+	bucket := r.at(r.size)
+	*bucket = historyBucket{start: start}
+	bucket.add(stats)
+	r.size++
+}
+
+// covers reports whether the ring still holds every sample recorded since start.
+func (r *historyRing) covers(start time.Time) bool {
+	if r.size == 0 {
+		return false
+	}
+	if !r.evicted {
+		return true
+	}
+	return !start.IsZero() && !r.at(0).start.After(start.Truncate(r.resolution))
+}
+
+func (r *historyRing) samples(start, end time.Time) []*common.SystemStatsSample {
+	samples := make([]*common.SystemStatsSample, 0)
+	for i := 0; i < r.size; i++ {
+		bucket := r.at(i)
+		if bucket.start.Before(start.Truncate(r.resolution)) || bucket.start.After(end) {
+			continue
+		}
+		samples = append(samples, bucket.sample())
+	}
+	return samples
+}
+
+// History keeps downsampled system stats in memory so a panel can chart
+// what happened while it was disconnected.
+type History struct {
+	mu    sync.RWMutex
+	rings []*historyRing
+}
+
+// NewHistory creates a history with the given tiers, ordered from finest to coarsest.
+func NewHistory(tiers []HistoryTier) *History {
+	h := &History{rings: make([]*historyRing, 0, len(tiers))}
+	for _, tier := range tiers {
+		if tier.Resolution <= 0 || tier.Retention <= 0 {
+			continue
+		}
+		h.rings = append(h.rings, newHistoryRing(tier))
+	}
+	return h
+}
+
+// Add records a snapshot taken at the given time into every tier.
+func (h *History) Add(at time.Time, stats *common.SystemStatsResponse) {
+	if stats == nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, ring := range h.rings {
+		ring.add(at, stats)
+	}
+}
+
+// Query returns samples between start and end (inclusive) from the finest tier
+// that still covers start, falling back to the coarsest tier.
+// A zero start means "oldest available", a zero end means now.
+func (h *History) Query(start, end time.Time) *common.SystemStatsHistoryResponse {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	response := &common.SystemStatsHistoryResponse{Samples: []*common.SystemStatsSample{}}
+	if len(h.rings) == 0 {
+		return response
+	}
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	ring := h.rings[len(h.rings)-1]
+	for _, candidate := range h.rings {
+		if candidate.covers(start) {
+			ring = candidate
+			break
+		}
+	}
+
+	response.Resolution = uint32(ring.resolution / time.Second)
+	response.Samples = ring.samples(start, end)
+	return response
+}
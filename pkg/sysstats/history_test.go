@@ -0,0 +1,112 @@
+package sysstats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pasarguard/node/common"
+)
+
+func TestHistory_AveragesSamplesIntoBuckets(t *testing.T) {
+	history := NewHistory([]HistoryTier{{Resolution: time.Minute, Retention: time.Hour}})
+	base := time.Unix(1_700_000_040, 0)
+
+	history.Add(base, &common.SystemStatsResponse{CpuUsage: 10, MemUsed: 100, IncomingBandwidthSpeed: 1000})
+	history.Add(base.Add(10*time.Second), &common.SystemStatsResponse{CpuUsage: 30, MemUsed: 300, IncomingBandwidthSpeed: 3000})
+
+	resp := history.Query(time.Time{}, base.Add(time.Minute))
+	if resp.Resolution != 60 {
+		t.Fatalf("Expected resolution 60, got %d", resp.Resolution)
+	}
+	if len(resp.Samples) != 1 {
+		t.Fatalf("Expected 1 sample, got %d", len(resp.Samples))
+	}
+
+	sample := resp.Samples[0]
+	if sample.CpuUsage != 20 {
+		t.Errorf("Expected cpu 20, got %f", sample.CpuUsage)
+	}
+	if sample.MemUsed != 200 {
+		t.Errorf("Expected mem 200, got %d", sample.MemUsed)
+	}
+	if sample.IncomingBandwidthSpeed != 2000 {
+		t.Errorf("Expected incoming 2000, got %d", sample.IncomingBandwidthSpeed)
+	}
+	if sample.Timestamp != base.Truncate(time.Minute).Unix() {
+		t.Errorf("Expected timestamp %d, got %d", base.Truncate(time.Minute).Unix(), sample.Timestamp)
+	}
+}
+
+func TestHistory_EvictsOldBuckets(t *testing.T) {
+	history := NewHistory([]HistoryTier{{Resolution: time.Second, Retention: 5 * time.Second}})
+	base := time.Unix(1_700_000_000, 0)
+
+	for i := 0; i < 10; i++ {
+		history.Add(base.Add(time.Duration(i)*time.Second), &common.SystemStatsResponse{CpuUsage: float64(i)})
+	}
+
+	resp := history.Query(time.Time{}, base.Add(time.Minute))
+	if len(resp.Samples) != 5 {
+		t.Fatalf("Expected 5 samples, got %d", len(resp.Samples))
+	}
+	if resp.Samples[0].CpuUsage != 5 {
+		t.Errorf("Expected oldest cpu 5, got %f", resp.Samples[0].CpuUsage)
+	}
+	if resp.Samples[4].CpuUsage != 9 {
+		t.Errorf("Expected newest cpu 9, got %f", resp.Samples[4].CpuUsage)
+	}
+}
+
+func TestHistory_EvictsByAgeAfterGap(t *testing.T) {
+	history := NewHistory([]HistoryTier{{Resolution: time.Second, Retention: 5 * time.Second}})
+	base := time.Unix(1_700_000_000, 0)
+
+	history.Add(base, &common.SystemStatsResponse{CpuUsage: 1})
+	history.Add(base.Add(time.Minute), &common.SystemStatsResponse{CpuUsage: 2})
+
+	resp := history.Query(time.Time{}, base.Add(2*time.Minute))
+	if len(resp.Samples) != 1 {
+		t.Fatalf("Expected 1 sample, got %d", len(resp.Samples))
+	}
+	if resp.Samples[0].CpuUsage != 2 {
+		t.Errorf("Expected cpu 2, got %f", resp.Samples[0].CpuUsage)
+	}
+}
+
+func TestHistory_QueryPicksFinestCoveringTier(t *testing.T) {
+	history := NewHistory([]HistoryTier{
+		{Resolution: time.Second, Retention: 10 * time.Second},
+		{Resolution: time.Minute, Retention: time.Hour},
+	})
+	base := time.Unix(1_699_999_980, 0)
+
+	for i := 0; i < 120; i++ {
+		history.Add(base.Add(time.Duration(i)*time.Second), &common.SystemStatsResponse{CpuUsage: 50})
+	}
+	end := base.Add(120 * time.Second)
+
+	recent := history.Query(base.Add(115*time.Second), end)
+	if recent.Resolution != 1 {
+		t.Fatalf("Expected fine resolution for recent range, got %d", recent.Resolution)
+	}
+	if len(recent.Samples) != 5 {
+		t.Errorf("Expected 5 fine samples, got %d", len(recent.Samples))
+	}
+
+	old := history.Query(base, end)
+	if old.Resolution != 60 {
+		t.Fatalf("Expected coarse resolution for old range, got %d", old.Resolution)
+	}
+	if len(old.Samples) != 2 {
+		t.Errorf("Expected 2 coarse samples, got %d", len(old.Samples))
+	}
+}
+
+func TestHistory_QueryEmpty(t *testing.T) {
+	history := NewHistory(DefaultHistoryTiers)
+
+	resp := history.Query(time.Time{}, time.Time{})
+	if len(resp.Samples) != 0 {
+		t.Fatalf("Expected no samples, got %d", len(resp.Samples))
+	}
+}
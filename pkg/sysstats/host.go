@@ -0,0 +1,109 @@
+package sysstats
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shirou/gopsutil/v4/disk"
+	"github.com/shirou/gopsutil/v4/load"
+
+	"github.com/pasarguard/node/common"
+)
+
+// HostStats holds the metrics that are cheap enough to read on demand.
+type HostStats struct {
+	Load                *common.LoadAverage
+	Disks               []*common.DiskUsage
+	OpenFileDescriptors uint64
+	MaxFileDescriptors  uint64
+	Sockets             []*common.SocketStats
+}
+
+// GetHostStats reads load average, disk usage of the given paths, file
+// descriptor usage and socket counts. Each metric is best effort so a
+// missing source does not hide the others.
+func GetHostStats(paths []string) *HostStats {
+	stats := &HostStats{
+		Load:    &common.LoadAverage{},
+		Disks:   getDiskUsage(paths),
+		Sockets: getSocketStats(),
+	}
+
+	if avg, err := load.Avg(); err == nil {
+		stats.Load = &common.LoadAverage{Load1: avg.Load1, Load5: avg.Load5, Load15: avg.Load15}
+	}
+
+	stats.OpenFileDescriptors, stats.MaxFileDescriptors = getFileDescriptors()
+
+	return stats
+}
+
+func getDiskUsage(paths []string) []*common.DiskUsage {
+	partitions, _ := disk.Partitions(false)
+
+	seen := make(map[string]struct{}, len(paths))
+	usages := make([]*common.DiskUsage, 0, len(paths))
+	for _, path := range paths {
+		path = existingPath(path)
+		if path == "" {
+			continue
+		}
+		if _, ok := seen[path]; ok {
+			continue
+		}
+		seen[path] = struct{}{}
+
+		usage, err := disk.Usage(path)
+		if err != nil {
+			continue
+		}
+		usages = append(usages, &common.DiskUsage{
+			Path:        path,
+			Mountpoint:  mountpointOf(path, partitions),
+			Fstype:      usage.Fstype,
+			Total:       usage.Total,
+			Used:        usage.Used,
+			Free:        usage.Free,
+			UsedPercent: usage.UsedPercent,
+		})
+	}
+
+	return usages
+}
+
+// existingPath walks up from path until it finds something that exists,
+// so a log file that has not been created yet still reports its volume.
+func existingPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return ""
+	}
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return ""
+		}
+		path = parent
+	}
+}
+
+func mountpointOf(path string, partitions []disk.PartitionStat) string {
+	best := ""
+	for _, partition := range partitions {
+		mount := partition.Mountpoint
+		if mount != "/" && path != mount && !strings.HasPrefix(path, mount+string(filepath.Separator)) {
+			continue
+		}
+		if len(mount) > len(best) {
+			best = mount
+		}
+	}
+	return best
+}
@@ -0,0 +1,103 @@
+//go:build linux
+
+package sysstats
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pasarguard/node/common"
+)
+
+// tcpStates maps the hex state column of /proc/net/tcp to its name.
+var tcpStates = map[string]string{
+	"01": "ESTABLISHED",
+	"02": "SYN_SENT",
+	"03": "SYN_RECV",
+	"04": "FIN_WAIT1",
+	"05": "FIN_WAIT2",
+	"06": "TIME_WAIT",
+	"07": "CLOSE",
+	"08": "CLOSE_WAIT",
+	"09": "LAST_ACK",
+	"0A": "LISTEN",
+	"0B": "CLOSING",
+	"0C": "NEW_SYN_RECV",
+}
+
+func getFileDescriptors() (open, max uint64) {
+	data, err := os.ReadFile("/proc/sys/fs/file-nr")
+	if err != nil {
+		return 0, 0
+	}
+	open, max, _ = parseFileNr(data)
+	return open, max
+}
+
+// parseFileNr parses "allocated unused max" from /proc/sys/fs/file-nr.
+func parseFileNr(data []byte) (open, max uint64, ok bool) {
+	fields := strings.Fields(string(data))
+	if len(fields) < 3 {
+		return 0, 0, false
+	}
+	allocated, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	unused, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	max, err = strconv.ParseUint(fields[2], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	if unused > allocated {
+		unused = allocated
+	}
+	return allocated - unused, max, true
+}
+
+func getSocketStats() []*common.SocketStats {
+	tcp := &common.SocketStats{Protocol: "tcp", States: map[string]uint64{}}
+	udp := &common.SocketStats{Protocol: "udp", States: map[string]uint64{}}
+
+	for _, file := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		if data, err := os.ReadFile(file); err == nil {
+			countSocketStates(data, tcp, tcpStates)
+		}
+	}
+	for _, file := range []string{"/proc/net/udp", "/proc/net/udp6"} {
+		if data, err := os.ReadFile(file); err == nil {
+			// UDP has no connection state; the kernel reports 07 for unconnected and 01 for connected sockets.
+			countSocketStates(data, udp, map[string]string{"01": "ESTABLISHED", "07": "UNCONN"})
+		}
+	}
+
+	return []*common.SocketStats{tcp, udp}
+}
+
+// countSocketStates adds each socket line of a /proc/net/{tcp,udp}[6] table to stats.
+func countSocketStates(data []byte, stats *common.SocketStats, names map[string]string) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	header := true
+	for scanner.Scan() {
+		if header {
+			header = false
+			continue
+		}
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+		state, ok := names[strings.ToUpper(fields[3])]
+		if !ok {
+			state = "UNKNOWN"
+		}
+		stats.States[state]++
+		stats.Total++
+	}
+}
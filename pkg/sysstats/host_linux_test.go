@@ -0,0 +1,44 @@
+//go:build linux
+
+package sysstats
+
+import (
+	"testing"
+
+	"github.com/pasarguard/node/common"
+)
+
+func TestParseFileNr(t *testing.T) {
+	open, max, ok := parseFileNr([]byte("2048\t48\t9223372036854775807\n"))
+	if !ok {
+		t.Fatal("expected file-nr to parse")
+	}
+	if open != 2000 {
+		t.Errorf("Expected 2000 open descriptors, got %d", open)
+	}
+	if max != 9223372036854775807 {
+		t.Errorf("Unexpected max descriptors: %d", max)
+	}
+
+	if _, _, ok := parseFileNr([]byte("garbage")); ok {
+		t.Fatal("expected malformed file-nr to fail")
+	}
+}
+
+func TestCountSocketStates(t *testing.T) {
+	const sample = "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n" +
+		"   0: 00000000:0016 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1 1 0 100 0 0 10 0\n" +
+		"   1: 0100007F:1F90 0100007F:C350 01 00000000:00000000 00:00000000 00000000     0        0 2 1 0 20 4 30 10 -1\n" +
+		"   2: 0100007F:1F90 0100007F:C351 01 00000000:00000000 00:00000000 00000000     0        0 3 1 0 20 4 30 10 -1\n" +
+		"   3: 0100007F:1F90 0100007F:C352 06 00000000:00000000 03:00000000 00000000     0        0 0 3 0\n"
+
+	stats := &common.SocketStats{Protocol: "tcp", States: map[string]uint64{}}
+	countSocketStates([]byte(sample), stats, tcpStates)
+
+	if stats.Total != 4 {
+		t.Fatalf("Expected 4 sockets, got %d", stats.Total)
+	}
+	if stats.States["LISTEN"] != 1 || stats.States["ESTABLISHED"] != 2 || stats.States["TIME_WAIT"] != 1 {
+		t.Errorf("Unexpected states: %v", stats.States)
+	}
+}
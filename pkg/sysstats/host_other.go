@@ -0,0 +1,13 @@
+//go:build !linux
+
+package sysstats
+
+import "github.com/pasarguard/node/common"
+
+func getFileDescriptors() (open, max uint64) {
+	return 0, 0
+}
+
+func getSocketStats() []*common.SocketStats {
+	return []*common.SocketStats{}
+}
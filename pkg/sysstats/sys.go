@@ -1,6 +1,9 @@
 package sysstats
 
 import (
+	"math"
+	"runtime"
+	"sync"
 	"time"
 
 	"github.com/shirou/gopsutil/v4/cpu"
@@ -10,56 +13,165 @@ import (
 	"github.com/pasarguard/node/common"
 )
 
-func GetSystemStats() (*common.SystemStatsResponse, error) {
+// Snapshot is one collection round: the aggregate stats plus the per-core
+// and per-interface breakdown they were computed from.
+type Snapshot struct {
+	Summary      *common.SystemStatsResponse
+	CpuCoreUsage []float64
+	Interfaces   []*common.InterfaceStats
+}
+
+// Sources are the raw counter readers used by a Collector.
+// They are swappable so tests can feed synthetic counters.
+type Sources struct {
+	CPUTimes      func() ([]cpu.TimesStat, error)
+	IOCounters    func() ([]net.IOCountersStat, error)
+	VirtualMemory func() (*mem.VirtualMemoryStat, error)
+	Now           func() time.Time
+}
+
+// DefaultSources reads per-core CPU times, per-interface counters and memory from the host.
+func DefaultSources() Sources {
+	return Sources{
+		CPUTimes:      func() ([]cpu.TimesStat, error) { return cpu.Times(true) },
+		IOCounters:    func() ([]net.IOCountersStat, error) { return net.IOCounters(true) },
+		VirtualMemory: mem.VirtualMemory,
+		Now:           time.Now,
+	}
+}
+
+// Collector computes CPU usage and bandwidth from the delta between the
+// counters read on consecutive calls, so collecting never sleeps.
+type Collector struct {
+	sources Sources
+
+	mu      sync.Mutex
+	prevCPU []cpu.TimesStat
+	prevNet []net.IOCountersStat
+	prevAt  time.Time
+}
+
+func NewCollector(sources Sources) *Collector {
+	return &Collector{sources: sources}
+}
+
+// Prime records the baseline counters without producing a snapshot.
+func (c *Collector) Prime() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cpuTimes, ioCounters, err := c.readCounters()
+	if err != nil {
+		return err
+	}
+	c.prevCPU, c.prevNet, c.prevAt = cpuTimes, ioCounters, c.sources.Now()
+	return nil
+}
+
+// Collect reads the current counters and returns rates relative to the
+// previous call. The first call without Prime reports zero rates.
+func (c *Collector) Collect() (*Snapshot, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	stats := &common.SystemStatsResponse{}
+	snapshot := &Snapshot{Summary: stats}
 
-	vm, err := mem.VirtualMemory()
+	vm, err := c.sources.VirtualMemory()
 	if err != nil {
-		return stats, err
+		return snapshot, err
 	}
 	stats.MemTotal = vm.Total
 	stats.MemUsed = vm.Used
 
-	cores, err := cpu.Counts(true)
+	cpuTimes, ioCounters, err := c.readCounters()
 	if err != nil {
-		return stats, err
+		return snapshot, err
 	}
-	stats.CpuCores = uint64(cores)
+	now := c.sources.Now()
 
-	percentages, err := cpu.Percent(time.Second, false)
-	if err != nil {
-		return stats, err
+	elapsed := time.Duration(0)
+	if !c.prevAt.IsZero() {
+		elapsed = now.Sub(c.prevAt)
 	}
-	if len(percentages) > 0 {
-		stats.CpuUsage = percentages[0]
+
+	stats.CpuCores = uint64(len(cpuTimes))
+	snapshot.CpuCoreUsage = coreUsage(c.prevCPU, cpuTimes)
+	stats.CpuUsage = averageUsage(snapshot.CpuCoreUsage)
+
+	snapshot.Interfaces = interfaceStatsFromCounters(c.prevNet, ioCounters, elapsed)
+	for _, iface := range snapshot.Interfaces {
+		stats.IncomingBandwidthSpeed += iface.GetRxSpeed()
+		stats.OutgoingBandwidthSpeed += iface.GetTxSpeed()
 	}
 
-	incomingSpeed, outgoingSpeed, err := getBandwidthSpeed()
+	c.prevCPU, c.prevNet, c.prevAt = cpuTimes, ioCounters, now
+	return snapshot, nil
+}
+
+func (c *Collector) readCounters() ([]cpu.TimesStat, []net.IOCountersStat, error) {
+	cpuTimes, err := c.sources.CPUTimes()
 	if err != nil {
-		return stats, err
+		return nil, nil, err
 	}
-	stats.IncomingBandwidthSpeed = incomingSpeed
-	stats.OutgoingBandwidthSpeed = outgoingSpeed
+	ioCounters, err := c.sources.IOCounters()
+	if err != nil {
+		return nil, nil, err
+	}
+	return cpuTimes, ioCounters, nil
+}
 
-	return stats, nil
+// coreUsage returns the busy percentage of each core between two readings.
+// Cores without a previous reading (first call, hotplug) report zero.
+func coreUsage(prev, cur []cpu.TimesStat) []float64 {
+	usage := make([]float64, len(cur))
+	if len(prev) != len(cur) {
+		return usage
+	}
+	for i := range cur {
+		usage[i] = busyPercent(prev[i], cur[i])
+	}
+	return usage
 }
 
-// getBandwidthSpeed returns the aggregate incoming (rx) and outgoing (tx)
-// bandwidth in bytes per second, sampled over a 1-second interval.
-// Loopback interface (lo) is excluded from the calculation.
-func getBandwidthSpeed() (uint64, uint64, error) {
-	first, err := net.IOCounters(true)
-	if err != nil {
-		return 0, 0, err
+func busyPercent(prev, cur cpu.TimesStat) float64 {
+	prevTotal, prevBusy := cpuTotalAndBusy(prev)
+	curTotal, curBusy := cpuTotalAndBusy(cur)
+
+	if curBusy <= prevBusy {
+		return 0
+	}
+	if curTotal <= prevTotal {
+		return 100
 	}
+	return math.Min(100, math.Max(0, (curBusy-prevBusy)/(curTotal-prevTotal)*100))
+}
 
-	time.Sleep(1 * time.Second)
+func cpuTotalAndBusy(t cpu.TimesStat) (float64, float64) {
+	total := t.Total()
+	if runtime.GOOS == "linux" {
+		// Guest time is already accounted in user and nice on Linux.
+		total -= t.Guest
+		total -= t.GuestNice
+	}
+	return total, total - t.Idle - t.Iowait
+}
 
-	second, err := net.IOCounters(true)
-	if err != nil {
-		return 0, 0, err
+func averageUsage(percentages []float64) float64 {
+	if len(percentages) == 0 {
+		return 0
+	}
+	var total float64
+	for _, p := range percentages {
+		total += p
 	}
+	return total / float64(len(percentages))
+}
 
+// interfaceStatsFromCounters returns counters and incoming (rx) / outgoing (tx)
+// bandwidth in bytes per second for every interface between two readings
+// taken elapsed apart. Loopback interface (lo) is excluded.
+func interfaceStatsFromCounters(first, second []net.IOCountersStat, elapsed time.Duration) []*common.InterfaceStats {
 	prev := make(map[string]net.IOCountersStat, len(first))
 	for _, c := range first {
 		if c.Name == "lo" {
@@ -68,16 +180,37 @@ func getBandwidthSpeed() (uint64, uint64, error) {
 		prev[c.Name] = c
 	}
 
-	var totalRxBytes, totalTxBytes uint64
+	interfaces := make([]*common.InterfaceStats, 0, len(second))
 	for _, c := range second {
 		if c.Name == "lo" {
 			continue
 		}
-		if p, ok := prev[c.Name]; ok {
-			totalRxBytes += c.BytesRecv - p.BytesRecv
-			totalTxBytes += c.BytesSent - p.BytesSent
+		iface := &common.InterfaceStats{
+			Name:      c.Name,
+			RxBytes:   c.BytesRecv,
+			TxBytes:   c.BytesSent,
+			RxPackets: c.PacketsRecv,
+			TxPackets: c.PacketsSent,
+			RxErrors:  c.Errin,
+			TxErrors:  c.Errout,
+			RxDropped: c.Dropin,
+			TxDropped: c.Dropout,
+		}
+		if p, ok := prev[c.Name]; ok && elapsed > 0 {
+			iface.RxSpeed = counterRate(p.BytesRecv, c.BytesRecv, elapsed)
+			iface.TxSpeed = counterRate(p.BytesSent, c.BytesSent, elapsed)
 		}
+		interfaces = append(interfaces, iface)
 	}
 
-	return totalRxBytes, totalTxBytes, nil
+	return interfaces
+}
+
+// counterRate returns the per-second rate between two counter readings.
+// A counter that went backwards (interface reset) reports zero.
+func counterRate(prev, cur uint64, elapsed time.Duration) uint64 {
+	if cur < prev {
+		return 0
+	}
+	return uint64(float64(cur-prev) / elapsed.Seconds())
 }
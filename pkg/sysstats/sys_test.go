@@ -0,0 +1,199 @@
+package sysstats
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/disk"
+	"github.com/shirou/gopsutil/v4/mem"
+	"github.com/shirou/gopsutil/v4/net"
+)
+
+func TestInterfaceStatsFromCounters(t *testing.T) {
+	first := []net.IOCountersStat{
+		{Name: "lo", BytesRecv: 10, BytesSent: 10},
+		{Name: "eth0", BytesRecv: 1000, BytesSent: 500},
+		{Name: "wg0", BytesRecv: 5000, BytesSent: 5000},
+	}
+	second := []net.IOCountersStat{
+		{Name: "lo", BytesRecv: 1000, BytesSent: 1000},
+		{Name: "eth0", BytesRecv: 3000, BytesSent: 1500, PacketsRecv: 7, Errin: 1},
+		{Name: "wg0", BytesRecv: 100, BytesSent: 100},
+		{Name: "eth1", BytesRecv: 42},
+	}
+
+	interfaces := interfaceStatsFromCounters(first, second, 2*time.Second)
+	if len(interfaces) != 3 {
+		t.Fatalf("Expected 3 interfaces without loopback, got %d", len(interfaces))
+	}
+
+	byName := make(map[string]int, len(interfaces))
+	for i, iface := range interfaces {
+		byName[iface.GetName()] = i
+	}
+
+	eth0 := interfaces[byName["eth0"]]
+	if eth0.GetRxSpeed() != 1000 || eth0.GetTxSpeed() != 500 {
+		t.Errorf("Unexpected eth0 rates: rx=%d tx=%d", eth0.GetRxSpeed(), eth0.GetTxSpeed())
+	}
+	if eth0.GetRxBytes() != 3000 || eth0.GetRxPackets() != 7 || eth0.GetRxErrors() != 1 {
+		t.Errorf("Unexpected eth0 counters: %v", eth0)
+	}
+
+	wg0 := interfaces[byName["wg0"]]
+	if wg0.GetRxSpeed() != 0 || wg0.GetTxSpeed() != 0 {
+		t.Errorf("Expected zero rate after counter reset, got rx=%d tx=%d", wg0.GetRxSpeed(), wg0.GetTxSpeed())
+	}
+
+	eth1 := interfaces[byName["eth1"]]
+	if eth1.GetRxSpeed() != 0 {
+		t.Errorf("Expected zero rate for new interface, got %d", eth1.GetRxSpeed())
+	}
+}
+
+func TestAverageUsage(t *testing.T) {
+	if got := averageUsage([]float64{10, 20, 60}); got != 30 {
+		t.Errorf("Expected 30, got %f", got)
+	}
+	if got := averageUsage(nil); got != 0 {
+		t.Errorf("Expected 0 for no cores, got %f", got)
+	}
+}
+
+func TestMountpointOf(t *testing.T) {
+	partitions := []disk.PartitionStat{
+		{Mountpoint: "/"},
+		{Mountpoint: "/var"},
+		{Mountpoint: "/var/lib"},
+		{Mountpoint: "/var/li"},
+	}
+
+	if got := mountpointOf("/var/lib/pg-node/generated", partitions); got != "/var/lib" {
+		t.Errorf("Expected /var/lib, got %s", got)
+	}
+	if got := mountpointOf("/var/log/xray/access.log", partitions); got != "/var" {
+		t.Errorf("Expected /var, got %s", got)
+	}
+	if got := mountpointOf("/etc", partitions); got != "/" {
+		t.Errorf("Expected /, got %s", got)
+	}
+}
+
+type fakeSources struct {
+	cpu []cpu.TimesStat
+	net []net.IOCountersStat
+	now time.Time
+}
+
+func (f *fakeSources) sources() Sources {
+	return Sources{
+		CPUTimes:      func() ([]cpu.TimesStat, error) { return f.cpu, nil },
+		IOCounters:    func() ([]net.IOCountersStat, error) { return f.net, nil },
+		VirtualMemory: func() (*mem.VirtualMemoryStat, error) { return &mem.VirtualMemoryStat{Total: 4096, Used: 1024}, nil },
+		Now:           func() time.Time { return f.now },
+	}
+}
+
+func TestCollector_ComputesRatesFromDeltas(t *testing.T) {
+	fake := &fakeSources{
+		cpu: []cpu.TimesStat{
+			{CPU: "cpu0", User: 10, Idle: 90},
+			{CPU: "cpu1", User: 50, Idle: 50},
+		},
+		net: []net.IOCountersStat{{Name: "eth0", BytesRecv: 1000, BytesSent: 1000}},
+		now: time.Unix(1_700_000_000, 0),
+	}
+	collector := NewCollector(fake.sources())
+	if err := collector.Prime(); err != nil {
+		t.Fatalf("prime failed: %v", err)
+	}
+
+	fake.cpu = []cpu.TimesStat{
+		{CPU: "cpu0", User: 15, Idle: 95},
+		{CPU: "cpu1", User: 60, Idle: 50},
+	}
+	fake.net = []net.IOCountersStat{{Name: "eth0", BytesRecv: 5000, BytesSent: 3000}}
+	fake.now = fake.now.Add(2 * time.Second)
+
+	snapshot, err := collector.Collect()
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	stats := snapshot.Summary
+	if stats.GetCpuCores() != 2 {
+		t.Errorf("Expected 2 cores, got %d", stats.GetCpuCores())
+	}
+	if snapshot.CpuCoreUsage[0] != 50 || snapshot.CpuCoreUsage[1] != 100 {
+		t.Errorf("Unexpected per-core usage: %v", snapshot.CpuCoreUsage)
+	}
+	if stats.GetCpuUsage() != 75 {
+		t.Errorf("Expected aggregate cpu 75, got %f", stats.GetCpuUsage())
+	}
+	if stats.GetIncomingBandwidthSpeed() != 2000 || stats.GetOutgoingBandwidthSpeed() != 1000 {
+		t.Errorf("Unexpected bandwidth: in=%d out=%d", stats.GetIncomingBandwidthSpeed(), stats.GetOutgoingBandwidthSpeed())
+	}
+	if stats.GetMemTotal() != 4096 || stats.GetMemUsed() != 1024 {
+		t.Errorf("Unexpected memory: total=%d used=%d", stats.GetMemTotal(), stats.GetMemUsed())
+	}
+}
+
+func TestCollector_FirstCollectWithoutPrimeReportsZeroRates(t *testing.T) {
+	fake := &fakeSources{
+		cpu: []cpu.TimesStat{{CPU: "cpu0", User: 10, Idle: 90}},
+		net: []net.IOCountersStat{{Name: "eth0", BytesRecv: 1000}},
+		now: time.Unix(1_700_000_000, 0),
+	}
+	collector := NewCollector(fake.sources())
+
+	snapshot, err := collector.Collect()
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+	if snapshot.Summary.GetCpuUsage() != 0 || snapshot.Summary.GetIncomingBandwidthSpeed() != 0 {
+		t.Errorf("Expected zero rates on first collect, got cpu=%f in=%d",
+			snapshot.Summary.GetCpuUsage(), snapshot.Summary.GetIncomingBandwidthSpeed())
+	}
+	if len(snapshot.Interfaces) != 1 || snapshot.Interfaces[0].GetRxBytes() != 1000 {
+		t.Errorf("Expected raw counters on first collect, got %v", snapshot.Interfaces)
+	}
+}
+
+func TestCollector_SourceErrorKeepsBaseline(t *testing.T) {
+	fake := &fakeSources{
+		cpu: []cpu.TimesStat{{CPU: "cpu0", User: 10, Idle: 90}},
+		net: []net.IOCountersStat{{Name: "eth0", BytesRecv: 1000}},
+		now: time.Unix(1_700_000_000, 0),
+	}
+	sources := fake.sources()
+	failNet := false
+	sources.IOCounters = func() ([]net.IOCountersStat, error) {
+		if failNet {
+			return nil, errors.New("boom")
+		}
+		return fake.net, nil
+	}
+	collector := NewCollector(sources)
+	if err := collector.Prime(); err != nil {
+		t.Fatalf("prime failed: %v", err)
+	}
+
+	failNet = true
+	fake.now = fake.now.Add(time.Second)
+	if _, err := collector.Collect(); err == nil {
+		t.Fatal("expected collect error")
+	}
+
+	failNet = false
+	fake.net = []net.IOCountersStat{{Name: "eth0", BytesRecv: 3000}}
+	fake.now = fake.now.Add(time.Second)
+	snapshot, err := collector.Collect()
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+	if snapshot.Summary.GetIncomingBandwidthSpeed() != 1000 {
+		t.Errorf("Expected rate over both intervals (1000), got %d", snapshot.Summary.GetIncomingBandwidthSpeed())
+	}
+}